@@ -53,6 +53,7 @@ const (
 	OnTargetBranch         = pipelinesascode.GroupName + "/on-target-branch"
 	OnPathChange           = pipelinesascode.GroupName + "/on-path-change"
 	OnLabel                = pipelinesascode.GroupName + "/on-label"
+	OnDraft                = pipelinesascode.GroupName + "/on-draft"
 	OnPathChangeIgnore     = pipelinesascode.GroupName + "/on-path-change-ignore"
 	OnCelExpression        = pipelinesascode.GroupName + "/on-cel-expression"
 	TargetNamespace        = pipelinesascode.GroupName + "/target-namespace"
@@ -61,11 +62,25 @@ const (
 	LogURL                 = pipelinesascode.GroupName + "/log-url"
 	ExecutionOrder         = pipelinesascode.GroupName + "/execution-order"
 	SCMReportingPLRStarted = pipelinesascode.GroupName + "/scm-reporting-plr-started"
+	HeldPullRequests       = pipelinesascode.GroupName + "/held-pull-requests"
+	Priority               = pipelinesascode.GroupName + "/priority"
+	ConcurrencyGroup       = pipelinesascode.GroupName + "/concurrency-group"
+	ConcurrencyGroupCEL    = pipelinesascode.GroupName + "/concurrency-group-cel-expression"
+	ConcurrencyGroupLimit  = pipelinesascode.GroupName + "/concurrency-group-limit"
+	QueuePromote           = pipelinesascode.GroupName + "/queue-promote"
+	MaxRetries             = pipelinesascode.GroupName + "/max-retries"
+	RetryCELExpression     = pipelinesascode.GroupName + "/retry-on-cel-expression"
+	RetryAttempt           = pipelinesascode.GroupName + "/retry-attempt"
 	// PublicGithubAPIURL default is "https://api.github.com" but it can be overridden by X-GitHub-Enterprise-Host header.
 	PublicGithubAPIURL   = "https://api.github.com"
 	GithubApplicationID  = "github-application-id"
 	GithubPrivateKey     = "github-private-key"
 	ResultsRecordSummary = "results.tekton.dev/recordSummaryAnnotations"
+	// ResultsResult, ResultsRecord and ResultsLog are set by the Tekton
+	// Results watcher on the PipelineRuns and TaskRuns it has stored.
+	ResultsResult = "results.tekton.dev/result"
+	ResultsRecord = "results.tekton.dev/record"
+	ResultsLog    = "results.tekton.dev/log"
 )
 
 var ParamsRe = regexp.MustCompile(`{{([^}]{2,})}}`)
@@ -66,6 +66,11 @@ type RepositoryRunStatus struct {
 
 	// CollectedTaskInfos is the information about tasks
 	CollectedTaskInfos *map[string]TaskInfos `json:"failure_reason,omitempty"`
+
+	// Attempt is the attempt of that run when the PipelineRun is retried
+	// automatically, starting at 1 for the first run
+	// +optional
+	Attempt int `json:"attempt,omitempty"`
 }
 
 // TaskInfos contains information about a task.
@@ -150,6 +155,23 @@ type Settings struct {
 	// +kubebuilder:validation:Enum=source;default_branch
 	PipelineRunProvenance string `json:"pipelinerun_provenance,omitempty"`
 
+	// DefaultPriority is the priority of the PipelineRuns of this repository without a
+	// pipelinesascode.tekton.dev/priority annotation. When a concurrency limit is set, the
+	// queued PipelineRuns with a higher priority are started first.
+	// +optional
+	DefaultPriority *int `json:"default_priority,omitempty"`
+
+	// Retention defines how long the completed PipelineRuns of this repository are kept
+	// before the watcher deletes them, on top of the max-keep-runs annotation.
+	// +optional
+	Retention *Retention `json:"retention,omitempty"`
+
+	// MatchReportComment posts on the pull requests a collapsible comment explaining for
+	// each PipelineRun which annotations have matched the event or not. The comment is
+	// updated on every event of the pull request.
+	// +optional
+	MatchReportComment *bool `json:"match_report_comment,omitempty"`
+
 	// Policy defines authorization policies for the repository, controlling who can
 	// trigger PipelineRuns under different conditions.
 	// +optional
@@ -160,6 +182,10 @@ type Settings struct {
 	Gitlab *GitlabSettings `json:"gitlab,omitempty"`
 
 	Github *GithubSettings `json:"github,omitempty"`
+
+	// Bitbucket contains settings for repositories hosted on Bitbucket Cloud or Bitbucket Data Center.
+	// +optional
+	Bitbucket *BitbucketSettings `json:"bitbucket,omitempty"`
 }
 
 type GitlabSettings struct {
@@ -180,10 +206,28 @@ type GithubSettings struct {
 	CommentStrategy string `json:"comment_strategy,omitempty"`
 }
 
+type BitbucketSettings struct {
+	// CommentStrategy defines how Bitbucket comments are handled for pipeline results.
+	// Options:
+	// - 'disable_all': Disables all comments on pull requests
+	// +optional
+	// +kubebuilder:validation:Enum="";disable_all
+	CommentStrategy string `json:"comment_strategy,omitempty"`
+}
+
 func (s *Settings) Merge(newSettings *Settings) {
 	if newSettings.PipelineRunProvenance != "" && s.PipelineRunProvenance == "" {
 		s.PipelineRunProvenance = newSettings.PipelineRunProvenance
 	}
+	if newSettings.DefaultPriority != nil && s.DefaultPriority == nil {
+		s.DefaultPriority = newSettings.DefaultPriority
+	}
+	if newSettings.Retention != nil && s.Retention == nil {
+		s.Retention = newSettings.Retention
+	}
+	if newSettings.MatchReportComment != nil && s.MatchReportComment == nil {
+		s.MatchReportComment = newSettings.MatchReportComment
+	}
 	if newSettings.Policy != nil && s.Policy == nil {
 		s.Policy = newSettings.Policy
 	}
@@ -192,6 +236,26 @@ func (s *Settings) Merge(newSettings *Settings) {
 	}
 }
 
+type Retention struct {
+	// Successful is how long the successful PipelineRuns are kept after they completed,
+	// as a duration like "48h" or a number of days like "2d". They are kept forever when
+	// empty.
+	// +optional
+	Successful string `json:"successful,omitempty"`
+
+	// Failed is how long the failed and cancelled PipelineRuns are kept after they
+	// completed, as a duration like "48h" or a number of days like "14d". They are kept
+	// forever when empty.
+	// +optional
+	Failed string `json:"failed,omitempty"`
+
+	// KeepLast is the number of the most recent completed PipelineRuns of every branch
+	// which are always kept, whatever their age.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	KeepLast *int `json:"keep_last,omitempty"`
+}
+
 type Policy struct {
 	// OkToTest defines a list of usernames that are allowed to trigger pipeline runs on pull requests
 	// from external contributors by commenting "/ok-to-test" on the PR. These users are typically
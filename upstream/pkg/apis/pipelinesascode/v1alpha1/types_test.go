@@ -8,6 +8,10 @@ import (
 
 func TestMergeSpecs(t *testing.T) {
 	two := 2
+	ten := 10
+	enabled, disabled := true, false
+	localRetention := &Retention{Successful: "2d"}
+	globalRetention := &Retention{Failed: "14d", KeepLast: &two}
 	incomings := &[]Incoming{{
 		Type: "type",
 		Secret: Secret{
@@ -55,6 +59,9 @@ func TestMergeSpecs(t *testing.T) {
 				Settings: &Settings{
 					GithubAppTokenScopeRepos: []string{"repo1", "repo2"},
 					PipelineRunProvenance:    "provenance",
+					DefaultPriority:          &ten,
+					Retention:                globalRetention,
+					MatchReportComment:       &enabled,
 					Policy: &Policy{
 						OkToTest: []string{"ok1", "ok2"},
 					},
@@ -68,6 +75,9 @@ func TestMergeSpecs(t *testing.T) {
 				Settings: &Settings{
 					GithubAppTokenScopeRepos: []string{"repo1", "repo2"},
 					PipelineRunProvenance:    "provenance",
+					DefaultPriority:          &ten,
+					Retention:                globalRetention,
+					MatchReportComment:       &enabled,
 					Policy: &Policy{
 						OkToTest: []string{"ok1", "ok2"},
 					},
@@ -84,6 +94,9 @@ func TestMergeSpecs(t *testing.T) {
 				Settings: &Settings{
 					GithubAppTokenScopeRepos: []string{"repo1", "repo2"},
 					PipelineRunProvenance:    "provenance",
+					DefaultPriority:          &two,
+					Retention:                localRetention,
+					MatchReportComment:       &disabled,
 					Policy: &Policy{
 						OkToTest: []string{"ok1", "ok2"},
 					},
@@ -94,6 +107,9 @@ func TestMergeSpecs(t *testing.T) {
 				Settings: &Settings{
 					GithubAppTokenScopeRepos: []string{"hello", "moto"},
 					PipelineRunProvenance:    "somewhere",
+					DefaultPriority:          &ten,
+					Retention:                globalRetention,
+					MatchReportComment:       &enabled,
 					Policy: &Policy{
 						OkToTest: []string{"to", "be"},
 					},
@@ -107,6 +123,9 @@ func TestMergeSpecs(t *testing.T) {
 				Settings: &Settings{
 					GithubAppTokenScopeRepos: []string{"repo1", "repo2"},
 					PipelineRunProvenance:    "provenance",
+					DefaultPriority:          &two,
+					Retention:                localRetention,
+					MatchReportComment:       &disabled,
 					Policy: &Policy{
 						OkToTest: []string{"ok1", "ok2"},
 					},
@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	KindPipelineRun = "PipelineRun"
+	KindTaskRun     = "TaskRun"
+	KindLog         = "Log"
+
+	// credentials keys in the archive-secret Secret.
+	AccessKeyIDKey     = "access-key-id"
+	SecretAccessKeyKey = "secret-access-key" //nolint: gosec
+	TokenKey           = "token"
+
+	// requestTimeout is the longest a request to a sink can take and
+	// storeTimeout the longest archiving a PipelineRun can take, so a hanging
+	// sink does not block the cleanup of the PipelineRuns.
+	requestTimeout = 30 * time.Second
+	storeTimeout   = 5 * time.Minute
+)
+
+// httpClient is the client of the sinks talking to an API.
+var httpClient = &http.Client{Timeout: requestTimeout}
+
+// File is a document of an archived PipelineRun.
+type File struct {
+	// Name is the path of the file in the archive of the PipelineRun, for
+	// example taskruns/build.yaml or logs/build/step-compile.log.
+	Name    string
+	Kind    string
+	Content []byte
+}
+
+// Record is an archived PipelineRun with its TaskRuns and the logs of their
+// steps, the secrets have been redacted from all its files.
+type Record struct {
+	Namespace   string
+	Repository  string
+	PipelineRun string
+	UID         string
+	Files       []File
+}
+
+// Path is where the PipelineRun is archived in a sink.
+func (r *Record) Path() string {
+	return path.Join(r.Namespace, r.Repository, r.PipelineRun)
+}
+
+// Sink stores the archived PipelineRuns.
+type Sink interface {
+	Store(ctx context.Context, record *Record) error
+}
+
+// CredentialsFunc returns a value of the archive-secret Secret.
+type CredentialsFunc func(ctx context.Context, key string) (string, error)
+
+// NewSink returns the sink of an archive-url: a directory with file://, for
+// example on a PVC, an S3 compatible bucket with s3:// or a Tekton Results
+// API with results+http:// or results+https://.
+func NewSink(ctx context.Context, rawURL string, credentials CredentialsFunc) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive URL %q: %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		return &directorySink{dir: u.Path}, nil
+	case "s3":
+		return newS3Sink(ctx, u, credentials)
+	case "results+http", "results+https":
+		return newResultsSink(ctx, u, credentials)
+	}
+	return nil, fmt.Errorf("unsupported archive URL %q, must start with file://, s3://, results+http:// or results+https://", rawURL)
+}
+
+// optionalCredential returns a value of the archive-secret Secret, it is
+// empty when there is no Secret.
+func optionalCredential(ctx context.Context, credentials CredentialsFunc, key string) string {
+	if credentials == nil {
+		return ""
+	}
+	value, err := credentials(ctx, key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
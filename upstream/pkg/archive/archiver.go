@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Archiver archives the PipelineRuns deleted by the cleanups to the sink of
+// the archive-url setting, it does nothing when the setting is empty.
+type Archiver struct {
+	run       *params.Run
+	kinteract kubeinteraction.Interface
+}
+
+var _ kubeinteraction.Archiver = (*Archiver)(nil)
+
+func NewArchiver(run *params.Run, kinteract kubeinteraction.Interface) *Archiver {
+	return &Archiver{run: run, kinteract: kinteract}
+}
+
+func (a *Archiver) Archive(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) error {
+	if a.run.Info.Pac == nil {
+		return nil
+	}
+	pacOpts := a.run.Info.GetPacOpts()
+	if pacOpts.ArchiveURL == "" {
+		return nil
+	}
+	sink, err := NewSink(ctx, pacOpts.ArchiveURL, a.credentials(pacOpts.ArchiveSecret))
+	if err != nil {
+		return err
+	}
+	record, err := a.newRecord(ctx, logger, pr)
+	if err != nil {
+		return err
+	}
+	storeCtx, cancel := context.WithTimeout(ctx, storeTimeout)
+	defer cancel()
+	if err := sink.Store(storeCtx, record); err != nil {
+		return err
+	}
+	logger.Infof("archived PipelineRun %s/%s with %d files", pr.GetNamespace(), pr.GetName(), len(record.Files))
+	return nil
+}
+
+// credentials reads the credentials of the sink from the archive secret in
+// the namespace of the installation.
+func (a *Archiver) credentials(secretName string) CredentialsFunc {
+	return func(ctx context.Context, key string) (string, error) {
+		if secretName == "" || a.run.Info.Kube == nil {
+			return "", fmt.Errorf("no archive secret has been configured")
+		}
+		return a.kinteract.GetSecret(ctx, ktypes.GetSecretOpt{
+			Namespace: a.run.Info.Kube.Namespace,
+			Name:      secretName,
+			Key:       key,
+		})
+	}
+}
+
+// newRecord collects the PipelineRun, its TaskRuns and the logs of their
+// steps, with the values of the secrets attached to the PipelineRun replaced.
+// The logs which cannot be fetched anymore, for example when the pod has been
+// deleted, are skipped.
+func (a *Archiver) newRecord(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) (*Record, error) {
+	secretValues := secrets.GetSecretsAttachedToPipelineRun(ctx, a.kinteract, pr)
+	redact := func(content []byte) []byte {
+		return []byte(secrets.ReplaceSecretsInText(string(content), secretValues))
+	}
+	record := &Record{
+		Namespace:   pr.GetNamespace(),
+		Repository:  pr.GetAnnotations()[keys.Repository],
+		PipelineRun: pr.GetName(),
+		UID:         string(pr.GetUID()),
+	}
+
+	prCopy := pr.DeepCopy()
+	prCopy.ManagedFields = nil
+	prCopy.APIVersion, prCopy.Kind = tektonv1.SchemeGroupVersion.String(), KindPipelineRun
+	content, err := yaml.Marshal(prCopy)
+	if err != nil {
+		return nil, err
+	}
+	record.Files = append(record.Files, File{Name: "pipelinerun.yaml", Kind: KindPipelineRun, Content: redact(content)})
+
+	for _, child := range pr.Status.ChildReferences {
+		if child.Kind != KindTaskRun {
+			continue
+		}
+		tr, err := a.run.Clients.Tekton.TektonV1().TaskRuns(pr.GetNamespace()).Get(ctx, child.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot get taskrun %s: %w", child.Name, err)
+		}
+		tr.ManagedFields = nil
+		tr.APIVersion, tr.Kind = tektonv1.SchemeGroupVersion.String(), KindTaskRun
+		content, err := yaml.Marshal(tr)
+		if err != nil {
+			return nil, err
+		}
+		record.Files = append(record.Files, File{Name: path.Join("taskruns", tr.GetName()+".yaml"), Kind: KindTaskRun, Content: redact(content)})
+
+		if tr.Status.PodName == "" {
+			continue
+		}
+		for _, step := range tr.Status.Steps {
+			log, err := a.kinteract.GetPodLogs(ctx, pr.GetNamespace(), tr.Status.PodName, step.Container, -1)
+			if err != nil {
+				logger.Debugf("cannot get the logs of step %s of taskrun %s to archive them: %v", step.Name, tr.GetName(), err)
+				continue
+			}
+			record.Files = append(record.Files, File{
+				Name:    path.Join("logs", tr.GetName(), step.Name+".log"),
+				Kind:    KindLog,
+				Content: redact([]byte(log)),
+			})
+		}
+	}
+	return record, nil
+}
@@ -0,0 +1,158 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func newArchiveTestPR() *tektonv1.PipelineRun {
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pr",
+			Namespace:   "ns",
+			UID:         "uid",
+			Annotations: map[string]string{keys.Repository: "repo"},
+		},
+		Spec: tektonv1.PipelineRunSpec{
+			PipelineSpec: &tektonv1.PipelineSpec{
+				Tasks: []tektonv1.PipelineTask{{
+					Name: "build",
+					TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{
+						Steps: []tektonv1.Step{{
+							Name: "compile",
+							Env: []corev1.EnvVar{{
+								Name: "TOKEN",
+								ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+									Key:                  "token",
+								}},
+							}},
+						}},
+					}},
+				}},
+			},
+		},
+		Status: tektonv1.PipelineRunStatus{
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				ChildReferences: []tektonv1.ChildStatusReference{
+					{TypeMeta: runtime.TypeMeta{Kind: KindTaskRun}, Name: "pr-build"},
+					{TypeMeta: runtime.TypeMeta{Kind: KindTaskRun}, Name: "pr-deleted"},
+				},
+			},
+		},
+	}
+}
+
+func TestArchive(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	tr := &tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr-build", Namespace: "ns"},
+		Status: tektonv1.TaskRunStatus{TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+			PodName: "pr-build-pod",
+			Steps: []tektonv1.StepState{
+				{Name: "compile", Container: "step-compile"},
+				{Name: "test", Container: "step-test"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		archiveURL string
+		archived   bool
+	}{
+		{
+			name: "no archive",
+		},
+		{
+			name:       "directory",
+			archiveURL: "file://",
+			archived:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			dir := t.TempDir()
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{TaskRuns: []*tektonv1.TaskRun{tr}})
+			kint := &kubernetestint.KinterfaceTest{
+				GetSecretResult:  map[string]string{"creds": "s3cr3t"},
+				GetPodLogsOutput: map[string]string{"pr-build-pod": "logged in with s3cr3t"},
+			}
+			archiveURL := tt.archiveURL
+			if archiveURL != "" {
+				archiveURL += dir
+			}
+			a := NewArchiver(&params.Run{
+				Clients: clients.Clients{Tekton: stdata.Pipeline, Kube: stdata.Kube},
+				Info: info.Info{
+					Kube: &info.KubeOpts{Namespace: "pac"},
+					Pac:  &info.PacOpts{Settings: settings.Settings{ArchiveURL: archiveURL}},
+				},
+			}, kint)
+
+			assert.NilError(t, a.Archive(ctx, logger, newArchiveTestPR()))
+
+			files := []string{}
+			assert.NilError(t, filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+				if err == nil && !info.IsDir() {
+					rel, _ := filepath.Rel(dir, p)
+					files = append(files, filepath.ToSlash(rel))
+				}
+				return err
+			}))
+			if !tt.archived {
+				assert.Equal(t, len(files), 0)
+				return
+			}
+			assert.DeepEqual(t, files, []string{
+				"ns/repo/pr/logs/pr-build/compile.log",
+				"ns/repo/pr/logs/pr-build/test.log",
+				"ns/repo/pr/pipelinerun.yaml",
+				"ns/repo/pr/taskruns/pr-build.yaml",
+			})
+
+			log, err := os.ReadFile(filepath.Join(dir, "ns/repo/pr/logs/pr-build/compile.log"))
+			assert.NilError(t, err)
+			assert.Equal(t, string(log), "logged in with *****")
+			pr, err := os.ReadFile(filepath.Join(dir, "ns/repo/pr/pipelinerun.yaml"))
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(pr), "kind: PipelineRun"))
+			taskRun, err := os.ReadFile(filepath.Join(dir, "ns/repo/pr/taskruns/pr-build.yaml"))
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(taskRun), "podName: pr-build-pod"))
+		})
+	}
+}
+
+func TestArchiveInvalidSink(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	a := NewArchiver(&params.Run{
+		Info: info.Info{
+			Kube: &info.KubeOpts{Namespace: "pac"},
+			Pac:  &info.PacOpts{Settings: settings.Settings{ArchiveURL: "s3://bucket"}},
+		},
+	}, &kubernetestint.KinterfaceTest{})
+	assert.ErrorContains(t, a.Archive(ctx, logger, newArchiveTestPR()), "the archive secret must have the access-key-id and secret-access-key keys")
+}
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// directorySink stores the archived PipelineRuns in a directory, usually
+// backed by a PVC, one directory per PipelineRun.
+type directorySink struct {
+	dir string
+}
+
+func (d *directorySink) Store(_ context.Context, record *Record) error {
+	root := filepath.Join(d.dir, filepath.FromSlash(record.Path()))
+	for _, file := range record.Files {
+		name := filepath.Join(root, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+			return fmt.Errorf("cannot create the archive directory: %w", err)
+		}
+		if err := os.WriteFile(name, file.Content, 0o600); err != nil {
+			return fmt.Errorf("cannot write %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const resultsAPIPrefix = "/apis/results.tekton.dev/v1alpha2"
+
+// serviceAccountTokenFile authenticates to the Tekton Results API when the
+// archive secret has no token.
+var serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint: gosec
+
+var recordTypes = map[string]string{
+	KindPipelineRun: "tekton.dev/v1.PipelineRun",
+	KindTaskRun:     "tekton.dev/v1.TaskRun",
+	KindLog:         "text/plain",
+}
+
+// resultsSink stores the archived PipelineRuns in a Tekton Results API, as a
+// Result named after the uid of the PipelineRun with a Record per file.
+type resultsSink struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+type resultsResult struct {
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type resultsRecord struct {
+	Name string            `json:"name"`
+	Data resultsRecordData `json:"data"`
+}
+
+type resultsRecordData struct {
+	Type string `json:"type"`
+	// Value is serialized as base64 like the bytes of the API.
+	Value []byte `json:"value"`
+}
+
+func newResultsSink(ctx context.Context, u *url.URL, credentials CredentialsFunc) (*resultsSink, error) {
+	base := *u
+	base.Scheme = strings.TrimPrefix(u.Scheme, "results+")
+	token := optionalCredential(ctx, credentials, TokenKey)
+	if token == "" {
+		if b, err := os.ReadFile(serviceAccountTokenFile); err == nil {
+			token = strings.TrimSpace(string(b))
+		}
+	}
+	return &resultsSink{
+		client:  httpClient,
+		baseURL: strings.TrimSuffix(base.String(), "/"),
+		token:   token,
+	}, nil
+}
+
+func (r *resultsSink) Store(ctx context.Context, record *Record) error {
+	parent := record.Namespace
+	resultName := parent + "/results/" + record.UID
+	result := resultsResult{
+		Name: resultName,
+		Annotations: map[string]string{
+			"repository":  record.Repository,
+			"pipelinerun": record.PipelineRun,
+		},
+	}
+	resultsURL := fmt.Sprintf("%s%s/parents/%s/results", r.baseURL, resultsAPIPrefix, url.PathEscape(parent))
+	// the result has already been created when a previous archive failed
+	if err := r.post(ctx, resultsURL, result, http.StatusConflict); err != nil {
+		return err
+	}
+
+	recordsURL := fmt.Sprintf("%s/%s/records", resultsURL, url.PathEscape(record.UID))
+	for _, file := range record.Files {
+		value := file.Content
+		if file.Kind != KindLog {
+			var err error
+			if value, err = yaml.YAMLToJSON(file.Content); err != nil {
+				return fmt.Errorf("cannot convert %s to json: %w", file.Name, err)
+			}
+		}
+		rec := resultsRecord{
+			Name: resultName + "/records/" + recordID(file.Name),
+			Data: resultsRecordData{Type: recordTypes[file.Kind], Value: value},
+		}
+		if err := r.post(ctx, recordsURL, rec, http.StatusConflict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordID turns the name of a file into the id of a record.
+func recordID(name string) string {
+	return strings.NewReplacer("/", "-", ".", "-").Replace(name)
+}
+
+func (r *resultsSink) post(ctx context.Context, postURL string, body any, allowedStatus int) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot post to the results API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != allowedStatus {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cannot post to the results API %s: %s: %s", postURL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
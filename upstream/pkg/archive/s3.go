@@ -0,0 +1,159 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const defaultS3Region = "us-east-1"
+
+// s3Sink stores the archived PipelineRuns in an S3 compatible bucket, one
+// object per file. The requests are signed with AWS Signature Version 4.
+type s3Sink struct {
+	client          *http.Client
+	endpoint        *url.URL
+	bucket          string
+	prefix          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	now             func() time.Time
+}
+
+// newS3Sink returns the sink of an URL like
+// s3://bucket/prefix?endpoint=https://minio:9000&region=us-east-1, the
+// endpoint defaults to the one of AWS for the region.
+func newS3Sink(ctx context.Context, u *url.URL, credentials CredentialsFunc) (*s3Sink, error) {
+	region := u.Query().Get("region")
+	if region == "" {
+		region = defaultS3Region
+	}
+	rawEndpoint := u.Query().Get("endpoint")
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", rawEndpoint)
+	}
+	sink := &s3Sink{
+		client:          httpClient,
+		endpoint:        endpoint,
+		bucket:          u.Host,
+		prefix:          strings.Trim(u.Path, "/"),
+		region:          region,
+		accessKeyID:     optionalCredential(ctx, credentials, AccessKeyIDKey),
+		secretAccessKey: optionalCredential(ctx, credentials, SecretAccessKeyKey),
+		now:             time.Now,
+	}
+	if sink.accessKeyID == "" || sink.secretAccessKey == "" {
+		return nil, fmt.Errorf("the archive secret must have the %s and %s keys to archive to S3", AccessKeyIDKey, SecretAccessKeyKey)
+	}
+	return sink, nil
+}
+
+func (s *s3Sink) Store(ctx context.Context, record *Record) error {
+	for _, file := range record.Files {
+		if err := s.put(ctx, path.Join(s.prefix, record.Path(), file.Name), file.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *s3Sink) put(ctx context.Context, key string, content []byte) error {
+	objectURL := *s.endpoint
+	objectURL.Path = path.Join("/", s.endpoint.Path, s.bucket, key)
+	objectURL.RawPath = s3EscapePath(objectURL.Path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	s.sign(req, content)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot upload %s to S3: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cannot upload %s to S3: %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 authorization of a request, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *s3Sink) sign(req *http.Request, content []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(content)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{date, s.region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(s3SigningKey(s.secretAccessKey, date, s.region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func s3SigningKey(secretAccessKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// s3EscapePath escapes every character of a path but the unreserved ones and
+// the slashes, as expected in the canonical request.
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for _, c := range []byte(p) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,166 @@
+package archive
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+var testRecord = &Record{
+	Namespace:   "ns",
+	Repository:  "repo",
+	PipelineRun: "pr",
+	UID:         "uid",
+	Files: []File{
+		{Name: "pipelinerun.yaml", Kind: KindPipelineRun, Content: []byte("kind: PipelineRun\n")},
+		{Name: "logs/pr-build/compile.log", Kind: KindLog, Content: []byte("compiled")},
+	},
+}
+
+func testCredentials(values map[string]string) CredentialsFunc {
+	return func(_ context.Context, key string) (string, error) {
+		if value, ok := values[key]; ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("no key %s", key)
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	ctx := context.Background()
+	credentials := testCredentials(map[string]string{AccessKeyIDKey: "AKID", SecretAccessKeyKey: "secret"})
+
+	sink, err := NewSink(ctx, "file:///archive", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, sink.(*directorySink).dir, "/archive")
+
+	sink, err = NewSink(ctx, "s3://bucket/some/prefix/", credentials)
+	assert.NilError(t, err)
+	s3 := sink.(*s3Sink)
+	assert.Equal(t, s3.endpoint.String(), "https://s3.us-east-1.amazonaws.com")
+	assert.Equal(t, s3.bucket, "bucket")
+	assert.Equal(t, s3.prefix, "some/prefix")
+	// a hanging sink must not block the cleanups
+	assert.Equal(t, s3.client.Timeout, requestTimeout)
+
+	sink, err = NewSink(ctx, "s3://bucket?region=eu-west-1&endpoint=http://minio:9000", credentials)
+	assert.NilError(t, err)
+	assert.Equal(t, sink.(*s3Sink).endpoint.String(), "http://minio:9000")
+	assert.Equal(t, sink.(*s3Sink).region, "eu-west-1")
+
+	sink, err = NewSink(ctx, "results+https://results.tekton-pipelines:8080", testCredentials(map[string]string{TokenKey: "token\n"}))
+	assert.NilError(t, err)
+	assert.Equal(t, sink.(*resultsSink).baseURL, "https://results.tekton-pipelines:8080")
+	assert.Equal(t, sink.(*resultsSink).token, "token")
+	assert.Equal(t, sink.(*resultsSink).client.Timeout, requestTimeout)
+
+	_, err = NewSink(ctx, "ftp://archive", nil)
+	assert.ErrorContains(t, err, "unsupported archive URL")
+}
+
+func TestDirectorySink(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, (&directorySink{dir: dir}).Store(context.Background(), testRecord))
+	content, err := os.ReadFile(filepath.Join(dir, "ns", "repo", "pr", "logs", "pr-build", "compile.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "compiled")
+}
+
+func TestS3SigningKey(t *testing.T) {
+	// example of the AWS Signature Version 4 documentation
+	key := s3SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, hex.EncodeToString(key), "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d")
+}
+
+func TestS3Sink(t *testing.T) {
+	lock := sync.Mutex{}
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		assert.Assert(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20240102/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+		assert.Equal(t, r.Header.Get("X-Amz-Date"), "20240102T030405Z")
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, r.Header.Get("X-Amz-Content-Sha256"), sha256Hex(body))
+		if strings.HasSuffix(r.URL.Path, "denied.log") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, "AccessDenied")
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		objects[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse("s3://bucket/prefix?region=eu-west-1&endpoint=" + server.URL)
+	sink, err := newS3Sink(context.Background(), u, testCredentials(map[string]string{AccessKeyIDKey: "AKID", SecretAccessKeyKey: "secret"}))
+	assert.NilError(t, err)
+	sink.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	assert.NilError(t, sink.Store(context.Background(), testRecord))
+	assert.DeepEqual(t, objects, map[string]string{
+		"/bucket/prefix/ns/repo/pr/pipelinerun.yaml":          "kind: PipelineRun\n",
+		"/bucket/prefix/ns/repo/pr/logs/pr-build/compile.log": "compiled",
+	})
+
+	denied := &Record{Namespace: "ns", Repository: "repo", PipelineRun: "pr", Files: []File{{Name: "denied.log", Kind: KindLog}}}
+	assert.ErrorContains(t, sink.Store(context.Background(), denied), "403 Forbidden: AccessDenied")
+}
+
+func TestResultsSink(t *testing.T) {
+	lock := sync.Mutex{}
+	records := map[string]resultsRecord{}
+	results := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+		lock.Lock()
+		defer lock.Unlock()
+		switch r.URL.Path {
+		case "/apis/results.tekton.dev/v1alpha2/parents/ns/results":
+			result := resultsResult{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&result))
+			assert.Equal(t, result.Name, "ns/results/uid")
+			results++
+			if results > 1 {
+				w.WriteHeader(http.StatusConflict)
+			}
+		case "/apis/results.tekton.dev/v1alpha2/parents/ns/results/uid/records":
+			record := resultsRecord{}
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&record))
+			records[record.Name] = record
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse("results+" + server.URL)
+	sink, err := newResultsSink(context.Background(), u, testCredentials(map[string]string{TokenKey: "token"}))
+	assert.NilError(t, err)
+
+	// archiving again after a failure
+	for range 2 {
+		assert.NilError(t, sink.Store(context.Background(), testRecord))
+	}
+	assert.Equal(t, len(records), 2)
+	pr := records["ns/results/uid/records/pipelinerun-yaml"]
+	assert.Equal(t, pr.Data.Type, "tekton.dev/v1.PipelineRun")
+	assert.Equal(t, string(pr.Data.Value), `{"kind":"PipelineRun"}`)
+	log := records["ns/results/uid/records/logs-pr-build-compile-log"]
+	assert.Equal(t, log.Data.Type, "text/plain")
+	assert.Equal(t, string(log.Data.Value), "compiled")
+}
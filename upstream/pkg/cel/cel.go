@@ -74,6 +74,7 @@ func Value(query string, body any, headers, pacParams map[string]string, changed
 			decls.NewVariable("pull_request_labels", types.StringType),
 			decls.NewVariable("pull_request_number", types.StringType),
 			decls.NewVariable("git_auth_secret", types.StringType),
+			decls.NewVariable("draft", types.BoolType),
 		))
 	val, err := evaluate(query, celDec, map[string]any{
 		"body":    jsonMap,
@@ -99,9 +100,38 @@ func Value(query string, body any, headers, pacParams map[string]string, changed
 		"pull_request_labels": pacParams["pull_request_labels"],
 		"pull_request_number": pacParams["pull_request_number"],
 		"git_auth_secret":     pacParams["git_auth_secret"],
+		"draft":               pacParams["draft"] == "true",
 	})
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
+
+// Retry evaluates the retry-on-cel-expression of a failed PipelineRun, the
+// expression has access to the reason and the message of its failure, the
+// names of its failed tasks and the number of its attempt, and has to return a
+// boolean.
+func Retry(expr, reason, message string, failedTasks []string, attempt int) (bool, error) {
+	celDec, _ := cel.NewEnv(
+		cel.VariableDecls(
+			decls.NewVariable("reason", types.StringType),
+			decls.NewVariable("message", types.StringType),
+			decls.NewVariable("failed_tasks", types.NewListType(types.StringType)),
+			decls.NewVariable("attempt", types.IntType),
+		))
+	val, err := evaluate(expr, celDec, map[string]any{
+		"reason":       reason,
+		"message":      message,
+		"failed_tasks": failedTasks,
+		"attempt":      attempt,
+	})
+	if err != nil {
+		return false, err
+	}
+	retry, ok := val.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression %#v must return a boolean, got %s", expr, val.Type().TypeName())
+	}
+	return bool(retry), nil
+}
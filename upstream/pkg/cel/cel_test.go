@@ -43,3 +43,49 @@ func TestValue(t *testing.T) {
 	_, err = Value("invalid query", body, headers, pacParams, changedFiles)
 	assert.ErrorContains(t, err, "failed to parse expression")
 }
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		retry   bool
+		wantErr string
+	}{
+		{
+			name:  "reason",
+			expr:  `reason == "Failed"`,
+			retry: true,
+		},
+		{
+			name:  "failed task",
+			expr:  `"integration" in failed_tasks && attempt < 2`,
+			retry: true,
+		},
+		{
+			name:  "message",
+			expr:  `message.contains("OOMKilled")`,
+			retry: false,
+		},
+		{
+			name:    "not a boolean",
+			expr:    `reason`,
+			wantErr: "must return a boolean",
+		},
+		{
+			name:    "unknown variable",
+			expr:    `task == "build"`,
+			wantErr: "check failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, err := Retry(tt.expr, "Failed", "Tasks Completed: 2 (Failed: 1)", []string{"build", "integration"}, 1)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, retry, tt.retry)
+		})
+	}
+}
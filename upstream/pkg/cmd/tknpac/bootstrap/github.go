@@ -23,6 +23,7 @@ func generateManifest(opts *bootstrapOpts) ([]byte, error) {
 			"check_suite",
 			"issue_comment",
 			"commit_comment",
+			triggertype.MergeGroup.String(),
 			triggertype.PullRequest.String(),
 			"push",
 		},
@@ -30,6 +31,7 @@ func generateManifest(opts *bootstrapOpts) ([]byte, error) {
 			Checks:       github.Ptr("write"),
 			Contents:     github.Ptr("write"),
 			Issues:       github.Ptr("write"),
+			MergeQueues:  github.Ptr("read"),
 			Members:      github.Ptr("read"),
 			Metadata:     github.Ptr("read"),
 			PullRequests: github.Ptr("write"),
@@ -8,6 +8,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	_ "embed"
@@ -154,7 +155,7 @@ func getCompletions(pacParams map[string]string, body map[string]any, headers ma
 			"event", "event_type", "target_branch", "source_branch", "target_url", "source_url",
 			"event_title", "revision", "repo_owner", "repo_name", "sender", "repo_url",
 			"git_tag", "target_namespace", "trigger_comment", "pull_request_labels",
-			"pull_request_number", "git_auth_secret",
+			"pull_request_number", "git_auth_secret", "draft",
 		}
 		defaultCompletions := append([]string{"/help", "/exit", "body", "headers", "pac", "files"}, directVars...)
 		var suggestions []string
@@ -449,6 +450,7 @@ func pacParamsFromEvent(event *info.Event) map[string]string {
 		"event_title":         eventTitle,
 		"trigger_comment":     triggerComment,
 		"pull_request_labels": pullRequestLabels,
+		"draft":               strconv.FormatBool(event.PullRequestDraft),
 	}
 }
 
@@ -535,6 +537,32 @@ func detectProvider(headers map[string]string, body []byte) (string, error) {
 	return "", fmt.Errorf("unable to detect provider from headers or payload")
 }
 
+// ReadHeadersFile reads the headers of a webhook from a file, the file can be
+// in JSON, in the plain HTTP headers format or a shell script generated by
+// gosmee.
+func ReadHeadersFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	bs := bytes.TrimSpace(b)
+	switch {
+	case len(bs) > 0 && (bs[0] == '{' || bs[0] == '['):
+		// JSON format headers
+		headers := map[string]string{}
+		if err := json.Unmarshal(bs, &headers); err != nil {
+			return nil, err
+		}
+		return headers, nil
+	case isGosmeeScript(string(bs)):
+		// Gosmee-generated shell script with curl commands
+		return parseGosmeeScript(string(bs))
+	default:
+		// Plain HTTP headers format
+		return parseHTTPHeaders(string(bs))
+	}
+}
+
 func Command(ioStreams *cli.IOStreams) *cobra.Command {
 	var bodyFile, headersFile, provider, githubToken string
 
@@ -565,32 +593,11 @@ that would be used in PipelineRun configurations.`,
 			}
 
 			if headersFile != "" {
-				b, err := os.ReadFile(headersFile)
+				h, err := ReadHeadersFile(headersFile)
 				if err != nil {
 					return err
 				}
-				bs := bytes.TrimSpace(b)
-				switch {
-				case len(bs) > 0 && (bs[0] == '{' || bs[0] == '['):
-					// JSON format headers
-					if err := json.Unmarshal(bs, &headers); err != nil {
-						return err
-					}
-				case isGosmeeScript(string(bs)):
-					// Gosmee-generated shell script with curl commands
-					h, err := parseGosmeeScript(string(bs))
-					if err != nil {
-						return err
-					}
-					headers = h
-				default:
-					// Plain HTTP headers format
-					h, err := parseHTTPHeaders(string(bs))
-					if err != nil {
-						return err
-					}
-					headers = h
-				}
+				headers = h
 			}
 			// nolint:ineffassign,staticcheck
 			pacParams := map[string]string{}
@@ -753,6 +753,7 @@ func TestPacParamsFromEvent(t *testing.T) {
 				TriggerTarget:    triggertype.PullRequest,
 				TriggerComment:   "test comment\nwith newlines",
 				PullRequestLabel: []string{"bug", "enhancement"},
+				PullRequestDraft: true,
 			},
 			want: map[string]string{
 				"revision":            "abc123",
@@ -771,6 +772,7 @@ func TestPacParamsFromEvent(t *testing.T) {
 				"event_title":         "Add new feature",
 				"trigger_comment":     "test comment\\nwith newlines",
 				"pull_request_labels": "bug\nenhancement",
+				"draft":               "true",
 			},
 		},
 		{
@@ -806,6 +808,7 @@ func TestPacParamsFromEvent(t *testing.T) {
 				"event_title":         "Release v1.0.0",
 				"trigger_comment":     "",
 				"pull_request_labels": "",
+				"draft":               "false",
 			},
 		},
 	}
@@ -851,6 +854,7 @@ func TestPacParamsFromEventEdgeCases(t *testing.T) {
 				"event_title":         "",
 				"trigger_comment":     "",
 				"pull_request_labels": "",
+				"draft":               "false",
 			},
 		},
 		{
@@ -876,6 +880,7 @@ func TestPacParamsFromEventEdgeCases(t *testing.T) {
 				"event_title":         "",
 				"trigger_comment":     "line1\\nline2\\nline3", // newlines escaped
 				"pull_request_labels": "",
+				"draft":               "false",
 			},
 		},
 		{
@@ -900,6 +905,7 @@ func TestPacParamsFromEventEdgeCases(t *testing.T) {
 				"event_title":         "",
 				"trigger_comment":     "",
 				"pull_request_labels": "",
+				"draft":               "false",
 			},
 		},
 	}
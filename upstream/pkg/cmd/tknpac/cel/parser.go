@@ -143,6 +143,7 @@ func (p *GitHubParser) PopulateEvent(event *info.Event, parsedEvent any) error {
 		event.SHA = gitEvent.GetPullRequest().GetHead().GetSHA()
 		event.SHAURL = fmt.Sprintf("%s/commit/%s", gitEvent.GetPullRequest().GetHTMLURL(), gitEvent.GetPullRequest().GetHead().GetSHA())
 		event.PullRequestTitle = gitEvent.GetPullRequest().GetTitle()
+		event.PullRequestDraft = gitEvent.GetPullRequest().GetDraft()
 		event.HeadBranch = gitEvent.GetPullRequest().GetHead().GetRef()
 		event.BaseBranch = gitEvent.GetPullRequest().GetBase().GetRef()
 		event.HeadURL = gitEvent.GetPullRequest().GetHead().GetRepo().GetHTMLURL()
@@ -257,6 +258,7 @@ func (p *GitLabParser) PopulateEvent(event *info.Event, parsedEvent any) error {
 		}
 		event.PullRequestNumber = gitEvent.ObjectAttributes.IID
 		event.PullRequestTitle = gitEvent.ObjectAttributes.Title
+		event.PullRequestDraft = gitEvent.ObjectAttributes.Draft
 		event.DefaultBranch = gitEvent.Project.DefaultBranch
 		event.TriggerTarget = triggertype.PullRequest
 		if gitEvent.ObjectAttributes.Action == "close" {
@@ -353,6 +355,7 @@ func (p *BitbucketCloudParser) PopulateEvent(event *info.Event, parsedEvent any)
 		event.BaseURL = e.PullRequest.Destination.Repository.Links.HTML.HRef
 		event.PullRequestNumber = e.PullRequest.ID
 		event.PullRequestTitle = e.PullRequest.Title
+		event.PullRequestDraft = e.PullRequest.Draft
 		event.TriggerTarget = triggertype.PullRequest
 		if event.EventType == "pullrequest:rejected" || event.EventType == "pullrequest:fulfilled" {
 			event.TriggerTarget = triggertype.PullRequestClosed
@@ -487,6 +490,9 @@ func (p *BitbucketDataCenterParser) PopulateEvent(event *info.Event, parsedEvent
 			if title, ok := pullRequest["title"].(string); ok {
 				event.PullRequestTitle = title
 			}
+			if draft, ok := pullRequest["draft"].(bool); ok {
+				event.PullRequestDraft = draft
+			}
 		}
 		if actor, ok := data["actor"].(map[string]any); ok {
 			if name, ok := actor["name"].(string); ok {
@@ -590,6 +596,7 @@ func (p *GiteaParser) PopulateEvent(event *info.Event, parsedEvent any) error {
 		event.BaseURL = gitEvent.PullRequest.Base.Repository.HTMLURL
 		event.PullRequestNumber = int(gitEvent.Index)
 		event.PullRequestTitle = gitEvent.PullRequest.Title
+		event.PullRequestDraft = gitEvent.PullRequest.Draft
 		event.DefaultBranch = gitEvent.Repository.DefaultBranch
 		event.TriggerTarget = triggertype.PullRequest
 		if gitEvent.Action == giteaStructs.HookIssueClosed {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"text/tabwriter"
 	"text/template"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -161,6 +163,27 @@ func filterOnlyToPipelineRun(opts *describeOpts, statuses []v1alpha1.RepositoryR
 	return ret
 }
 
+// livePriorities returns the effective priority of the PipelineRuns of the
+// repository by name, when the repository has a concurrency limit or the
+// PipelineRun has a priority annotation.
+func livePriorities(ctx context.Context, cs *params.Run, repository *v1alpha1.Repository) map[string]string {
+	priorities := map[string]string{}
+	prs, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repository.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: keys.Repository + "=" + repository.Name,
+	})
+	if err != nil {
+		return priorities
+	}
+	hasConcurrency := repository.Spec.ConcurrencyLimit != nil && *repository.Spec.ConcurrencyLimit > 0
+	for i := range prs.Items {
+		pr := &prs.Items[i]
+		if _, ok := pr.GetAnnotations()[keys.Priority]; ok || hasConcurrency {
+			priorities[pr.GetName()] = strconv.Itoa(sync.PipelineRunPriority(pr))
+		}
+	}
+	return priorities
+}
+
 func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *describeOpts, ioStreams *cli.IOStreams, repoName string) error {
 	var repository *v1alpha1.Repository
 	var err error
@@ -238,6 +261,7 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 	}
 
 	statuses := status.MixLivePRandRepoStatus(ctx, cs, *repository)
+	priorities := livePriorities(ctx, cs, repository)
 
 	if opts.TargetPipelineRun != "" {
 		statuses = filterOnlyToPipelineRun(opts, statuses)
@@ -253,6 +277,7 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		Clock       clockwork.Clock
 		Opts        *describeOpts
 		EventList   []corev1.Event
+		Priorities  map[string]string
 	}{
 		Repository:  repository,
 		Statuses:    statuses,
@@ -260,6 +285,7 @@ func describe(ctx context.Context, cs *params.Run, clock clockwork.Clock, opts *
 		Clock:       clock,
 		EventList:   eventList,
 		Opts:        opts,
+		Priorities:  priorities,
 	}
 	w := ansiterm.NewTabWriter(ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
 	t := template.Must(template.New("Describe Repository").Funcs(funcMap).Parse(describeTemplate))
@@ -39,6 +39,7 @@ func TestDescribe(t *testing.T) {
 		opts             *describeOpts
 		pruns            []*tektonv1.PipelineRun
 		events           []*corev1.Event
+		settings         *v1alpha1.Settings
 	}
 	tests := []struct {
 		name    string
@@ -100,6 +101,25 @@ func TestDescribe(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "live run with priority",
+			args: args{
+				repoName:         "test-run",
+				currentNamespace: ns,
+				opts:             &describeOpts{},
+				settings:         &v1alpha1.Settings{DefaultPriority: github.Ptr(10)},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, "running", ns, running, map[string]string{
+						keys.Branch:   "tartanpion",
+						keys.Priority: "20",
+					}, map[string]string{
+						keys.Repository: "test-run",
+					}, 30),
+				},
+				statuses: []v1alpha1.RepositoryRunStatus{},
+			},
+			wantErr: false,
+		},
 		{
 			name: "target a pipelinerun",
 			args: args{
@@ -460,7 +480,8 @@ func TestDescribe(t *testing.T) {
 						Namespace: ns,
 					},
 					Spec: v1alpha1.RepositorySpec{
-						URL: "https://anurl.com",
+						URL:      "https://anurl.com",
+						Settings: tt.args.settings,
 					},
 					Status: tt.args.statuses,
 				},
@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputFlag    = "output"
+	paramsFlag    = "params"
+	outputText    = "text"
+	outputJSON    = "json"
+	outputSARIF   = "sarif"
+	defaultTekton = ".tekton"
+)
+
+var longhelp = fmt.Sprintf(`lint - check the .tekton directory before pushing it.
+
+Check locally the PipelineRuns of a repository for the errors Pipelines-as-Code
+would only report when receiving an event: invalid YAML or Tekton resources,
+unknown or malformed Pipelines-as-Code annotations, CEL expressions which do
+not compile, PipelineRuns with the same name, {{ parameters }} which will not be
+replaced and remote tasks which cannot be found in the repository.
+
+The .tekton directory of the current directory is checked unless some files or
+directories are given as arguments:
+
+%s pac lint .tekton/pull-request.yaml
+
+The custom parameters of the Repository are not known locally, declare them with
+the -p flag to not get warned about them:
+
+%s pac lint -p my_custom_param
+
+The result can be output as JSON or SARIF with the -o flag, for example to be
+used in a pre-commit hook or uploaded to a code scanning service. The command
+exits with an error when an error has been found, the warnings only are not
+making it fail.`, settings.TknBinaryName, settings.TknBinaryName)
+
+type lintOptions struct {
+	ioStreams    *cli.IOStreams
+	output       string
+	customParams []string
+	rootDir      string
+}
+
+// Result is the output of the linter as JSON.
+type Result struct {
+	Files    int       `json:"files"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Findings []Finding `json:"findings"`
+}
+
+func Command(ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &lintOptions{ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "lint [file or directory...]",
+		Short: "Check the PipelineRuns of the .tekton directory for errors",
+		Long:  longhelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch opts.output {
+			case outputText, outputJSON, outputSARIF:
+			default:
+				return fmt.Errorf("invalid output format %q, must be one of %s, %s or %s", opts.output, outputText, outputJSON, outputSARIF)
+			}
+			if len(args) == 0 {
+				args = []string{defaultTekton}
+			}
+			if opts.rootDir = git.GetGitInfo(".").TopLevelPath; opts.rootDir == "" {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				opts.rootDir = cwd
+			}
+			return lint(cmd.Context(), opts, args)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVarP(&opts.output, outputFlag, "o", outputText,
+		"output format, one of text, json or sarif")
+	cmd.Flags().StringSliceVarP(&opts.customParams, paramsFlag, "p", []string{},
+		"custom parameters of the Repository used in the PipelineRuns")
+	return cmd
+}
+
+func lint(ctx context.Context, opts *lintOptions, paths []string) error {
+	l := &linter{rootDir: opts.rootDir, customParams: opts.customParams}
+	findings, files, err := l.lintFiles(ctx, paths)
+	if err != nil {
+		return err
+	}
+	result := Result{Files: files, Findings: findings}
+	for _, f := range findings {
+		if f.Severity == severityError {
+			result.Errors++
+		} else {
+			result.Warnings++
+		}
+	}
+
+	switch opts.output {
+	case outputJSON:
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(opts.ioStreams.Out, string(out))
+	case outputSARIF:
+		out, err := json.MarshalIndent(toSARIF(findings), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(opts.ioStreams.Out, string(out))
+	default:
+		printText(opts.ioStreams, result)
+	}
+
+	if result.Errors > 0 {
+		return fmt.Errorf("%d error(s) found", result.Errors)
+	}
+	return nil
+}
+
+func printText(ioStreams *cli.IOStreams, result Result) {
+	cs := ioStreams.ColorScheme()
+	for _, f := range result.Findings {
+		severity := cs.Red(f.Severity)
+		if f.Severity == severityWarning {
+			severity = cs.Yellow(f.Severity)
+		}
+		resource := ""
+		if f.Resource != "" {
+			resource = fmt.Sprintf(" %s:", cs.Bold(f.Resource))
+		}
+		fmt.Fprintf(ioStreams.Out, "%s:%d: %s:%s %s %s\n", f.File, f.Line, severity, resource, f.Message, cs.Dimmed("["+f.Rule+"]"))
+	}
+	if len(result.Findings) == 0 {
+		fmt.Fprintf(ioStreams.Out, "%s no problems found in %d file(s)\n", cs.SuccessIcon(), result.Files)
+		return
+	}
+	fmt.Fprintf(ioStreams.Out, "\n%d error(s) and %d warning(s) found in %d file(s)\n", result.Errors, result.Warnings, result.Files)
+}
@@ -0,0 +1,310 @@
+package lint
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const validPipelineRun = `---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pull-request
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main, release-*]"
+    pipelinesascode.tekton.dev/task: "[git-clone, https://example.com/task.yaml]"
+spec:
+  params:
+    - name: revision
+      value: "{{ revision }}"
+    - name: number
+      value: "{{ body.pull_request.number }}"
+  pipelineSpec:
+    tasks:
+      - name: noop
+        taskSpec:
+          steps:
+            - name: noop
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: "true"
+`
+
+func TestLintFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		customParams []string
+		// wantFindings are "file:line:severity:rule" of the findings
+		wantFindings []string
+		wantMessages []string
+	}{
+		{
+			name: "no problems",
+			files: map[string]string{
+				"pull-request.yaml": validPipelineRun,
+				"lint-task.yaml":    "apiVersion: tekton.dev/v1\nkind: Task\nmetadata:\n  name: lint\nspec:\n  steps:\n    - name: lint\n      image: golangci-lint\n      script: golangci-lint run\n",
+			},
+		},
+		{
+			name: "invalid yaml",
+			files: map[string]string{
+				"bad.yaml": "apiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: [bad\n",
+			},
+			wantFindings: []string{"bad.yaml:4:error:yaml"},
+			wantMessages: []string{"no PipelineRun of the repository will run until it is fixed"},
+		},
+		{
+			name: "invalid tekton schema",
+			files: map[string]string{
+				"schema.yaml": "apiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: schema\n  annotations:\n    pipelinesascode.tekton.dev/on-cel-expression: event == \"push\"\nspec:\n  pipelineSpec:\n    tasks:\n      - name: noop\n",
+			},
+			wantFindings: []string{"schema.yaml:1:error:tekton-schema"},
+			wantMessages: []string{"expected exactly one, got neither"},
+		},
+		{
+			name: "unknown kinds are ignored",
+			files: map[string]string{
+				"stepaction.yaml": "apiVersion: tekton.dev/v1alpha1\nkind: StepAction\nmetadata:\n  name: clone\nspec:\n  image: alpine\n",
+				"configmap.yaml":  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n",
+			},
+		},
+		{
+			name: "bad annotations",
+			files: map[string]string{
+				"annotations.yaml": `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: annotations
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request, pull-request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main, release-[0-9]"
+    pipelinesascode.tekton.dev/on-targetbranch: "main"
+    pipelinesascode.tekton.dev/max-keep-runs: "5"
+    other.io/annotation: "ignored"
+spec:
+  pipelineRef:
+    name: pipeline
+`,
+			},
+			wantFindings: []string{
+				"annotations.yaml:6:error:on-event",
+				"annotations.yaml:7:error:on-target-branch",
+				"annotations.yaml:8:warning:unknown-annotation",
+			},
+			wantMessages: []string{
+				`unknown event "pull-request"`,
+				`malformed branch glob "release-[0-9"`,
+				"annotation pipelinesascode.tekton.dev/on-targetbranch is not a Pipelines-as-Code annotation",
+			},
+		},
+		{
+			name: "on-event without on-target-branch",
+			files: map[string]string{
+				"incomplete.yaml": "apiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  name: incomplete\n  annotations:\n    pipelinesascode.tekton.dev/on-event: push\nspec:\n  pipelineRef:\n    name: pipeline\n",
+			},
+			wantFindings: []string{"incomplete.yaml:6:warning:on-target-branch"},
+			wantMessages: []string{"never be matched without an on-target-branch annotation"},
+		},
+		{
+			name: "cel expressions",
+			files: map[string]string{
+				"cel.yaml": `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: cel
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-cel-expression: event == "push" && target_branch ==
+spec:
+  pipelineRef:
+    name: pipeline
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: cel-not-bool
+  annotations:
+    pipelinesascode.tekton.dev/on-cel-expression: target_branch
+spec:
+  pipelineRef:
+    name: pipeline
+`,
+			},
+			wantFindings: []string{
+				"cel.yaml:7:error:on-cel-expression",
+				"cel.yaml:7:warning:on-cel-expression",
+				"cel.yaml:17:error:on-cel-expression",
+			},
+			wantMessages: []string{
+				"failed to parse expression",
+				"annotations are ignored when the on-cel-expression annotation is set",
+				"returns a string instead of a bool",
+			},
+		},
+		{
+			name: "duplicate names",
+			files: map[string]string{
+				"a.yaml": "apiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  generateName: ci-\nspec:\n  pipelineRef:\n    name: pipeline\n",
+				"b.yaml": "# comment\napiVersion: tekton.dev/v1\nkind: PipelineRun\nmetadata:\n  generateName: ci-\nspec:\n  pipelineRef:\n    name: pipeline\n",
+			},
+			wantFindings: []string{"b.yaml:1:error:duplicate-name"},
+			wantMessages: []string{"PipelineRun ci- is already defined in"},
+		},
+		{
+			name: "unresolved params",
+			files: map[string]string{
+				"params.yaml": strings.Replace(validPipelineRun, "{{ revision }}", "{{ revision }}-{{ build_id }}-{{ my_param }}", 1),
+			},
+			customParams: []string{"my_param"},
+			wantFindings: []string{"params.yaml:13:warning:unresolved-param"},
+			wantMessages: []string{"parameter {{ build_id }} is not a standard parameter"},
+		},
+		{
+			name: "remote tasks",
+			files: map[string]string{
+				"remote.yaml": `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: remote
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+    pipelinesascode.tekton.dev/task: "[git-clone, custom://buildah, https://]"
+    pipelinesascode.tekton.dev/task-1: ".tekton/tasks/missing.yaml"
+    pipelinesascode.tekton.dev/task-2: ".tekton/exists.yaml"
+    pipelinesascode.tekton.dev/pipeline: "[pipeline-a, pipeline-b]"
+spec:
+  pipelineRef:
+    name: pipeline-a
+`,
+				"exists.yaml": "apiVersion: tekton.dev/v1\nkind: Task\nmetadata:\n  name: exists\nspec:\n  steps:\n    - name: noop\n      image: alpine\n",
+			},
+			wantFindings: []string{
+				"remote.yaml:8:error:remote-task",
+				"remote.yaml:9:error:remote-task",
+				"remote.yaml:11:error:remote-task",
+			},
+			wantMessages: []string{
+				`"https://" is not a valid URL`,
+				".tekton/tasks/missing.yaml cannot be found inside the repository",
+				"only one pipeline is allowed on remote resolution",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ops := []fs.PathOp{}
+			for name, content := range tt.files {
+				ops = append(ops, fs.WithFile(name, content))
+			}
+			tmpdir := fs.NewDir(t, "TestLintFiles", fs.WithDir(".tekton", ops...))
+			tektonDir := tmpdir.Join(".tekton")
+			l := &linter{rootDir: tmpdir.Path(), customParams: tt.customParams}
+			findings, files, err := l.lintFiles(ctx, []string{tektonDir})
+			assert.NilError(t, err)
+			assert.Equal(t, files, len(tt.files))
+
+			got := []string{}
+			messages := ""
+			for _, f := range findings {
+				rel, err := filepath.Rel(tektonDir, f.File)
+				assert.NilError(t, err)
+				got = append(got, strings.Join([]string{rel, strconv.Itoa(f.Line), f.Severity, f.Rule}, ":"))
+				messages += f.Message + "\n"
+			}
+			if len(tt.wantFindings) == 0 {
+				assert.Equal(t, len(got), 0, "unexpected findings: %v\n%s", got, messages)
+				return
+			}
+			assert.DeepEqual(t, got, tt.wantFindings)
+			for _, msg := range tt.wantMessages {
+				assert.Assert(t, strings.Contains(messages, msg), "%q not found in %s", msg, messages)
+			}
+		})
+	}
+}
+
+func TestLintOutput(t *testing.T) {
+	tmpdir := fs.NewDir(t, "TestLintOutput", fs.WithDir(".tekton",
+		fs.WithFile("pr.yaml", strings.Replace(validPipelineRun, "{{ revision }}", "{{ unknown }}", 1)),
+		fs.WithFile("bad.yaml", "foo: [bar\n"),
+	))
+
+	tests := []struct {
+		output  string
+		wantErr string
+		check   func(t *testing.T, out string)
+	}{
+		{
+			output:  outputText,
+			wantErr: "1 error(s) found",
+			check: func(t *testing.T, out string) {
+				assert.Assert(t, strings.Contains(out, "pr.yaml:13: warning: parameter {{ unknown }}"), out)
+				assert.Assert(t, strings.Contains(out, "1 error(s) and 1 warning(s) found in 2 file(s)"), out)
+			},
+		},
+		{
+			output:  outputJSON,
+			wantErr: "1 error(s) found",
+			check: func(t *testing.T, out string) {
+				result := Result{}
+				assert.NilError(t, json.Unmarshal([]byte(out), &result))
+				assert.Equal(t, result.Files, 2)
+				assert.Equal(t, result.Errors, 1)
+				assert.Equal(t, result.Warnings, 1)
+				assert.Equal(t, result.Findings[0].Rule, ruleYAML)
+			},
+		},
+		{
+			output:  outputSARIF,
+			wantErr: "1 error(s) found",
+			check: func(t *testing.T, out string) {
+				sarif := sarifLog{}
+				assert.NilError(t, json.Unmarshal([]byte(out), &sarif))
+				assert.Equal(t, sarif.Version, sarifVersion)
+				assert.Equal(t, len(sarif.Runs), 1)
+				assert.Equal(t, len(sarif.Runs[0].Tool.Driver.Rules), len(rules))
+				results := sarif.Runs[0].Results
+				assert.Equal(t, len(results), 2)
+				assert.Equal(t, results[0].Level, severityError)
+				assert.Equal(t, results[1].RuleID, ruleUnresolvedParam)
+				assert.Equal(t, results[1].Level, severityWarning)
+				assert.Equal(t, results[1].Locations[0].PhysicalLocation.Region.StartLine, 13)
+				assert.Assert(t, strings.HasSuffix(results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI, ".tekton/pr.yaml"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ios, _, out, _ := cli.IOTest()
+			opts := &lintOptions{ioStreams: ios, output: tt.output, rootDir: tmpdir.Path()}
+			err := lint(ctx, opts, []string{tmpdir.Join(".tekton")})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			tt.check(t, out.String())
+		})
+	}
+}
+
+func TestSplitDocuments(t *testing.T) {
+	docs := splitDocuments("file.yaml", "---\n# first\na: b\n---\n\n\nc: d\n---\n")
+	assert.Equal(t, len(docs), 2)
+	assert.Equal(t, docs[0].line, 2)
+	assert.Equal(t, docs[0].content, "# first\na: b\n")
+	assert.Equal(t, docs[1].line, 7)
+	assert.Equal(t, docs[1].content, "c: d\n")
+}
@@ -0,0 +1,494 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacerrors "github.com/openshift-pipelines/pipelines-as-code/pkg/errors"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	// placeholderValue replaces the values we cannot know before the
+	// PipelineRun is created.
+	placeholderValue = "placeholder"
+
+	ruleYAML            = "yaml"
+	ruleTektonSchema    = "tekton-schema"
+	ruleUnknownAnnot    = "unknown-annotation"
+	ruleOnEvent         = "on-event"
+	ruleOnTargetBranch  = "on-target-branch"
+	ruleOnCelExpression = "on-cel-expression"
+	ruleDuplicateName   = "duplicate-name"
+	ruleUnresolvedParam = "unresolved-param"
+	ruleRemoteTask      = "remote-task"
+)
+
+// rules describes the checks done by the linter, in the order they are shown
+// in the SARIF output.
+var rules = []struct {
+	ID          string
+	Description string
+}{
+	{ruleYAML, "The file is not valid YAML"},
+	{ruleTektonSchema, "The document is not a valid Tekton resource"},
+	{ruleUnknownAnnot, "The annotation is not a Pipelines-as-Code annotation of a PipelineRun"},
+	{ruleOnEvent, "The on-event annotation cannot match any event"},
+	{ruleOnTargetBranch, "The on-target-branch annotation cannot match any branch"},
+	{ruleOnCelExpression, "The on-cel-expression annotation cannot be compiled"},
+	{ruleDuplicateName, "Multiple PipelineRuns have the same name"},
+	{ruleUnresolvedParam, "The {{ parameter }} will not be replaced by Pipelines-as-Code"},
+	{ruleRemoteTask, "The remote task or pipeline annotation cannot be resolved"},
+}
+
+var (
+	yamlDocSeparatorRe = regexp.MustCompile(`(?m)^---\s*$`)
+	yamlErrorLineRe    = regexp.MustCompile(`line (\d+)`)
+	// the documents of a kind unknown to Pipelines-as-Code, like the
+	// StepActions, are ignored on the server.
+	ignoredDecodeErrorsRe = regexp.MustCompile(`.*no kind.*is registered for version.*in scheme.*`)
+	// same as the annotations the remote tasks and pipeline are fetched from.
+	remoteTaskAnnotationRe     = regexp.MustCompile(fmt.Sprintf("^%s/task(-[0-9]+)?$", pipelinesascode.GroupName))
+	remotePipelineAnnotationRe = regexp.MustCompile(fmt.Sprintf("^%s/pipeline$", pipelinesascode.GroupName))
+
+	// onEvents are the events a PipelineRun can be matched on with the
+	// on-event annotation.
+	onEvents = []string{
+		triggertype.PullRequest.String(),
+		triggertype.Push.String(),
+		triggertype.Incoming.String(),
+		triggertype.PullRequestClosed.String(),
+		triggertype.MergeGroup.String(),
+	}
+
+	// pipelineRunAnnotations are the Pipelines-as-Code annotations that can
+	// be set on a PipelineRun of the .tekton directory.
+	pipelineRunAnnotations = []string{
+		keys.OnEvent,
+		keys.OnComment,
+		keys.OnTargetBranch,
+		keys.OnPathChange,
+		keys.OnPathChangeIgnore,
+		keys.OnLabel,
+		keys.OnDraft,
+		keys.OnCelExpression,
+		keys.TargetNamespace,
+		keys.MaxKeepRuns,
+		keys.CancelInProgress,
+		keys.Priority,
+		keys.ConcurrencyGroup,
+		keys.ConcurrencyGroupCEL,
+		keys.ConcurrencyGroupLimit,
+		keys.MaxRetries,
+		keys.RetryCELExpression,
+	}
+
+	// standardParams are the {{ parameters }} Pipelines-as-Code replaces from
+	// the event, the body, headers and files ones are evaluated as CEL.
+	standardParams = []string{
+		"revision", "repo_url", "repo_owner", "repo_name", "target_branch",
+		"source_branch", "git_tag", "source_url", "sender", "target_namespace",
+		"event_type", "trigger_comment", "pull_request_labels",
+		"pull_request_number", "git_auth_secret",
+	}
+	celParamPrefixes = []string{"body", "headers", "files"}
+)
+
+// Finding is a problem found in a file of the .tekton directory.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Resource string `json:"resource,omitempty"`
+	Message  string `json:"message"`
+}
+
+// document is a yaml document of a file with the line it starts at.
+type document struct {
+	file    string
+	line    int
+	content string
+}
+
+type namedPipelineRun struct {
+	doc  document
+	name string
+}
+
+type linter struct {
+	// rootDir is the root of the repository, where the remote tasks
+	// referenced by a path are looked up.
+	rootDir      string
+	customParams []string
+	findings     []Finding
+	pipelineRuns []namedPipelineRun
+}
+
+// lintFiles checks the yaml files of the paths, the directories are walked
+// through like the .tekton directory is on the server.
+func (l *linter) lintFiles(ctx context.Context, paths []string) ([]Finding, int, error) {
+	files, err := listYamlFiles(paths)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, 0, err
+		}
+		l.lintFile(ctx, file, string(content))
+	}
+	l.checkDuplicateNames()
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].File != l.findings[j].File {
+			return l.findings[i].File < l.findings[j].File
+		}
+		return l.findings[i].Line < l.findings[j].Line
+	})
+	return l.findings, len(files), nil
+}
+
+func (l *linter) lintFile(ctx context.Context, file, content string) {
+	if err := provider.ValidateYaml([]byte(content), file); err != nil {
+		line := 1
+		if m := yamlErrorLineRe.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		l.add(ruleYAML, severityError, document{file: file, line: line}, "",
+			fmt.Sprintf("%v, no PipelineRun of the repository will run until it is fixed", err))
+		return
+	}
+
+	for _, doc := range splitDocuments(file, content) {
+		l.checkParams(doc)
+		l.lintDocument(ctx, doc)
+	}
+}
+
+func (l *linter) lintDocument(ctx context.Context, doc document) {
+	types, err := resolve.ReadTektonTypes(ctx, zap.NewNop().Sugar(), doc.content)
+	if err != nil {
+		l.add(ruleTektonSchema, severityError, doc, "", err.Error())
+		return
+	}
+	for _, verr := range types.ValidationErrors {
+		if ignoredDecodeErrorsRe.MatchString(verr.Err.Error()) ||
+			(!strings.HasPrefix(verr.Schema, tektonv1.SchemeGroupVersion.Group) && verr.Schema != pacerrors.GenericBadYAMLValidation) {
+			continue
+		}
+		l.add(ruleTektonSchema, severityError, doc, verr.Name, verr.Err.Error())
+	}
+
+	for _, pr := range types.PipelineRuns {
+		name := pr.GetGenerateName()
+		if name == "" {
+			name = pr.GetName()
+		}
+		l.checkPipelineRunAnnotations(doc, name, pr.GetAnnotations())
+		l.checkRemoteAnnotations(doc, name, pr.GetAnnotations())
+		l.pipelineRuns = append(l.pipelineRuns, namedPipelineRun{doc: doc, name: name})
+	}
+	for _, pipeline := range types.Pipelines {
+		l.checkRemoteAnnotations(doc, pipeline.GetName(), pipeline.GetAnnotations())
+	}
+	l.checkSchema(ctx, doc)
+}
+
+// checkSchema validates the Tekton resources of a document the way they are
+// when created, after the {{ parameters }} have been replaced and with a name
+// generated by the API server.
+func (l *linter) checkSchema(ctx context.Context, doc document) {
+	types, err := resolve.ReadTektonTypes(ctx, zap.NewNop().Sugar(), keys.ParamsRe.ReplaceAllString(doc.content, placeholderValue))
+	if err != nil {
+		return
+	}
+	resources := map[string]apis.Validatable{}
+	for _, pr := range types.PipelineRuns {
+		name := pr.GetGenerateName()
+		if pr.GetName() == "" {
+			pr.SetName(pr.GetGenerateName() + placeholderValue)
+		} else {
+			name = pr.GetName()
+		}
+		resources[name] = pr
+	}
+	for _, pipeline := range types.Pipelines {
+		resources[pipeline.GetName()] = pipeline
+	}
+	for _, task := range types.Tasks {
+		resources[task.GetName()] = task
+	}
+	for _, name := range slices.Sorted(maps.Keys(resources)) {
+		if ferr := resources[name].Validate(ctx); ferr != nil {
+			for _, e := range ferr.WrappedErrors() {
+				l.add(ruleTektonSchema, severityError, doc, name, e.Error())
+			}
+		}
+	}
+}
+
+func (l *linter) checkPipelineRunAnnotations(doc document, name string, annotations map[string]string) {
+	for _, key := range sortedKeys(annotations) {
+		value := annotations[key]
+		if !strings.HasPrefix(key, pipelinesascode.GroupName+"/") ||
+			remoteTaskAnnotationRe.MatchString(key) || remotePipelineAnnotationRe.MatchString(key) {
+			continue
+		}
+		if !slices.Contains(pipelineRunAnnotations, key) {
+			l.addAt(ruleUnknownAnnot, severityWarning, doc, key, name,
+				fmt.Sprintf("annotation %s is not a Pipelines-as-Code annotation that can be set on a PipelineRun", key))
+			continue
+		}
+
+		switch key {
+		case keys.OnEvent:
+			for _, msg := range validateOnEvent(value) {
+				l.addAt(ruleOnEvent, severityError, doc, key, name, msg)
+			}
+		case keys.OnTargetBranch:
+			for _, msg := range validateOnTargetBranch(value) {
+				l.addAt(ruleOnTargetBranch, severityError, doc, key, name, msg)
+			}
+		case keys.OnCelExpression:
+			if err := matcher.ValidateCelExpression(value); err != nil {
+				l.addAt(ruleOnCelExpression, severityError, doc, key, name, err.Error())
+			}
+		}
+	}
+
+	_, hasEvent := annotations[keys.OnEvent]
+	_, hasBranch := annotations[keys.OnTargetBranch]
+	if _, hasCel := annotations[keys.OnCelExpression]; hasCel {
+		if hasEvent || hasBranch {
+			l.addAt(ruleOnCelExpression, severityWarning, doc, keys.OnCelExpression, name,
+				"the on-event and on-target-branch annotations are ignored when the on-cel-expression annotation is set")
+		}
+		return
+	}
+	switch {
+	case hasEvent && !hasBranch:
+		l.addAt(ruleOnTargetBranch, severityWarning, doc, keys.OnEvent, name,
+			"the PipelineRun will never be matched without an on-target-branch annotation")
+	case hasBranch && !hasEvent:
+		l.addAt(ruleOnEvent, severityWarning, doc, keys.OnTargetBranch, name,
+			"the PipelineRun will never be matched without an on-event annotation")
+	}
+}
+
+func validateOnEvent(value string) []string {
+	if strings.TrimSpace(value) == "[]" {
+		return []string{fmt.Sprintf("annotation %s is empty", keys.OnEvent)}
+	}
+	events, err := matcher.GetAnnotationValues(value)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	msgs := []string{}
+	for _, event := range events {
+		if !slices.Contains(onEvents, event) {
+			msgs = append(msgs, fmt.Sprintf("unknown event %q, the supported events are: %s", event, strings.Join(onEvents, ", ")))
+		}
+	}
+	return msgs
+}
+
+func validateOnTargetBranch(value string) []string {
+	if strings.TrimSpace(value) == "[]" {
+		return []string{fmt.Sprintf("annotation %s is empty", keys.OnTargetBranch)}
+	}
+	branches, err := matcher.GetAnnotationValues(value)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	msgs := []string{}
+	for _, branch := range branches {
+		if _, err := glob.Compile(branch); err != nil {
+			msgs = append(msgs, fmt.Sprintf("malformed branch glob %q: %v", branch, err))
+		}
+	}
+	return msgs
+}
+
+// checkRemoteAnnotations checks the values of the annotations the remote
+// tasks and pipeline are fetched from, the ones inside the repository must
+// exist.
+func (l *linter) checkRemoteAnnotations(doc document, name string, annotations map[string]string) {
+	for _, key := range sortedKeys(annotations) {
+		isPipeline := remotePipelineAnnotationRe.MatchString(key)
+		if !isPipeline && !remoteTaskAnnotationRe.MatchString(key) {
+			continue
+		}
+		values, err := matcher.GetAnnotationValues(annotations[key])
+		if err != nil {
+			l.addAt(ruleRemoteTask, severityError, doc, key, name, err.Error())
+			continue
+		}
+		if isPipeline && len(values) > 1 {
+			l.addAt(ruleRemoteTask, severityError, doc, key, name,
+				fmt.Sprintf("only one pipeline is allowed on remote resolution, got %d of them", len(values)))
+		}
+		for _, value := range values {
+			if msg := l.validateRemoteResource(value); msg != "" {
+				l.addAt(ruleRemoteTask, severityError, doc, key, name, msg)
+			}
+		}
+	}
+}
+
+func (l *linter) validateRemoteResource(value string) string {
+	switch {
+	case strings.HasPrefix(value, "https://"), strings.HasPrefix(value, "http://"):
+		if u, err := url.Parse(value); err != nil || u.Host == "" {
+			return fmt.Sprintf("%q is not a valid URL", value)
+		}
+	case strings.Contains(value, "://"):
+		// a task of a custom hub catalog, we cannot know the catalogs
+		// configured on the cluster.
+	case strings.Contains(value, "/"):
+		if _, err := os.Stat(filepath.Join(l.rootDir, value)); err != nil {
+			return fmt.Sprintf("%s cannot be found inside the repository", value)
+		}
+	}
+	return ""
+}
+
+// checkParams reports the {{ parameters }} which are neither standard nor
+// custom parameters, they are left as is in the PipelineRun.
+func (l *linter) checkParams(doc document) {
+	for i, line := range strings.Split(doc.content, "\n") {
+		for _, m := range keys.ParamsRe.FindAllStringSubmatch(line, -1) {
+			param := strings.TrimSpace(m[1])
+			if l.isKnownParam(param) {
+				continue
+			}
+			l.add(ruleUnresolvedParam, severityWarning, document{file: doc.file, line: doc.line + i}, "",
+				fmt.Sprintf("parameter {{ %s }} is not a standard parameter, it will be left as is unless it is a custom parameter of the Repository", param))
+		}
+	}
+}
+
+func (l *linter) isKnownParam(param string) bool {
+	if slices.Contains(standardParams, param) || slices.Contains(l.customParams, param) {
+		return true
+	}
+	for _, prefix := range celParamPrefixes {
+		if param == prefix || strings.HasPrefix(param, prefix+".") || strings.HasPrefix(param, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDuplicateNames reports the PipelineRuns with the same name across the
+// files, Pipelines-as-Code refuses to run any of them.
+func (l *linter) checkDuplicateNames() {
+	seen := map[string]namedPipelineRun{}
+	for _, pr := range l.pipelineRuns {
+		if pr.name == "" {
+			continue
+		}
+		if first, ok := seen[pr.name]; ok {
+			l.add(ruleDuplicateName, severityError, pr.doc, pr.name,
+				fmt.Sprintf("PipelineRun %s is already defined in %s:%d", pr.name, first.doc.file, first.doc.line))
+			continue
+		}
+		seen[pr.name] = pr
+	}
+}
+
+func (l *linter) add(rule, severity string, doc document, resource, message string) {
+	l.findings = append(l.findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		File:     doc.file,
+		Line:     doc.line,
+		Resource: resource,
+		Message:  message,
+	})
+}
+
+// addAt adds a finding at the line of the document where the annotation is.
+func (l *linter) addAt(rule, severity string, doc document, annotation, resource, message string) {
+	if idx := strings.Index(doc.content, annotation); idx >= 0 {
+		doc.line += strings.Count(doc.content[:idx], "\n")
+	}
+	l.add(rule, severity, doc, resource, message)
+}
+
+// splitDocuments splits the yaml documents of a file, keeping track of the
+// line they start at.
+func splitDocuments(file, content string) []document {
+	docs := []document{}
+	start := 0
+	for _, loc := range append(yamlDocSeparatorRe.FindAllStringIndex(content, -1), []int{len(content), len(content)}) {
+		doc := content[start:loc[0]]
+		line := strings.Count(content[:start], "\n") + 1
+		start = loc[1]
+		// start at the first line with some content
+		trimmed := strings.TrimLeft(doc, " \t\r\n")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		line += strings.Count(doc[:len(doc)-len(trimmed)], "\n")
+		docs = append(docs, document{file: file, line: line, content: trimmed})
+	}
+	return docs
+}
+
+func listYamlFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(fname string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (filepath.Ext(fname) == ".yaml" || filepath.Ext(fname) == ".yml") {
+				files = append(files, fname)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
@@ -4,10 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/jonboulle/clockwork"
@@ -33,7 +30,10 @@ logs - show the PipelineRun logs attached to a Repository
 
 tkn pac logs will get the logs of a PipelineRun belonging to a Repository.
 
-the PipelineRun needs to exist on the kubernetes cluster to be able to display the logs.`
+the PipelineRun needs to exist on the kubernetes cluster to be able to display the logs.
+When the pods or the TaskRuns of the PipelineRun have been deleted, the logs are
+fetched from the Tekton Results API set with --results-url or the
+PAC_TEKTON_RESULTS_URL environment variable.`
 
 const (
 	namespaceFlag          = "namespace"
@@ -42,6 +42,9 @@ const (
 	defaultLimit           = -1
 	openWebBrowserFlag     = "web"
 	useLastPipelineRunFlag = "last"
+	followFlag             = "follow"
+	taskFlag               = "task"
+	resultsURLFlag         = "results-url"
 )
 
 type logOption struct {
@@ -50,10 +53,12 @@ type logOption struct {
 	opts       *cli.PacCliOpts
 	ioStreams  *cli.IOStreams
 	repoName   string
-	tknPath    string
 	limit      int
 	webBrowser bool
 	useLastPR  bool
+	follow     bool
+	tasks      []string
+	results    *resultsClient
 }
 
 func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
@@ -100,18 +105,22 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 				return err
 			}
 
-			tknPath, err := cmd.Flags().GetString(tknPathFlag)
+			follow, err := cmd.Flags().GetBool(followFlag)
 			if err != nil {
 				return err
 			}
-			if tknPath == "" {
-				if tknPath, err = getTknPath(); err != nil {
-					return err
-				}
 
-				if tknPath == "" {
-					return fmt.Errorf("cannot find tkn binary in Path")
-				}
+			tasks, err := cmd.Flags().GetStringSlice(taskFlag)
+			if err != nil {
+				return err
+			}
+
+			resultsURL, err := cmd.Flags().GetString(resultsURLFlag)
+			if err != nil {
+				return err
+			}
+			if resultsURL == "" {
+				resultsURL = os.Getenv("PAC_TEKTON_RESULTS_URL")
 			}
 
 			lopts := &logOption{
@@ -122,8 +131,18 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 				repoName:   repoName,
 				limit:      limit,
 				webBrowser: webBrowser,
-				tknPath:    tknPath,
 				useLastPR:  useLastPR,
+				follow:     follow,
+				tasks:      tasks,
+			}
+			if resultsURL != "" {
+				lopts.results = &resultsClient{
+					client:  &run.Clients.HTTP,
+					baseURL: resultsURL,
+				}
+				if run.Clients.RESTConfig != nil {
+					lopts.results.token = run.Clients.RESTConfig.BearerToken
+				}
 			}
 			return log(ctx, lopts)
 		},
@@ -131,6 +150,7 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 
 	cmd.Flags().StringP(
 		tknPathFlag, "", "", fmt.Sprintf("Path to the %s binary (default to search for it in you $PATH)", settings.TknBinaryName))
+	_ = cmd.Flags().MarkDeprecated(tknPathFlag, "the logs are now shown without the tkn binary")
 
 	cmd.Flags().StringP(
 		namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
@@ -141,7 +161,7 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	)
 
 	cmd.Flags().BoolP(
-		openWebBrowserFlag, "w", false, "Open Web browser to detected console instead of using tkn")
+		openWebBrowserFlag, "w", false, "Open Web browser to detected console instead of showing the logs")
 
 	cmd.Flags().BoolP(
 		useLastPipelineRunFlag, "L", false, "show logs of the last PipelineRun")
@@ -149,16 +169,16 @@ func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
 	cmd.Flags().IntP(
 		limitFlag, "", defaultLimit, "Limit the number of PipelineRun to show (-1 is unlimited)")
 
-	return cmd
-}
+	cmd.Flags().BoolP(
+		followFlag, "f", false, "Follow the logs of the PipelineRun until it is done")
 
-func getTknPath() (string, error) {
-	fname, err := exec.LookPath(settings.TknBinaryName)
-	if err != nil {
-		return "", err
-	}
+	cmd.Flags().StringSliceP(
+		taskFlag, "t", []string{}, "Only show the logs of these tasks of the PipelineRun")
 
-	return filepath.Abs(fname)
+	cmd.Flags().StringP(
+		resultsURLFlag, "", "", "URL of the Tekton Results API to get the logs from when the pods have been deleted")
+
+	return cmd
 }
 
 // getPipelineRunsToRepo returns all PipelineRuns running in a namespace.
@@ -240,7 +260,7 @@ func log(ctx context.Context, lo *logOption) error {
 	if lo.webBrowser {
 		return showLogsWithWebConsole(ctx, lo, replyName)
 	}
-	return showlogswithtkn(lo.tknPath, replyName, lo.cs.Info.Kube.Namespace)
+	return newLogStreamer(lo).streamPipelineRun(ctx, lo.cs.Info.Kube.Namespace, replyName)
 }
 
 func showLogsWithWebConsole(ctx context.Context, lo *logOption, pr string) error {
@@ -256,12 +276,3 @@ func showLogsWithWebConsole(ctx context.Context, lo *logOption, pr string) error
 	}
 	return browser.OpenWebBrowser(ctx, lo.cs.Clients.ConsoleUI().DetailURL(prObj))
 }
-
-func showlogswithtkn(tknPath, pr, ns string) error {
-	//nolint: gosec
-	if err := syscall.Exec(tknPath, []string{tknPath, "pr", "logs", "-f", "-n", ns, pr}, os.Environ()); err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Command finished with error: %v", err)
-		os.Exit(127)
-	}
-	return nil
-}
@@ -1,7 +1,6 @@
 package logs
 
 import (
-	"os/exec"
 	"testing"
 
 	"github.com/jonboulle/clockwork"
@@ -33,7 +32,6 @@ func TestLogs(t *testing.T) {
 		wantErr          bool
 		repoName         string
 		currentNamespace string
-		shift            int
 		pruns            []*tektonv1.PipelineRun
 		useLastPR        bool
 	}{
@@ -42,7 +40,6 @@ func TestLogs(t *testing.T) {
 			wantErr:          false,
 			repoName:         "test",
 			currentNamespace: ns,
-			shift:            0,
 			pruns: []*tektonv1.PipelineRun{
 				tektontest.MakePRCompletion(cw, "test-pipeline", ns, completed, nil, map[string]string{
 					keys.Repository: "test",
@@ -65,14 +62,13 @@ func TestLogs(t *testing.T) {
 			},
 		},
 		{
-			name:             "bad/shift",
+			name:             "bad/pipelinerun of another repository",
 			wantErr:          true,
 			repoName:         "test",
 			currentNamespace: ns,
-			shift:            2,
 			pruns: []*tektonv1.PipelineRun{
 				tektontest.MakePRCompletion(cw, "test-pipeline", ns, completed, nil, map[string]string{
-					keys.Repository: "test",
+					keys.Repository: "other",
 				}, 30),
 			},
 		},
@@ -114,13 +110,12 @@ func TestLogs(t *testing.T) {
 				Clients: clients.Clients{
 					PipelineAsCode: stdata.PipelineAsCode,
 					Tekton:         stdata.Pipeline,
+					Kube:           stdata.Kube,
 				},
 				Info: info.Info{Kube: &info.KubeOpts{Namespace: tt.currentNamespace}},
 			}
 			cs.Clients.SetConsoleUI(consoleui.FallBackConsole{})
 
-			tknPath, err := exec.LookPath("true")
-			assert.NilError(t, err)
 			io, _ := tcli.NewIOStream()
 			lopts := &logOption{
 				cs: cs,
@@ -130,17 +125,18 @@ func TestLogs(t *testing.T) {
 				},
 				repoName:  tt.repoName,
 				limit:     1,
-				tknPath:   tknPath,
 				ioStreams: io,
 				useLastPR: tt.useLastPR,
 			}
 
-			err = log(ctx, lopts)
+			err := log(ctx, lopts)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("log() wantError is true but no error has been set")
 				}
+				return
 			}
+			assert.NilError(t, err)
 		})
 	}
 }
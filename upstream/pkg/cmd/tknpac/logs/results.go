@@ -0,0 +1,158 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+const resultsAPIPrefix = "/apis/results.tekton.dev/v1alpha2/parents/"
+
+// resultsClient fetches the logs stored by Tekton Results, which we use when
+// the pods of the TaskRuns have been garbage collected.
+type resultsClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+// resultsLogChunk is a chunk of log when the API streams them as JSON.
+type resultsLogChunk struct {
+	Result *struct {
+		Data []byte `json:"data"`
+	} `json:"result"`
+	Data []byte `json:"data"`
+}
+
+// resultsLogName returns the name of the log of a TaskRun in Tekton Results
+// from the annotations set by its watcher, the log has the same id as the
+// record of the TaskRun.
+func resultsLogName(tr *tektonv1.TaskRun) string {
+	if name := tr.GetAnnotations()[keys.ResultsLog]; name != "" {
+		return name
+	}
+	if record := tr.GetAnnotations()[keys.ResultsRecord]; strings.Contains(record, "/records/") {
+		return strings.Replace(record, "/records/", "/logs/", 1)
+	}
+	return ""
+}
+
+// resultsRecords is a page of the records of a result.
+type resultsRecords struct {
+	Records []struct {
+		Name string `json:"name"`
+		Data struct {
+			Type  string `json:"type"`
+			Value []byte `json:"value"`
+		} `json:"data"`
+	} `json:"records"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// resultsName returns the name of the result of a PipelineRun in Tekton
+// Results, it groups the records of the PipelineRun and of its TaskRuns.
+func resultsName(pr *tektonv1.PipelineRun) string {
+	if name := pr.GetAnnotations()[keys.ResultsResult]; name != "" {
+		return name
+	}
+	if name, _, ok := strings.Cut(pr.GetAnnotations()[keys.ResultsRecord], "/records/"); ok {
+		return name
+	}
+	return ""
+}
+
+func (r *resultsClient) get(ctx context.Context, name string, query url.Values) (*http.Response, error) {
+	u := strings.TrimSuffix(r.baseURL, "/") + resultsAPIPrefix + name
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// taskRuns returns the TaskRuns stored in a result, which we use when they
+// have been deleted from the cluster. Their record is set as annotation so
+// their logs can be found.
+func (r *resultsClient) taskRuns(ctx context.Context, result string) ([]tektonv1.TaskRun, error) {
+	ret := []tektonv1.TaskRun{}
+	query := url.Values{}
+	for {
+		resp, err := r.get(ctx, result+"/records", query)
+		if err != nil {
+			return nil, err
+		}
+		page := resultsRecords{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode the records: %w", err)
+		}
+		for _, record := range page.Records {
+			if !strings.HasSuffix(record.Data.Type, ".TaskRun") {
+				continue
+			}
+			tr := tektonv1.TaskRun{}
+			if err := json.Unmarshal(record.Data.Value, &tr); err != nil {
+				return nil, fmt.Errorf("cannot decode the taskrun of record %s: %w", record.Name, err)
+			}
+			if tr.Annotations == nil {
+				tr.Annotations = map[string]string{}
+			}
+			tr.Annotations[keys.ResultsRecord] = record.Name
+			ret = append(ret, tr)
+		}
+		if page.NextPageToken == "" {
+			return ret, nil
+		}
+		query.Set("page_token", page.NextPageToken)
+	}
+}
+
+func (r *resultsClient) log(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := r.get(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp.Body, nil
+	}
+
+	defer resp.Body.Close()
+	var logs bytes.Buffer
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		chunk := resultsLogChunk{}
+		if err := decoder.Decode(&chunk); err != nil {
+			return nil, fmt.Errorf("cannot decode the logs: %w", err)
+		}
+		if chunk.Result != nil {
+			logs.Write(chunk.Result.Data)
+		} else {
+			logs.Write(chunk.Data)
+		}
+	}
+	return io.NopCloser(&logs), nil
+}
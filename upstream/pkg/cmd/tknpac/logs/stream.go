@@ -0,0 +1,290 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// pollInterval is how often we check for new TaskRuns and for the steps
+	// to start when following the logs.
+	pollInterval = 2 * time.Second
+	stepPrefix   = "step-"
+	// maxLogLineSize is the longest line of log we are able to show.
+	maxLogLineSize = 1024 * 1024
+)
+
+// logStreamer writes the logs of the steps of the TaskRuns of a PipelineRun
+// from their pods, or from the Tekton Results API when the pods have been
+// deleted.
+type logStreamer struct {
+	run     *params.Run
+	cw      clockwork.Clock
+	out     io.Writer
+	errOut  io.Writer
+	cs      *cli.ColorScheme
+	follow  bool
+	tasks   []string
+	results *resultsClient
+	secrets []ktypes.SecretValue
+	colors  map[string]func(string) string
+}
+
+func newLogStreamer(lo *logOption) *logStreamer {
+	return &logStreamer{
+		run:     lo.cs,
+		cw:      lo.cw,
+		out:     lo.ioStreams.Out,
+		errOut:  lo.ioStreams.ErrOut,
+		cs:      lo.ioStreams.ColorScheme(),
+		follow:  lo.follow,
+		tasks:   lo.tasks,
+		results: lo.results,
+		colors:  map[string]func(string) string{},
+	}
+}
+
+// streamPipelineRun shows the logs of the TaskRuns of a PipelineRun one after
+// the other, in the order they have been started. When following the logs it
+// waits for the new TaskRuns until the PipelineRun is done.
+func (s *logStreamer) streamPipelineRun(ctx context.Context, ns, name string) error {
+	pr, err := s.run.Clients.Tekton.TektonV1().PipelineRuns(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if kinteract, err := kubeinteraction.NewKubernetesInteraction(s.run); err == nil {
+		s.secrets = secrets.GetSecretsAttachedToPipelineRun(ctx, kinteract, pr)
+	}
+
+	shown := map[string]bool{}
+	for {
+		taskRuns, err := s.taskRuns(ctx, pr)
+		if err != nil {
+			return err
+		}
+		for i := range taskRuns {
+			if shown[taskRuns[i].GetName()] {
+				continue
+			}
+			shown[taskRuns[i].GetName()] = true
+			if err := s.streamTaskRun(ctx, &taskRuns[i]); err != nil {
+				return err
+			}
+		}
+		if !s.follow || pr.IsDone() {
+			return nil
+		}
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+		if pr, err = s.run.Clients.Tekton.TektonV1().PipelineRuns(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+}
+
+// taskRuns returns the TaskRuns of the PipelineRun sorted by their start
+// time, only keeping the tasks we have been asked for. They are taken from
+// Tekton Results when they have been deleted from the cluster.
+func (s *logStreamer) taskRuns(ctx context.Context, pr *tektonv1.PipelineRun) ([]tektonv1.TaskRun, error) {
+	trs, err := s.run.Clients.Tekton.TektonV1().TaskRuns(pr.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", pipeline.PipelineRunLabelKey, pr.GetName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := trs.Items
+	if len(items) == 0 && pr.IsDone() {
+		if items, err = s.resultsTaskRuns(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
+	ret := []tektonv1.TaskRun{}
+	for _, tr := range items {
+		if len(s.tasks) > 0 && !slices.Contains(s.tasks, taskName(&tr)) {
+			continue
+		}
+		ret = append(ret, tr)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		si, sj := ret[i].Status.StartTime, ret[j].Status.StartTime
+		if si == nil || sj == nil {
+			return si != nil
+		}
+		return si.Before(sj)
+	})
+	return ret, nil
+}
+
+// resultsTaskRuns returns the TaskRuns of a PipelineRun from Tekton Results
+// when they have been deleted from the cluster.
+func (s *logStreamer) resultsTaskRuns(ctx context.Context, pr *tektonv1.PipelineRun) ([]tektonv1.TaskRun, error) {
+	result := resultsName(pr)
+	if s.results == nil || result == "" {
+		return nil, nil
+	}
+	trs, err := s.results.taskRuns(ctx, result)
+	if err != nil {
+		fmt.Fprintf(s.errOut, "%s cannot get the taskruns of pipelinerun %s from Tekton Results: %v\n", s.cs.WarningIcon(), pr.GetName(), err)
+		return nil, nil
+	}
+	return trs, nil
+}
+
+func (s *logStreamer) streamTaskRun(ctx context.Context, tr *tektonv1.TaskRun) error {
+	task := taskName(tr)
+	for s.follow && tr.Status.PodName == "" && !tr.IsDone() {
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+		var err error
+		if tr, err = s.run.Clients.Tekton.TektonV1().TaskRuns(tr.GetNamespace()).Get(ctx, tr.GetName(), metav1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+	if tr.Status.PodName == "" {
+		fmt.Fprintf(s.errOut, "%s task %s has no logs: %s\n", s.cs.WarningIcon(), task, taskRunMessage(tr))
+		return nil
+	}
+
+	pod, err := s.run.Clients.Kube.CoreV1().Pods(tr.GetNamespace()).Get(ctx, tr.Status.PodName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return s.streamFromResults(ctx, task, tr)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if !strings.HasPrefix(container.Name, stepPrefix) {
+			continue
+		}
+		step := strings.TrimPrefix(container.Name, stepPrefix)
+		if s.follow {
+			if err := s.waitForContainer(ctx, pod.GetNamespace(), pod.GetName(), container.Name); err != nil {
+				return err
+			}
+		}
+		stream, err := s.run.Clients.Kube.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &corev1.PodLogOptions{
+			Container: container.Name,
+			Follow:    s.follow,
+		}).Stream(ctx)
+		if err != nil {
+			fmt.Fprintf(s.errOut, "%s cannot get the logs of step %s of task %s: %v\n", s.cs.WarningIcon(), step, task, err)
+			continue
+		}
+		err = s.writeLines(stream, s.prefix(task, step))
+		stream.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamFromResults shows the logs of a TaskRun stored by Tekton Results,
+// they contain all the steps of the TaskRun.
+func (s *logStreamer) streamFromResults(ctx context.Context, task string, tr *tektonv1.TaskRun) error {
+	logName := resultsLogName(tr)
+	if s.results == nil || logName == "" {
+		fmt.Fprintf(s.errOut, "%s the pod of task %s has been deleted and its logs are not stored in Tekton Results\n", s.cs.WarningIcon(), task)
+		return nil
+	}
+	stream, err := s.results.log(ctx, logName)
+	if err != nil {
+		fmt.Fprintf(s.errOut, "%s cannot get the logs of task %s from Tekton Results: %v\n", s.cs.WarningIcon(), task, err)
+		return nil
+	}
+	defer stream.Close()
+	return s.writeLines(stream, s.prefix(task, ""))
+}
+
+// waitForContainer waits for a step to have started, or for its pod to be
+// done when it will never start.
+func (s *logStreamer) waitForContainer(ctx context.Context, ns, podName, container string) error {
+	for {
+		pod, err := s.run.Clients.Kube.CoreV1().Pods(ns).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return nil
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == container && (status.State.Running != nil || status.State.Terminated != nil) {
+				return nil
+			}
+		}
+		if err := s.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *logStreamer) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.cw.After(pollInterval):
+		return nil
+	}
+}
+
+// writeLines writes every line of a log with a prefix and the values of the
+// secrets attached to the PipelineRun replaced.
+func (s *logStreamer) writeLines(r io.Reader, prefix string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineSize)
+	for scanner.Scan() {
+		fmt.Fprintf(s.out, "%s %s\n", prefix, secrets.ReplaceSecretsInText(scanner.Text(), s.secrets))
+	}
+	return scanner.Err()
+}
+
+// prefix returns the [task : step] prefix of the log lines, with a different
+// colour for every task.
+func (s *logStreamer) prefix(task, step string) string {
+	colorFunc, ok := s.colors[task]
+	if !ok {
+		palette := []func(string) string{s.cs.Cyan, s.cs.Magenta, s.cs.Blue, s.cs.Yellow, s.cs.Green}
+		colorFunc = palette[len(s.colors)%len(palette)]
+		s.colors[task] = colorFunc
+	}
+	if step == "" {
+		return colorFunc(fmt.Sprintf("[%s]", task))
+	}
+	return colorFunc(fmt.Sprintf("[%s : %s]", task, step))
+}
+
+func taskName(tr *tektonv1.TaskRun) string {
+	if name := tr.GetLabels()[pipeline.PipelineTaskLabelKey]; name != "" {
+		return name
+	}
+	return tr.GetName()
+}
+
+func taskRunMessage(tr *tektonv1.TaskRun) string {
+	if cond := tr.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.Message != "" {
+		return cond.Message
+	}
+	return "it has not started"
+}
@@ -0,0 +1,301 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	ktypes "github.com/openshift-pipelines/pipelines-as-code/pkg/secrets/types"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestStreamPipelineRun(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	ns := "ns"
+	prName := "pr"
+	completed := tektonv1.PipelineRunReasonCompleted.String()
+
+	makeTaskRun := func(name, task, podName string, annotations map[string]string, timeshift int) *tektonv1.TaskRun {
+		tr := tektontest.MakeTaskRunCompletion(cw, name, ns, completed, annotations,
+			tektonv1.TaskRunStatusFields{PodName: podName}, knativeduckv1.Conditions{}, timeshift)
+		tr.Labels = map[string]string{
+			pipeline.PipelineRunLabelKey:  prName,
+			pipeline.PipelineTaskLabelKey: task,
+		}
+		return tr
+	}
+	makePod := func(name string, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name          string
+		taskRuns      []*tektonv1.TaskRun
+		pods          []*corev1.Pod
+		tasks         []string
+		resultsLogs   string
+		resultsJSON   bool
+		wantOutput    []string
+		notWantOutput []string
+		wantErrOutput string
+	}{
+		{
+			name: "steps of the pods in the order of the tasks",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-test", "test", "pr-test-pod", nil, 20),
+				makeTaskRun("pr-build", "build", "pr-build-pod", nil, 10),
+			},
+			pods: []*corev1.Pod{
+				makePod("pr-build-pod", "step-compile", "sidecar-registry"),
+				makePod("pr-test-pod", "step-unit", "step-e2e"),
+			},
+			wantOutput: []string{
+				"[build : compile] fake logs",
+				"[test : unit] fake logs",
+				"[test : e2e] fake logs",
+			},
+			notWantOutput: []string{"registry"},
+		},
+		{
+			name: "only the selected tasks",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-build", "build", "pr-build-pod", nil, 20),
+				makeTaskRun("pr-lint", "lint", "pr-lint-pod", nil, 10),
+			},
+			pods: []*corev1.Pod{
+				makePod("pr-build-pod", "step-compile"),
+				makePod("pr-lint-pod", "step-golangci"),
+			},
+			tasks:         []string{"lint"},
+			wantOutput:    []string{"[lint : golangci] fake logs"},
+			notWantOutput: []string{"build"},
+		},
+		{
+			name: "fall back to tekton results when the pod has been deleted",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-build", "build", "pr-build-pod", map[string]string{
+					keys.ResultsRecord: "ns/results/pruid/records/truid",
+				}, 20),
+			},
+			resultsLogs: "compiling\ndone",
+			wantOutput:  []string{"[build] compiling", "[build] done"},
+		},
+		{
+			name: "fall back to tekton results streaming json",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-build", "build", "pr-build-pod", map[string]string{
+					keys.ResultsLog: "ns/results/pruid/logs/truid",
+				}, 20),
+			},
+			resultsLogs: "compiling\ndone",
+			resultsJSON: true,
+			wantOutput:  []string{"[build] compiling", "[build] done"},
+		},
+		{
+			name: "pod deleted without tekton results",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-build", "build", "pr-build-pod", nil, 20),
+			},
+			wantErrOutput: "the pod of task build has been deleted and its logs are not stored in Tekton Results",
+		},
+		{
+			name: "taskrun without pod",
+			taskRuns: []*tektonv1.TaskRun{
+				makeTaskRun("pr-build", "build", "", nil, 20),
+			},
+			wantErrOutput: "task build has no logs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tdata := testclient.Data{
+				PipelineRuns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(cw, prName, ns, completed, nil, map[string]string{}, 30),
+				},
+				TaskRuns: tt.taskRuns,
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, tdata)
+			for _, pod := range tt.pods {
+				_, err := stdata.Kube.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			ios, _, out, errOut := cli.IOTest()
+			lopts := &logOption{
+				cs: &params.Run{
+					Clients: clients.Clients{
+						Tekton: stdata.Pipeline,
+						Kube:   stdata.Kube,
+					},
+					Info: info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+				},
+				cw:        cw,
+				ioStreams: ios,
+				tasks:     tt.tasks,
+			}
+			if tt.resultsLogs != "" {
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Assert(t, strings.HasPrefix(r.URL.Path, resultsAPIPrefix+"ns/results/pruid/logs/truid"), r.URL.Path)
+					if tt.resultsJSON {
+						w.Header().Set("Content-Type", "application/json")
+						for _, line := range strings.SplitAfter(tt.resultsLogs, "\n") {
+							b, _ := json.Marshal(map[string]any{"result": map[string]any{"data": []byte(line)}})
+							fmt.Fprintln(w, string(b))
+						}
+						return
+					}
+					fmt.Fprint(w, tt.resultsLogs)
+				}))
+				defer ts.Close()
+				lopts.results = &resultsClient{client: ts.Client(), baseURL: ts.URL}
+			}
+
+			err := newLogStreamer(lopts).streamPipelineRun(ctx, ns, prName)
+			assert.NilError(t, err)
+			got := out.String()
+			last := -1
+			for _, want := range tt.wantOutput {
+				idx := strings.Index(got, want)
+				assert.Assert(t, idx > last, "%q not found in order in %q", want, got)
+				last = idx
+			}
+			for _, notWant := range tt.notWantOutput {
+				assert.Assert(t, !strings.Contains(got, notWant), "%q found in %q", notWant, got)
+			}
+			if tt.wantErrOutput != "" {
+				assert.Assert(t, strings.Contains(errOut.String(), tt.wantErrOutput), errOut.String())
+			}
+		})
+	}
+}
+
+func TestStreamPipelineRunFromResultsRecords(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	ns := "ns"
+	completed := tektonv1.PipelineRunReasonCompleted.String()
+
+	record := func(name, dataType string, tr *tektonv1.TaskRun) map[string]any {
+		value, err := json.Marshal(tr)
+		assert.NilError(t, err)
+		return map[string]any{"name": name, "data": map[string]any{"type": dataType, "value": value}}
+	}
+	makeTaskRun := func(name, task string, timeshift int) *tektonv1.TaskRun {
+		tr := tektontest.MakeTaskRunCompletion(cw, name, ns, completed, nil,
+			tektonv1.TaskRunStatusFields{PodName: name + "-pod"}, knativeduckv1.Conditions{}, timeshift)
+		tr.Labels = map[string]string{pipeline.PipelineTaskLabelKey: task}
+		return tr
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case resultsAPIPrefix + "ns/results/pruid/records":
+			w.Header().Set("Content-Type", "application/json")
+			// the records are paginated
+			if r.URL.Query().Get("page_token") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"records": []any{
+						record("ns/results/pruid/records/pruid", "tekton.dev/v1.PipelineRun", &tektonv1.TaskRun{}),
+						record("ns/results/pruid/records/test", "tekton.dev/v1.TaskRun", makeTaskRun("pr-test", "test", 20)),
+					},
+					"nextPageToken": "next",
+				})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"records": []any{record("ns/results/pruid/records/build", "tekton.dev/v1.TaskRun", makeTaskRun("pr-build", "build", 10))},
+			})
+		case resultsAPIPrefix + "ns/results/pruid/logs/build":
+			fmt.Fprint(w, "compiling")
+		case resultsAPIPrefix + "ns/results/pruid/logs/test":
+			fmt.Fprint(w, "testing")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	for _, annotations := range []map[string]string{
+		{keys.ResultsResult: "ns/results/pruid"},
+		{keys.ResultsRecord: "ns/results/pruid/records/pruid"},
+	} {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+			PipelineRuns: []*tektonv1.PipelineRun{
+				tektontest.MakePRCompletion(cw, "pr", ns, completed, annotations, map[string]string{}, 30),
+			},
+		})
+		ios, _, out, errOut := cli.IOTest()
+		lopts := &logOption{
+			cs: &params.Run{
+				Clients: clients.Clients{Tekton: stdata.Pipeline, Kube: stdata.Kube},
+				Info:    info.Info{Kube: &info.KubeOpts{Namespace: ns}},
+			},
+			cw:        cw,
+			ioStreams: ios,
+			results:   &resultsClient{client: ts.Client(), baseURL: ts.URL},
+		}
+		assert.NilError(t, newLogStreamer(lopts).streamPipelineRun(ctx, ns, "pr"))
+		assert.Equal(t, out.String(), "[build] compiling\n[test] testing\n", errOut.String())
+	}
+}
+
+func TestWriteLinesReplaceSecrets(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := &logStreamer{
+		out:     out,
+		secrets: []ktypes.SecretValue{{Name: "token", Value: "SUPERSECRET"}},
+	}
+	assert.NilError(t, s.writeLines(strings.NewReader("login with SUPERSECRET\nok"), "[login : auth]"))
+	assert.Equal(t, out.String(), "[login : auth] login with *****\n[login : auth] ok\n")
+}
+
+func TestResultsLogName(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        string
+	}{
+		{
+			name:        "log annotation",
+			annotations: map[string]string{keys.ResultsLog: "ns/results/a/logs/b", keys.ResultsRecord: "ns/results/a/records/c"},
+			want:        "ns/results/a/logs/b",
+		},
+		{
+			name:        "record annotation",
+			annotations: map[string]string{keys.ResultsRecord: "ns/results/a/records/c"},
+			want:        "ns/results/a/logs/c",
+		},
+		{
+			name: "not stored",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &tektonv1.TaskRun{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			assert.Equal(t, resultsLogName(tr), tt.want)
+		})
+	}
+}
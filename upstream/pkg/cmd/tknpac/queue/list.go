@@ -0,0 +1,302 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/juju/ansiterm"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	allNamespacesFlag = "all-namespaces"
+	outputFlag        = "output"
+	pacNamespaceFlag  = "pac-namespace"
+)
+
+// Queue is a queue of a Repository, or of one of its concurrency groups, with
+// its running PipelineRuns and its pending ones in the order they are going
+// to start.
+type Queue struct {
+	Repository string           `json:"repository"`
+	Namespace  string           `json:"namespace"`
+	Group      string           `json:"group,omitempty"`
+	Running    []sync.QueueItem `json:"running"`
+	Pending    []sync.QueueItem `json:"pending"`
+}
+
+type listOptions struct {
+	run           *params.Run
+	ioStreams     *cli.IOStreams
+	clock         clockwork.Clock
+	namespace     string
+	allNamespaces bool
+	repoName      string
+	output        string
+	pacNamespace  string
+}
+
+func listCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	lopts := &listOptions{ioStreams: ioStreams, run: run, clock: clockwork.NewRealClock()}
+	cmd := &cobra.Command{
+		Use:     "list [repository]",
+		Aliases: []string{"ls"},
+		Short:   "List the running and pending PipelineRuns of the queues in the order they are going to start",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if lopts.namespace, err = cmd.Flags().GetString(namespaceFlag); err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				lopts.repoName = args[0]
+			}
+			if lopts.output != "" && lopts.output != "json" && lopts.output != "yaml" {
+				return fmt.Errorf("invalid output format %q, must be json or yaml", lopts.output)
+			}
+			ctx := cmd.Context()
+			if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+				return err
+			}
+			return list(ctx, lopts)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addNamespaceFlag(cmd)
+	cmd.Flags().BoolVarP(&lopts.allNamespaces, allNamespacesFlag, "A", false,
+		"list the queues of the repositories across all namespaces.")
+	cmd.Flags().StringVarP(&lopts.output, outputFlag, "o", "",
+		"output format, one of json or yaml")
+	cmd.Flags().StringVarP(&lopts.pacNamespace, pacNamespaceFlag, "", "",
+		"The namespace where pac is installed, where the order of the queues is stored")
+	return cmd
+}
+
+func list(ctx context.Context, lopts *listOptions) error {
+	run := lopts.run
+	namespace := run.Info.Kube.Namespace
+	if lopts.namespace != "" {
+		namespace = lopts.namespace
+	}
+	if lopts.allNamespaces {
+		namespace = ""
+	}
+
+	repositories := []v1alpha1.Repository{}
+	if lopts.repoName != "" {
+		repo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(namespace).Get(ctx, lopts.repoName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		repositories = append(repositories, *repo)
+	} else {
+		repos, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		repositories = repos.Items
+	}
+
+	pacNamespace := lopts.pacNamespace
+	if pacNamespace == "" {
+		// without access to the installation the queues are shown by creation time
+		pacNamespace, _, _ = params.GetInstallLocation(ctx, run)
+	}
+
+	queues := []Queue{}
+	for i := range repositories {
+		repoQueues, err := getQueues(ctx, run, &repositories[i], pacNamespace)
+		if err != nil {
+			return err
+		}
+		queues = append(queues, repoQueues...)
+	}
+
+	switch lopts.output {
+	case "json":
+		out, err := json.MarshalIndent(queues, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(lopts.ioStreams.Out, string(out))
+		return nil
+	case "yaml":
+		out, err := yaml.Marshal(queues)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(lopts.ioStreams.Out, string(out))
+		return nil
+	}
+
+	if len(queues) == 0 {
+		fmt.Fprintln(lopts.ioStreams.Out, "No PipelineRuns running or queued")
+		return nil
+	}
+	cs := lopts.ioStreams.ColorScheme()
+	w := ansiterm.NewTabWriter(lopts.ioStreams.Out, 0, 5, 3, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", cs.Underline("REPOSITORY"), cs.Underline("GROUP"), cs.Underline("POSITION"),
+		cs.Underline("PIPELINERUN"), cs.Underline("STATUS"), cs.Underline("QUEUED"))
+	for _, q := range queues {
+		repoName := q.Repository
+		if lopts.allNamespaces {
+			repoName = q.Namespace + "/" + q.Repository
+		}
+		group := q.Group
+		if group == "" {
+			group = "-"
+		}
+		for _, it := range q.Running {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", repoName, group, "-", prName(it.Key), cs.ColorStatus("Running"), queuedAge(it, lopts.clock))
+		}
+		for i, it := range q.Pending {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", repoName, group, i+1, prName(it.Key), cs.Dimmed("Pending"), queuedAge(it, lopts.clock))
+		}
+	}
+	return w.Flush()
+}
+
+func prName(prKey string) string {
+	_, name, _ := strings.Cut(prKey, "/")
+	return name
+}
+
+func queuedAge(it sync.QueueItem, clock clockwork.Clock) string {
+	return formatting.Age(&metav1.Time{Time: it.Enqueued}, clock)
+}
+
+// getQueues returns the queues of a Repository, the one of the Repository
+// first and then the ones of its concurrency groups. The order of the
+// PipelineRuns is the one saved by the watcher when the queue state can be
+// read from the installation namespace, the creation time otherwise.
+func getQueues(ctx context.Context, run *params.Run, repo *v1alpha1.Repository, pacNamespace string) ([]Queue, error) {
+	started, queued, err := queuedPipelineRuns(ctx, run, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{}
+	seen := map[string]bool{}
+	for _, pr := range append(append([]*tektonv1.PipelineRun{}, started...), queued...) {
+		group := sync.PipelineRunGroup(pr)
+		if !seen[group.Name] {
+			seen[group.Name] = true
+			groups = append(groups, group.Name)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i] == "" && groups[j] != "" })
+
+	var store sync.QueueStore
+	if pacNamespace != "" {
+		store = sync.NewConfigMapQueueStore(run.Clients.Kube, pacNamespace)
+	}
+
+	queues := []Queue{}
+	for _, group := range groups {
+		queuedInGroup := filterGroup(queued, group)
+		// without concurrency limit the started PipelineRuns are not in a queue
+		if group == "" && len(queuedInGroup) == 0 && (repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0) {
+			continue
+		}
+		q := Queue{Repository: repo.GetName(), Namespace: repo.GetNamespace(), Group: group}
+		var state *sync.QueueState
+		if store != nil {
+			// the queue state is only readable with access to the installation namespace
+			state, _ = store.Load(ctx, sync.QueueKey(repo, sync.Group{Name: group}))
+		}
+		q.Running = orderQueue(state, filterGroup(started, group), true)
+		q.Pending = orderQueue(state, queuedInGroup, false)
+		queues = append(queues, q)
+	}
+	return queues, nil
+}
+
+// queuedPipelineRuns returns the started and the pending queued PipelineRuns
+// of a Repository by creation time.
+func queuedPipelineRuns(ctx context.Context, run *params.Run, repo *v1alpha1.Repository) ([]*tektonv1.PipelineRun, []*tektonv1.PipelineRun, error) {
+	prs, err := run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", keys.Repository, formatting.CleanValueKubernetes(repo.GetName())),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	items := prs.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+	started, queued := []*tektonv1.PipelineRun{}, []*tektonv1.PipelineRun{}
+	for i := range items {
+		pr := &items[i]
+		switch pr.GetLabels()[keys.State] {
+		case kubeinteraction.StateStarted:
+			if !pr.IsDone() {
+				started = append(started, pr)
+			}
+		case kubeinteraction.StateQueued:
+			if pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+				queued = append(queued, pr)
+			}
+		}
+	}
+	return started, queued, nil
+}
+
+func filterGroup(prs []*tektonv1.PipelineRun, group string) []*tektonv1.PipelineRun {
+	filtered := []*tektonv1.PipelineRun{}
+	for _, pr := range prs {
+		if sync.PipelineRunGroup(pr).Name == group {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
+
+// orderQueue returns the PipelineRuns in the order of the saved state of the
+// queue, the ones missing from it come after by creation time.
+func orderQueue(state *sync.QueueState, prs []*tektonv1.PipelineRun, running bool) []sync.QueueItem {
+	byKey := map[string]*tektonv1.PipelineRun{}
+	for _, pr := range prs {
+		byKey[sync.PrKey(pr)] = pr
+	}
+
+	items := []sync.QueueItem{}
+	if state != nil {
+		saved := state.Pending
+		if running {
+			saved = state.Running
+		}
+		for _, it := range saved {
+			if _, ok := byKey[it.Key]; ok {
+				items = append(items, it)
+				delete(byKey, it.Key)
+			}
+		}
+	}
+	for _, pr := range prs {
+		if _, ok := byKey[sync.PrKey(pr)]; ok {
+			items = append(items, sync.QueueItem{
+				Key:      sync.PrKey(pr),
+				Priority: pr.CreationTimestamp.UnixNano(),
+				Enqueued: pr.CreationTimestamp.Time,
+			})
+		}
+	}
+	return items
+}
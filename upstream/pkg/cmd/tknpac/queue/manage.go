@@ -0,0 +1,187 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const groupFlag = "group"
+
+func promoteCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote pipelinerun",
+		Short: "Move a queued PipelineRun to the front of its queue",
+		Long: `Move a queued PipelineRun to the front of its queue, it starts as soon as
+a PipelineRun of the queue is done whatever its priority.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			namespace, err := commandNamespace(ctx, cmd, run)
+			if err != nil {
+				return err
+			}
+			return promote(ctx, run, ioStreams, namespace, args[0])
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addNamespaceFlag(cmd)
+	return cmd
+}
+
+func removeCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove pipelinerun...",
+		Aliases: []string{"rm"},
+		Short:   "Remove queued PipelineRuns from their queue by cancelling them",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			namespace, err := commandNamespace(ctx, cmd, run)
+			if err != nil {
+				return err
+			}
+			for _, name := range args {
+				if err := remove(ctx, run, ioStreams, namespace, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addNamespaceFlag(cmd)
+	return cmd
+}
+
+func clearCommand(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	var group string
+	cmd := &cobra.Command{
+		Use:   "clear repository",
+		Short: "Remove all the queued PipelineRuns of a Repository by cancelling them",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			namespace, err := commandNamespace(ctx, cmd, run)
+			if err != nil {
+				return err
+			}
+			return clearQueue(ctx, run, ioStreams, namespace, args[0], group, cmd.Flags().Changed(groupFlag))
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	addNamespaceFlag(cmd)
+	cmd.Flags().StringVarP(&group, groupFlag, "g", "",
+		"only clear the queue of this concurrency group")
+	return cmd
+}
+
+func commandNamespace(ctx context.Context, cmd *cobra.Command, run *params.Run) (string, error) {
+	namespace, err := cmd.Flags().GetString(namespaceFlag)
+	if err != nil {
+		return "", err
+	}
+	if err := run.Clients.NewClients(ctx, &run.Info); err != nil {
+		return "", err
+	}
+	if namespace == "" {
+		namespace = run.Info.Kube.Namespace
+	}
+	return namespace, nil
+}
+
+// getQueuedPipelineRun returns a PipelineRun waiting in a queue.
+func getQueuedPipelineRun(ctx context.Context, run *params.Run, namespace, name string) (*tektonv1.PipelineRun, error) {
+	pr, err := run.Clients.Tekton.TektonV1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pr.GetAnnotations()[keys.State] != kubeinteraction.StateQueued || pr.Spec.Status != tektonv1.PipelineRunSpecStatusPending {
+		return nil, fmt.Errorf("pipelinerun %s/%s is not queued", namespace, name)
+	}
+	return pr, nil
+}
+
+// promote asks the watcher to move the PipelineRun to the front of its queue
+// with the queue-promote annotation, it removes it once done.
+func promote(ctx context.Context, run *params.Run, ioStreams *cli.IOStreams, namespace, name string) error {
+	if _, err := getQueuedPipelineRun(ctx, run, namespace, name); err != nil {
+		return err
+	}
+	if err := patchPipelineRun(ctx, run, namespace, name, map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{keys.QueuePromote: time.Now().UTC().Format(time.RFC3339)},
+		},
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "%s PipelineRun %s has been promoted to the front of its queue\n", ioStreams.ColorScheme().SuccessIcon(), name)
+	return nil
+}
+
+// remove cancels a queued PipelineRun, the watcher reports it as cancelled
+// and takes it out of its queue.
+func remove(ctx context.Context, run *params.Run, ioStreams *cli.IOStreams, namespace, name string) error {
+	if _, err := getQueuedPipelineRun(ctx, run, namespace, name); err != nil {
+		return err
+	}
+	if err := cancelPipelineRun(ctx, run, namespace, name); err != nil {
+		return err
+	}
+	fmt.Fprintf(ioStreams.Out, "%s PipelineRun %s has been removed from its queue\n", ioStreams.ColorScheme().SuccessIcon(), name)
+	return nil
+}
+
+// clearQueue cancels all the queued PipelineRuns of a Repository, or only the
+// ones of a concurrency group when asked to.
+func clearQueue(ctx context.Context, run *params.Run, ioStreams *cli.IOStreams, namespace, repoName, group string, onlyGroup bool) error {
+	repo, err := run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(namespace).Get(ctx, repoName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	_, queued, err := queuedPipelineRuns(ctx, run, repo)
+	if err != nil {
+		return err
+	}
+	if onlyGroup {
+		queued = filterGroup(queued, group)
+	}
+	for _, pr := range queued {
+		if err := cancelPipelineRun(ctx, run, pr.GetNamespace(), pr.GetName()); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(ioStreams.Out, "%s %d queued PipelineRun(s) of repository %s have been removed\n", ioStreams.ColorScheme().SuccessIcon(), len(queued), repoName)
+	return nil
+}
+
+func cancelPipelineRun(ctx context.Context, run *params.Run, namespace, name string) error {
+	return patchPipelineRun(ctx, run, namespace, name, map[string]any{
+		"spec": map[string]any{"status": tektonv1.PipelineRunSpecStatusCancelled},
+	})
+}
+
+func patchPipelineRun(ctx context.Context, run *params.Run, namespace, name string, mergePatch map[string]any) error {
+	patch, err := json.Marshal(mergePatch)
+	if err != nil {
+		return err
+	}
+	_, err = run.Clients.Tekton.TektonV1().PipelineRuns(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
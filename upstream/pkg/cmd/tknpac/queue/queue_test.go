@@ -0,0 +1,196 @@
+package queue
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"sigs.k8s.io/yaml"
+)
+
+const testNamespace = "namespace"
+
+func newIOStream() (*cli.IOStreams, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &cli.IOStreams{
+		In:     io.NopCloser(&bytes.Buffer{}),
+		Out:    out,
+		ErrOut: &bytes.Buffer{},
+	}, out
+}
+
+func newQueueTestPR(name, state string, created time.Time, annotations map[string]string) *tektonv1.PipelineRun {
+	pr := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.Time{Time: created},
+			Labels:            map[string]string{keys.Repository: "repo", keys.State: state},
+			Annotations:       map[string]string{keys.Repository: "repo", keys.State: state},
+		},
+	}
+	for k, v := range annotations {
+		pr.Annotations[k] = v
+	}
+	if state == kubeinteraction.StateQueued {
+		pr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+	}
+	return pr
+}
+
+func seedQueue(t *testing.T) (*params.Run, *pacv1alpha1.Repository, testclient.Clients) {
+	t.Helper()
+	ctx, _ := rtesting.SetupFakeContext(t)
+	created := time.Date(1999, time.February, 3, 4, 5, 6, 0, time.UTC)
+	limit := 1
+	repo := &pacv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: testNamespace},
+		Spec:       pacv1alpha1.RepositorySpec{URL: "https://anywhere.com/owner/repo", ConcurrencyLimit: &limit},
+	}
+	unlimited := &pacv1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "unlimited", Namespace: testNamespace},
+		Spec:       pacv1alpha1.RepositorySpec{URL: "https://anywhere.com/owner/unlimited"},
+	}
+	started := newQueueTestPR("unlimited-run", kubeinteraction.StateStarted, created, nil)
+	started.Labels[keys.Repository] = "unlimited"
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*pacv1alpha1.Repository{repo, unlimited},
+		PipelineRuns: []*tektonv1.PipelineRun{
+			newQueueTestPR("first", kubeinteraction.StateStarted, created, nil),
+			newQueueTestPR("second", kubeinteraction.StateQueued, created.Add(time.Second), nil),
+			newQueueTestPR("third", kubeinteraction.StateQueued, created.Add(2*time.Second), nil),
+			newQueueTestPR("deploy", kubeinteraction.StateQueued, created.Add(3*time.Second), map[string]string{keys.ConcurrencyGroup: "deploy"}),
+			started,
+		},
+	})
+	run := &params.Run{
+		Clients: clients.Clients{
+			PipelineAsCode: stdata.PipelineAsCode,
+			Tekton:         stdata.Pipeline,
+			Kube:           stdata.Kube,
+		},
+		Info: info.Info{Kube: &info.KubeOpts{Namespace: testNamespace}},
+	}
+	return run, repo, stdata
+}
+
+func TestList(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	run, repo, stdata := seedQueue(t)
+
+	// the third has been promoted by the watcher
+	store := sync.NewConfigMapQueueStore(stdata.Kube, "pac")
+	assert.NilError(t, store.Save(ctx, sync.RepoKey(repo), &sync.QueueState{
+		Running: []sync.QueueItem{{Key: "namespace/first", Priority: 1}},
+		Pending: []sync.QueueItem{{Key: "namespace/third", Priority: 2}, {Key: "namespace/second", Priority: 3}},
+	}))
+
+	tests := []struct {
+		name         string
+		pacNamespace string
+		wantPending  []string
+	}{
+		{
+			name:         "saved order",
+			pacNamespace: "pac",
+			wantPending:  []string{"namespace/third", "namespace/second"},
+		},
+		{
+			name:        "creation order without access to the saved order",
+			wantPending: []string{"namespace/second", "namespace/third"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, output := range []string{"json", "yaml"} {
+				ioStreams, out := newIOStream()
+				assert.NilError(t, list(ctx, &listOptions{run: run, ioStreams: ioStreams, output: output, pacNamespace: tt.pacNamespace}))
+				queues := []Queue{}
+				if output == "json" {
+					assert.NilError(t, json.Unmarshal(out.Bytes(), &queues))
+				} else {
+					assert.NilError(t, yaml.Unmarshal(out.Bytes(), &queues))
+				}
+				// the repository without limit has no queue
+				assert.Equal(t, len(queues), 2)
+				assert.Equal(t, queues[0].Repository, "repo")
+				assert.Equal(t, queues[0].Group, "")
+				assert.Equal(t, queues[0].Running[0].Key, "namespace/first")
+				pending := []string{}
+				for _, it := range queues[0].Pending {
+					pending = append(pending, it.Key)
+				}
+				assert.DeepEqual(t, pending, tt.wantPending)
+				assert.Equal(t, queues[1].Group, "deploy")
+				assert.Equal(t, queues[1].Pending[0].Key, "namespace/deploy")
+			}
+		})
+	}
+
+	ioStreams, out := newIOStream()
+	assert.NilError(t, list(ctx, &listOptions{run: run, ioStreams: ioStreams, clock: clockwork.NewFakeClock(), pacNamespace: "pac", repoName: "repo"}))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 5)
+	assert.Assert(t, strings.Contains(lines[1], "first") && strings.Contains(lines[1], "Running"))
+	assert.Assert(t, strings.Contains(lines[2], "1") && strings.Contains(lines[2], "third"))
+	assert.Assert(t, strings.Contains(lines[3], "2") && strings.Contains(lines[3], "second"))
+	assert.Assert(t, strings.Contains(lines[4], "deploy"))
+}
+
+func TestPromote(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	run, _, _ := seedQueue(t)
+	ioStreams, out := newIOStream()
+
+	assert.NilError(t, promote(ctx, run, ioStreams, testNamespace, "third"))
+	assert.Assert(t, strings.Contains(out.String(), "promoted"))
+	pr, err := run.Clients.Tekton.TektonV1().PipelineRuns(testNamespace).Get(ctx, "third", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, ok := pr.GetAnnotations()[keys.QueuePromote]
+	assert.Assert(t, ok)
+
+	assert.ErrorContains(t, promote(ctx, run, ioStreams, testNamespace, "first"), "is not queued")
+}
+
+func TestRemoveAndClear(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	run, _, _ := seedQueue(t)
+	ioStreams, _ := newIOStream()
+
+	status := func(name string) tektonv1.PipelineRunSpecStatus {
+		pr, err := run.Clients.Tekton.TektonV1().PipelineRuns(testNamespace).Get(ctx, name, metav1.GetOptions{})
+		assert.NilError(t, err)
+		return pr.Spec.Status
+	}
+
+	assert.NilError(t, remove(ctx, run, ioStreams, testNamespace, "second"))
+	assert.Equal(t, status("second"), tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelled))
+	assert.Equal(t, status("third"), tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusPending))
+	assert.ErrorContains(t, remove(ctx, run, ioStreams, testNamespace, "first"), "is not queued")
+
+	// only the queue of the group
+	assert.NilError(t, clearQueue(ctx, run, ioStreams, testNamespace, "repo", "deploy", true))
+	assert.Equal(t, status("deploy"), tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelled))
+	assert.Equal(t, status("third"), tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusPending))
+
+	assert.NilError(t, clearQueue(ctx, run, ioStreams, testNamespace, "repo", "", false))
+	assert.Equal(t, status("third"), tektonv1.PipelineRunSpecStatus(tektonv1.PipelineRunSpecStatusCancelled))
+	assert.Equal(t, status("first"), tektonv1.PipelineRunSpecStatus(""))
+}
@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/completion"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/spf13/cobra"
+)
+
+var namespaceFlag = "namespace"
+
+func Root(clients *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "queue",
+		Aliases:      []string{"q"},
+		Short:        "Inspect and manage the concurrency queues",
+		Long:         `Inspect and manage the queues of the PipelineRuns waiting for their turn with the concurrency limits`,
+		SilenceUsage: true,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	cmd.AddCommand(listCommand(clients, ioStreams))
+	cmd.AddCommand(promoteCommand(clients, ioStreams))
+	cmd.AddCommand(removeCommand(clients, ioStreams))
+	cmd.AddCommand(clearCommand(clients, ioStreams))
+	return cmd
+}
+
+func addNamespaceFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP(
+		namespaceFlag, "n", "", "If present, the namespace scope for this CLI request")
+	_ = cmd.RegisterFlagCompletionFunc(namespaceFlag,
+		func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return completion.BaseCompletion(namespaceFlag, args)
+		},
+	)
+}
@@ -0,0 +1,51 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/changedfiles"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// simulatedProvider wraps the provider detected from the payload so the
+// matcher and the resolver never call the API of the git provider: the changed
+// files come from the command line, the files of the repository from the local
+// checkout and nothing is ever created.
+type simulatedProvider struct {
+	provider.Interface
+	rootDir      string
+	changedFiles []string
+}
+
+func (s *simulatedProvider) GetFiles(_ context.Context, _ *info.Event) (changedfiles.ChangedFiles, error) {
+	if s.changedFiles == nil {
+		return changedfiles.ChangedFiles{}, fmt.Errorf("the changed files are not known without the provider API, set them with --%s", changedFilesFlag)
+	}
+	return changedfiles.ChangedFiles{All: s.changedFiles, Modified: s.changedFiles}, nil
+}
+
+func (s *simulatedProvider) GetFileInsideRepo(_ context.Context, _ *info.Event, path, _ string) (string, error) {
+	fpath := filepath.Join(s.rootDir, filepath.FromSlash(strings.TrimPrefix(path, "./")))
+	b, err := os.ReadFile(fpath)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s in the local checkout: %w", path, err)
+	}
+	return string(b), nil
+}
+
+func (s *simulatedProvider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
+	return false, "", nil
+}
+
+func (s *simulatedProvider) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
+	return nil
+}
+
+func (s *simulatedProvider) CreateStatus(_ context.Context, _ *info.Event, _ provider.StatusOpts) error {
+	return nil
+}
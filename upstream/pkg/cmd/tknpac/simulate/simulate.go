@@ -0,0 +1,396 @@
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cmd/tknpac/cel"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/customparams"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	pacversioned "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/git"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/templates"
+	"github.com/spf13/cobra"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	tektonversioned "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"go.uber.org/zap"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	payloadFlag      = "payload"
+	headersFlag      = "headers"
+	dirFlag          = "dir"
+	changedFilesFlag = "changed-files"
+	remoteTasksFlag  = "remote-tasks"
+	defaultTekton    = ".tekton"
+)
+
+var longhelp = fmt.Sprintf(`simulate - replay a webhook through the matcher locally.
+
+Run a webhook payload through the same code as the Pipelines-as-Code
+controller: the git provider is detected from the headers, the payload is
+parsed into an event which is matched against the PipelineRuns of the local
+.tekton directory.
+
+For each PipelineRun the annotations or the CEL expression which have matched
+or rejected it are reported, the matching PipelineRuns are then printed fully
+resolved:
+
+%s pac simulate --payload event.json --headers headers.txt
+
+The headers can be in JSON, in the plain HTTP format or the shell script
+generated by gosmee, the same way as "%s pac cel".
+
+Nothing is ever created: the API of the git provider is not called, the
+changed files used by the on-path-change annotations and the pathChanged() CEL
+function have to be given with --%s. The GitHub App token is not generated
+so the events needing the API, like the comments on GitHub, cannot be
+simulated. When connected to a cluster the Repository matching the event and
+its custom parameters are used, nothing is created there either.`, settings.TknBinaryName, settings.TknBinaryName, changedFilesFlag)
+
+type simulateOptions struct {
+	ioStreams    *cli.IOStreams
+	payloadFile  string
+	headersFile  string
+	tektonDir    string
+	changedFiles []string
+	remoteTasks  bool
+	rootDir      string
+	// connected is true when we have access to a cluster
+	connected bool
+}
+
+func Command(run *params.Run, ioStreams *cli.IOStreams) *cobra.Command {
+	opts := &simulateOptions{ioStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay a webhook payload through the matcher against the local .tekton directory",
+		Long:  longhelp,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if opts.payloadFile == "" || opts.headersFile == "" {
+				return fmt.Errorf("the --%s and --%s flags are required", payloadFlag, headersFlag)
+			}
+			ctx := cmd.Context()
+			if err := setupClients(ctx, run, opts); err != nil {
+				return err
+			}
+			if opts.rootDir = git.GetGitInfo(".").TopLevelPath; opts.rootDir == "" {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				opts.rootDir = cwd
+			}
+			return simulate(ctx, run, opts)
+		},
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+	cmd.Flags().StringVar(&opts.payloadFile, payloadFlag, "", "path to the JSON payload of the webhook")
+	cmd.Flags().StringVar(&opts.headersFile, headersFlag, "", "path to the headers of the webhook (JSON, HTTP format, or gosmee-generated shell script)")
+	cmd.Flags().StringVar(&opts.tektonDir, dirFlag, defaultTekton, "directory with the PipelineRuns to match")
+	cmd.Flags().StringSliceVar(&opts.changedFiles, changedFilesFlag, nil, "files changed by the event, used by the on-path-change annotations and the pathChanged() CEL function")
+	cmd.Flags().BoolVar(&opts.remoteTasks, remoteTasksFlag, false, "fetch the remote tasks and pipelines of the matching PipelineRuns")
+	return cmd
+}
+
+// setupClients connects to the cluster when there is a kubeconfig, without
+// it empty clients are used so the lookups on the cluster find nothing.
+func setupClients(ctx context.Context, run *params.Run, opts *simulateOptions) error {
+	errc := run.Clients.NewClients(ctx, &run.Info)
+	// only report error here on CLI
+	zaplog, err := zap.NewProduction(zap.IncreaseLevel(zap.FatalLevel))
+	if err != nil {
+		return err
+	}
+	run.Clients.Log = zaplog.Sugar()
+	if errc != nil {
+		if !strings.Contains(errc.Error(), "Couldn't get kubeConfiguration namespace") {
+			return errc
+		}
+		run.Clients.Kube = kubefake.NewSimpleClientset()
+		run.Clients.Tekton = tektonversioned.NewSimpleClientset()
+		run.Clients.PipelineAsCode = pacversioned.NewSimpleClientset()
+	} else {
+		opts.connected = true
+		// it's OK if pac is not installed, ignore the error
+		_ = run.UpdatePacConfig(ctx)
+	}
+	return settings.SyncConfig(run.Clients.Log, &run.Info.Pac.Settings, map[string]string{}, settings.DefaultValidators())
+}
+
+func simulate(ctx context.Context, run *params.Run, opts *simulateOptions) error {
+	out, errOut := opts.ioStreams.Out, opts.ioStreams.ErrOut
+	cs := opts.ioStreams.ColorScheme()
+	logger := run.Clients.Log
+
+	payload, err := os.ReadFile(opts.payloadFile)
+	if err != nil {
+		return err
+	}
+	headers, err := cel.ReadHeadersFile(opts.headersFile)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	vcx, err := detectProvider(run, req, string(payload), logger)
+	if err != nil {
+		return err
+	}
+	vcx.SetPacInfo(run.Info.Pac)
+	parsedPayload := payload
+	if _, ok := vcx.(*github.Provider); ok {
+		var stripped bool
+		if parsedPayload, stripped, err = stripInstallation(payload); err != nil {
+			return err
+		}
+		if stripped {
+			fmt.Fprintf(errOut, "%s the GitHub App token is not generated, the events needing the GitHub API cannot be simulated\n", cs.WarningIcon())
+		}
+	}
+	event, err := vcx.ParsePayload(ctx, run, req, string(parsedPayload))
+	if err != nil {
+		return fmt.Errorf("cannot parse the payload: %w", err)
+	}
+	event.Request = &info.Request{Header: req.Header, Payload: bytes.TrimSpace(payload)}
+	simulated := &simulatedProvider{Interface: vcx, rootDir: opts.rootDir, changedFiles: opts.changedFiles}
+
+	fmt.Fprintf(out, "%s %s event on %s: target branch %s, source branch %s, sha %s\n",
+		cs.Bold("Event:"), event.EventType, event.URL, event.BaseBranch, event.HeadBranch, event.SHA)
+
+	repo, _ := matcher.MatchEventURLRepo(ctx, run, event, "")
+	switch {
+	case repo != nil:
+		fmt.Fprintf(out, "%s %s/%s\n", cs.Bold("Repository:"), repo.GetNamespace(), repo.GetName())
+	case opts.connected:
+		fmt.Fprintf(errOut, "%s no Repository matching %s has been found on the cluster, the event would be ignored\n", cs.WarningIcon(), event.URL)
+	}
+	if repo == nil {
+		repo = &v1alpha1.Repository{}
+	}
+
+	rawTemplates, err := readTektonDir(opts.tektonDir)
+	if err != nil {
+		return err
+	}
+	eventEmitter := events.NewEventEmitter(kubefake.NewSimpleClientset(), logger)
+
+	// like the controller, match a first time on the raw templates to expand
+	// the dynamic event type of the gitops comments
+	if event.TargetTestPipelineRun == "" {
+		rtypes, err := resolve.ReadTektonTypes(ctx, logger, rawTemplates)
+		if err != nil {
+			return err
+		}
+		_, _ = matcher.MatchPipelinerunByAnnotation(ctx, logger, rtypes.PipelineRuns, run, event, simulated, eventEmitter, repo)
+	}
+
+	var kint kubeinteraction.Interface
+	if opts.connected {
+		if kint, err = kubeinteraction.NewKubernetesInteraction(run); err != nil {
+			return err
+		}
+	}
+	cp := customparams.NewCustomParams(event, repo, run, kint, eventEmitter, simulated)
+	maptemplate, changedFiles, err := cp.GetParams(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "%s cannot process the custom params of the Repository: %s\n", cs.WarningIcon(), err.Error())
+	}
+	if event.PullRequestNumber != 0 {
+		maptemplate["pull_request_number"] = fmt.Sprintf("%d", event.PullRequestNumber)
+	}
+	allTemplates := templates.ReplacePlaceHoldersVariables(rawTemplates, maptemplate, event.Event, event.Request.Header, changedFiles)
+
+	types, err := resolve.ReadTektonTypes(ctx, logger, allTemplates)
+	if err != nil {
+		return err
+	}
+	for _, verr := range types.ValidationErrors {
+		fmt.Fprintf(errOut, "%s %s: %s\n", cs.WarningIcon(), verr.Name, verr.Err.Error())
+	}
+	if len(types.PipelineRuns) == 0 {
+		return fmt.Errorf("cannot find any PipelineRun in %s", opts.tektonDir)
+	}
+	if _, err := resolve.MetadataResolve(types.PipelineRuns); err != nil {
+		return err
+	}
+
+	var matched []*tektonv1.PipelineRun
+	if event.TargetTestPipelineRun != "" {
+		for _, prun := range types.PipelineRuns {
+			if prun.GetAnnotations()[keys.OriginalPRName] == event.TargetTestPipelineRun {
+				matched = append(matched, prun)
+			}
+		}
+		fmt.Fprintf(out, "\nThe comment explicitly targets the PipelineRun %s with /test\n", event.TargetTestPipelineRun)
+	} else {
+		matches, report, _ := matcher.MatchPipelinerunByAnnotationWithReport(ctx, logger, types.PipelineRuns, run, event, simulated, eventEmitter, repo)
+		printReport(opts.ioStreams, report)
+		for _, match := range matches {
+			matched = append(matched, match.PipelineRun)
+		}
+	}
+	if len(matched) == 0 {
+		fmt.Fprintf(out, "\n%s no PipelineRun would be triggered by this event\n", cs.FailureIcon())
+		return nil
+	}
+
+	types.PipelineRuns = matched
+	pruns, err := resolve.Resolve(ctx, run, logger, simulated, types, event, &resolve.Opts{
+		GenerateName: true,
+		RemoteTasks:  opts.remoteTasks,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot resolve the matching PipelineRuns: %w", err)
+	}
+	fmt.Fprintln(out)
+	return printPipelineRuns(out, pruns)
+}
+
+// detectProvider detects the git provider in the same order as the
+// controller.
+func detectProvider(run *params.Run, req *http.Request, payload string, logger *zap.SugaredLogger) (provider.Interface, error) {
+	gitHub := github.New()
+	gitHub.Run = run
+	for _, vcx := range []provider.Interface{gitHub, &gitea.Provider{}, &bitbucketdatacenter.Provider{}, &gitlab.Provider{}, &bitbucketcloud.Provider{}} {
+		isProvider, processReq, plogger, reason, err := vcx.Detect(req, payload, logger)
+		if !isProvider {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !processReq {
+			if reason == "" {
+				reason = "unsupported event"
+			}
+			return nil, fmt.Errorf("the event is skipped by Pipelines-as-Code: %s", reason)
+		}
+		vcx.SetLogger(plogger)
+		return vcx, nil
+	}
+	return nil, fmt.Errorf("no supported Git provider has been detected from the headers and the payload")
+}
+
+// stripInstallation removes the GitHub App installation from the payload so
+// no token is generated when parsing it.
+func stripInstallation(payload []byte) ([]byte, bool, error) {
+	data := map[string]any{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, false, fmt.Errorf("invalid event body format: %w", err)
+	}
+	if _, ok := data["installation"]; !ok {
+		return payload, false, nil
+	}
+	delete(data, "installation")
+	b, err := json.Marshal(data)
+	return b, true, err
+}
+
+// readTektonDir concatenates the YAML files of the directory like the
+// providers do when reading the .tekton directory of the repository.
+func readTektonDir(dir string) (string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	var all strings.Builder
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		all.WriteString("---\n")
+		all.Write(b)
+		all.WriteString("\n")
+	}
+	return all.String(), nil
+}
+
+func printReport(ioStreams *cli.IOStreams, report *matcher.MatchReport) {
+	cs := ioStreams.ColorScheme()
+	for _, prReport := range report.PipelineRuns {
+		status := fmt.Sprintf("%s %s did not match", cs.FailureIcon(), cs.Bold(prReport.Name))
+		if prReport.Matched {
+			status = fmt.Sprintf("%s %s matched", cs.SuccessIcon(), cs.Bold(prReport.Name))
+		}
+		fmt.Fprintf(ioStreams.Out, "\n%s\n", status)
+		for _, check := range prReport.Checks {
+			icon := cs.FailureIcon()
+			if check.Matched {
+				icon = cs.SuccessIcon()
+			}
+			annotation := ""
+			if check.Annotation != "" {
+				annotation = check.Annotation + ": "
+				if check.Value != "" {
+					annotation = fmt.Sprintf("%s %s: ", check.Annotation, cs.Dimmed(check.Value))
+				}
+			}
+			fmt.Fprintf(ioStreams.Out, "  %s %s%s\n", icon, annotation, check.Message)
+		}
+	}
+}
+
+// cleanRe removes the empty fields of the marshalled PipelineRuns like the
+// resolve command.
+var cleanRe = regexp.MustCompile(`\n(\t|\s)*(status|taskRunTemplate|creationTimestamp|spec|taskRunTemplate|metadata|computeResources):\s*(null|{})\n`)
+
+func printPipelineRuns(out io.Writer, pruns []*tektonv1.PipelineRun) error {
+	for _, prun := range pruns {
+		prun.APIVersion = tektonv1.SchemeGroupVersion.String()
+		prun.Kind = "PipelineRun"
+		prun.SetNamespace("")
+		doc, err := yaml.Marshal(prun)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "---\n%s", cleanRe.ReplaceAllString(string(doc), "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package simulate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cli"
+	pacversioned "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned/fake"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tektonversioned "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/golden"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const tektonDir = `apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pull-request
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+    pipelinesascode.tekton.dev/on-path-change: "[docs/**]"
+spec:
+  params:
+    - name: revision
+      value: "{{ revision }}"
+  pipelineSpec:
+    tasks:
+      - name: hello
+        taskRef:
+          name: hello
+---
+apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: hello
+spec:
+  steps:
+    - name: hello
+      image: alpine
+      script: echo {{ repo_name }}
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: push
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+spec:
+  pipelineSpec:
+    tasks: []
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: cel
+  annotations:
+    pipelinesascode.tekton.dev/on-cel-expression: event == "pull_request" && source_branch == "main"
+spec:
+  pipelineSpec:
+    tasks: []
+`
+
+const pullRequestPayload = `{
+  "action": "opened",
+  "installation": {"id": 1234},
+  "pull_request": {
+    "number": 5,
+    "title": "Update the docs",
+    "head": {"ref": "docs", "sha": "0123456789abcdef", "repo": {"html_url": "https://github.com/owner/repo"}},
+    "base": {"ref": "main", "repo": {"html_url": "https://github.com/owner/repo"}},
+    "user": {"login": "user"}
+  },
+  "repository": {"name": "repo", "html_url": "https://github.com/owner/repo", "default_branch": "main", "owner": {"login": "owner"}},
+  "sender": {"login": "user"}
+}`
+
+const pushPayload = `{
+  "ref": "refs/heads/main",
+  "after": "0123456789abcdef",
+  "head_commit": {"id": "0123456789abcdef", "message": "Update the docs"},
+  "pusher": {"name": "user"},
+  "repository": {"name": "repo", "html_url": "https://github.com/owner/repo", "default_branch": "main", "owner": {"login": "owner"}},
+  "sender": {"login": "user"}
+}`
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		headers      string
+		changedFiles []string
+		wantErr      string
+	}{
+		{
+			name:         "pull request",
+			payload:      pullRequestPayload,
+			headers:      "X-GitHub-Event: pull_request\nContent-Type: application/json\n",
+			changedFiles: []string{"docs/index.md"},
+		},
+		{
+			name:    "pull request without changed files",
+			payload: pullRequestPayload,
+			headers: `{"X-GitHub-Event": "pull_request"}`,
+		},
+		{
+			name:    "push",
+			payload: pushPayload,
+			headers: `{"X-GitHub-Event": "push"}`,
+		},
+		{
+			name:    "skipped event",
+			payload: `{"action": "created"}`,
+			headers: `{"X-GitHub-Event": "star"}`,
+			wantErr: "the event is skipped by Pipelines-as-Code",
+		},
+		{
+			name:    "unknown provider",
+			payload: `{}`,
+			headers: `{"X-Unknown-Event": "push"}`,
+			wantErr: "no supported Git provider has been detected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			tmpdir := fs.NewDir(t, "TestSimulate",
+				fs.WithFile("payload.json", tt.payload),
+				fs.WithFile("headers.txt", tt.headers),
+				fs.WithDir(".tekton", fs.WithFile("pipelineruns.yaml", tektonDir)),
+			)
+			run := &params.Run{Info: info.NewInfo()}
+			run.Clients.Log = zap.NewNop().Sugar()
+			run.Clients.Kube = kubefake.NewSimpleClientset()
+			run.Clients.Tekton = tektonversioned.NewSimpleClientset()
+			run.Clients.PipelineAsCode = pacversioned.NewSimpleClientset()
+
+			ios, _, out, errOut := cli.IOTest()
+			opts := &simulateOptions{
+				ioStreams:    ios,
+				payloadFile:  filepath.Join(tmpdir.Path(), "payload.json"),
+				headersFile:  filepath.Join(tmpdir.Path(), "headers.txt"),
+				tektonDir:    filepath.Join(tmpdir.Path(), ".tekton"),
+				changedFiles: tt.changedFiles,
+				rootDir:      tmpdir.Path(),
+			}
+			err := simulate(ctx, run, opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			golden.Assert(t, out.String()+errOut.String(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+		})
+	}
+}
+
+func TestStripInstallation(t *testing.T) {
+	out, stripped, err := stripInstallation([]byte(`{"installation": {"id": 1}, "action": "opened"}`))
+	assert.NilError(t, err)
+	assert.Assert(t, stripped)
+	assert.Equal(t, string(out), `{"action":"opened"}`)
+
+	_, stripped, err = stripInstallation([]byte(`{"action": "opened"}`))
+	assert.NilError(t, err)
+	assert.Assert(t, !stripped)
+
+	_, _, err = stripInstallation([]byte(`{`))
+	assert.ErrorContains(t, err, "invalid event body format")
+}
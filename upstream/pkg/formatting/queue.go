@@ -0,0 +1,35 @@
+package formatting
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Ordinal returns the English ordinal of a number, like 1st or 12th.
+func Ordinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// QueuePosition describes the position of a PipelineRun waiting in a queue,
+// the estimate is left out when it is unknown.
+func QueuePosition(position, total int, estimate time.Duration) string {
+	msg := fmt.Sprintf("%s of %d waiting", Ordinal(position), total)
+	if estimate <= 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s, ~%d min estimated", msg, int(math.Max(1, math.Round(estimate.Minutes()))))
+}
@@ -0,0 +1,50 @@
+package formatting
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 111: "111th"} {
+		assert.Equal(t, Ordinal(n), want)
+	}
+}
+
+func TestQueuePosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		total    int
+		estimate time.Duration
+		want     string
+	}{
+		{
+			name:     "without estimate",
+			position: 3,
+			total:    7,
+			want:     "3rd of 7 waiting",
+		},
+		{
+			name:     "with estimate",
+			position: 3,
+			total:    7,
+			estimate: 12*time.Minute + 20*time.Second,
+			want:     "3rd of 7 waiting, ~12 min estimated",
+		},
+		{
+			name:     "less than a minute",
+			position: 1,
+			total:    1,
+			estimate: 10 * time.Second,
+			want:     "1st of 1 waiting, ~1 min estimated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, QueuePosition(tt.position, tt.total, tt.estimate), tt.want)
+		})
+	}
+}
@@ -34,6 +34,7 @@ type MessageTemplate struct {
 	TknBinaryURL    string
 	TaskStatus      string
 	FailureSnippet  string
+	QueuePosition   string
 }
 
 func (mt MessageTemplate) MakeTemplate(tmpl string) (string, error) {
@@ -41,22 +41,35 @@ func (k Interaction) CleanupPipelines(ctx context.Context, logger *zap.SugaredLo
 
 		if c >= maxKeep {
 			logger.Infof("cleaning old PipelineRun: %s", prun.GetName())
-			err := k.Run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).Delete(
-				ctx, prun.GetName(), metav1.DeleteOptions{})
-			if err != nil {
+			if err := k.deletePipelineRun(ctx, logger, &prun); err != nil {
 				return err
 			}
+		}
+	}
 
-			// Try to Delete the secret created for git-clone basic-auth, it should have been created with a ownerRef on the pipelinerun and due being deleted when the pipelinerun is deleted
-			// but in some cases of conflicts and the ownerRef not being set, the secret is not deleted, and we need to delete it manually.
-			if secretName, ok := prun.GetAnnotations()[keys.GitAuthSecret]; ok {
-				err = k.Run.Clients.Kube.CoreV1().Secrets(repo.GetNamespace()).Delete(ctx, secretName, metav1.DeleteOptions{})
-				if err == nil {
-					logger.Infof("secret %s attached to pipelinerun %s has been deleted", secretName, prun.GetName())
-				}
-			}
+	return nil
+}
+
+func (k Interaction) deletePipelineRun(ctx context.Context, logger *zap.SugaredLogger, prun *tektonv1.PipelineRun) error {
+	if k.Archiver != nil {
+		if err := k.Archiver.Archive(ctx, logger, prun); err != nil {
+			return fmt.Errorf("cannot archive PipelineRun %s before deleting it: %w", prun.GetName(), err)
 		}
 	}
 
+	err := k.Run.Clients.Tekton.TektonV1().PipelineRuns(prun.GetNamespace()).Delete(
+		ctx, prun.GetName(), metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+
+	// Try to Delete the secret created for git-clone basic-auth, it should have been created with a ownerRef on the pipelinerun and due being deleted when the pipelinerun is deleted
+	// but in some cases of conflicts and the ownerRef not being set, the secret is not deleted, and we need to delete it manually.
+	if secretName, ok := prun.GetAnnotations()[keys.GitAuthSecret]; ok {
+		err = k.Run.Clients.Kube.CoreV1().Secrets(prun.GetNamespace()).Delete(ctx, secretName, metav1.DeleteOptions{})
+		if err == nil {
+			logger.Infof("secret %s attached to pipelinerun %s has been deleted", secretName, prun.GetName())
+		}
+	}
 	return nil
 }
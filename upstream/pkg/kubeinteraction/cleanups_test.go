@@ -1,6 +1,8 @@
 package kubeinteraction
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/jonboulle/clockwork"
@@ -20,6 +22,19 @@ import (
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
+type fakeArchiver struct {
+	archived []string
+	err      error
+}
+
+func (f *fakeArchiver) Archive(_ context.Context, _ *zap.SugaredLogger, pr *tektonv1.PipelineRun) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.archived = append(f.archived, pr.GetName())
+	return nil
+}
+
 func TestCleanupPipelines(t *testing.T) {
 	ns := "namespace"
 	cleanupRepoName := "clean-me-up-before-you-go-go-go-go"
@@ -45,6 +60,8 @@ func TestCleanupPipelines(t *testing.T) {
 		prunLatestInList string
 		secrets          []*corev1.Secret
 		sList            int
+		archiver         *fakeArchiver
+		archived         []string
 	}
 
 	tests := []struct {
@@ -68,6 +85,39 @@ func TestCleanupPipelines(t *testing.T) {
 				prunLatestInList: "pipeline-newest",
 			},
 		},
+		{
+			name: "archive before cleanup",
+			args: args{
+				namespace:      ns,
+				repositoryName: cleanupRepoName,
+				maxKeep:        1,
+				kept:           1,
+				prunCurrent:    &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: cleanupAnnotations}},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(clock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+					tektontest.MakePRCompletion(clock, "pipeline-middest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 20),
+					tektontest.MakePRCompletion(clock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 30),
+				},
+				archiver: &fakeArchiver{},
+				archived: []string{"pipeline-middest", "pipeline-oldest"},
+			},
+		},
+		{
+			name: "archive failure keeps the pipelineruns",
+			args: args{
+				namespace:      ns,
+				repositoryName: cleanupRepoName,
+				maxKeep:        1,
+				kept:           2,
+				prunCurrent:    &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Labels: cleanupLabels, Annotations: cleanupAnnotations}},
+				pruns: []*tektonv1.PipelineRun{
+					tektontest.MakePRCompletion(clock, "pipeline-newest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 10),
+					tektontest.MakePRCompletion(clock, "pipeline-oldest", ns, tektonv1.PipelineRunReasonSuccessful.String(), nil, cleanupLabels, 30),
+				},
+				archiver: &fakeArchiver{err: fmt.Errorf("sink unavailable")},
+			},
+			wantErr: true,
+		},
 		{
 			name: "cleanup-skip-running",
 			args: args{
@@ -227,11 +277,17 @@ func TestCleanupPipelines(t *testing.T) {
 					},
 				},
 			}
+			if tt.args.archiver != nil {
+				kint.Archiver = tt.args.archiver
+			}
 
 			err := kint.CleanupPipelines(ctx, fakelogger, repo, tt.args.prunCurrent, tt.args.maxKeep)
 			if tt.wantErr {
 				assert.Assert(t, err != nil)
 			}
+			if tt.args.archived != nil {
+				assert.DeepEqual(t, tt.args.archiver.archived, tt.args.archived)
+			}
 
 			plist, err := kint.Run.Clients.Tekton.TektonV1().PipelineRuns(tt.args.namespace).List(
 				ctx, metav1.ListOptions{})
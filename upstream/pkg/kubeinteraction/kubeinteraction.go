@@ -2,6 +2,7 @@ package kubeinteraction
 
 import (
 	"context"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
@@ -13,6 +14,7 @@ import (
 
 type Interface interface {
 	CleanupPipelines(context.Context, *zap.SugaredLogger, *v1alpha1.Repository, *pipelinev1.PipelineRun, int) error
+	ApplyRetention(context.Context, *zap.SugaredLogger, *v1alpha1.Repository, RetentionPolicy, time.Time) (int, error)
 	CreateSecret(ctx context.Context, ns string, secret *corev1.Secret) error
 	DeleteSecret(context.Context, *zap.SugaredLogger, string, string) error
 	UpdateSecretWithOwnerRef(context.Context, *zap.SugaredLogger, string, string, *pipelinev1.PipelineRun) error
@@ -20,8 +22,17 @@ type Interface interface {
 	GetPodLogs(context.Context, string, string, string, int64) (string, error)
 }
 
+// Archiver saves a PipelineRun, its TaskRuns and their logs before the
+// cleanups delete it.
+type Archiver interface {
+	Archive(context.Context, *zap.SugaredLogger, *pipelinev1.PipelineRun) error
+}
+
 type Interaction struct {
 	Run *params.Run
+	// Archiver is optional, the PipelineRuns are deleted without being
+	// archived when it is nil.
+	Archiver Archiver
 }
 
 // validate the interface implementation.
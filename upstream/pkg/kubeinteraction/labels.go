@@ -86,6 +86,14 @@ func AddLabelsAndAnnotations(event *info.Event, pipelineRun *tektonv1.PipelineRu
 		labels[keys.CancelInProgress] = value
 	}
 
+	// the default priority of the repository applies to the PipelineRuns
+	// without their own, it is set on them so the queue doesn't depend on the
+	// repository settings at the time it gets rebuilt.
+	if _, ok := pipelineRun.GetObjectMeta().GetAnnotations()[keys.Priority]; !ok &&
+		repo.Spec.Settings != nil && repo.Spec.Settings.DefaultPriority != nil {
+		annotations[keys.Priority] = strconv.Itoa(*repo.Spec.Settings.DefaultPriority)
+	}
+
 	for k, v := range labels {
 		pipelineRun.Labels[k] = v
 	}
@@ -24,6 +24,13 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 	event.SHAURL = "https://url/sha"
 	event.HeadBranch = "pr_branch"
 	event.HeadURL = "https://url/pr"
+	ten := 10
+	controllerInfo := &info.ControllerInfo{
+		Name:             "controller",
+		Configmap:        "configmap",
+		Secret:           "secret",
+		GlobalRepository: "repo",
+	}
 
 	type args struct {
 		event          *info.Event
@@ -32,8 +39,9 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 		controllerInfo *info.ControllerInfo
 	}
 	tests := []struct {
-		name string
-		args args
+		name         string
+		args         args
+		wantPriority string
 	}{
 		{
 			name: "test label and annotation added to pr",
@@ -52,13 +60,54 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 						Name: "repo",
 					},
 				},
-				controllerInfo: &info.ControllerInfo{
-					Name:             "controller",
-					Configmap:        "configmap",
-					Secret:           "secret",
-					GlobalRepository: "repo",
+				controllerInfo: controllerInfo,
+			},
+		},
+		{
+			name: "default priority of the repository",
+			args: args{
+				event: event,
+				pipelineRun: &tektonv1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels:      map[string]string{},
+						Annotations: map[string]string{},
+					},
+				},
+				repo: &apipac.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "repo",
+					},
+					Spec: apipac.RepositorySpec{
+						Settings: &apipac.Settings{DefaultPriority: &ten},
+					},
+				},
+				controllerInfo: controllerInfo,
+			},
+			wantPriority: "10",
+		},
+		{
+			name: "priority of the pipelinerun takes precedence",
+			args: args{
+				event: event,
+				pipelineRun: &tektonv1.PipelineRun{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{},
+						Annotations: map[string]string{
+							keys.Priority: "-5",
+						},
+					},
+				},
+				repo: &apipac.Repository{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "repo",
+					},
+					Spec: apipac.RepositorySpec{
+						Settings: &apipac.Settings{DefaultPriority: &ten},
+					},
 				},
+				controllerInfo: controllerInfo,
 			},
+			wantPriority: "-5",
 		},
 	}
 	for _, tt := range tests {
@@ -79,6 +128,7 @@ func TestAddLabelsAndAnnotations(t *testing.T) {
 			assert.Equal(t, tt.args.pipelineRun.Annotations[keys.ShaURL], tt.args.event.SHAURL)
 			assert.Equal(t, tt.args.pipelineRun.Annotations[keys.SourceBranch], tt.args.event.HeadBranch)
 			assert.Equal(t, tt.args.pipelineRun.Annotations[keys.SourceRepoURL], tt.args.event.HeadURL)
+			assert.Equal(t, tt.args.pipelineRun.Annotations[keys.Priority], tt.wantPriority)
 			assert.Equal(t, tt.args.pipelineRun.Annotations[keys.ControllerInfo],
 				fmt.Sprintf(`{"name":"%s","configmap":"%s","secret":"%s", "gRepo": "%s"}`, tt.args.controllerInfo.Name, tt.args.controllerInfo.Configmap, tt.args.controllerInfo.Secret, tt.args.controllerInfo.GlobalRepository))
 		})
@@ -0,0 +1,89 @@
+package kubeinteraction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	psort "github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// RetentionPolicy is how long the completed PipelineRuns of a Repository are
+// kept by status. A zero duration keeps them forever.
+type RetentionPolicy struct {
+	Successful time.Duration
+	Failed     time.Duration
+	// KeepLast is the number of the most recent completed PipelineRuns of
+	// every branch which are always kept.
+	KeepLast int
+}
+
+// Enabled checks if some PipelineRuns are deleted by the policy.
+func (p RetentionPolicy) Enabled() bool {
+	return p.Successful > 0 || p.Failed > 0
+}
+
+// retentionBranch is the branch a PipelineRun has run for, the source branch
+// of a pull request or the branch pushed to.
+func retentionBranch(pr *tektonv1.PipelineRun) string {
+	if branch := pr.GetAnnotations()[keys.SourceBranch]; branch != "" {
+		return branch
+	}
+	return pr.GetAnnotations()[keys.Branch]
+}
+
+// ApplyRetention deletes the completed PipelineRuns of a Repository which are
+// past the retention of their status, the most recent ones of every branch
+// are always kept. It returns the number of deleted PipelineRuns.
+func (k Interaction) ApplyRetention(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, policy RetentionPolicy, now time.Time) (int, error) {
+	if !policy.Enabled() {
+		return 0, nil
+	}
+	labelSelector := fmt.Sprintf("%s=%s,%s=%s",
+		keys.Repository, formatting.CleanValueKubernetes(repo.GetName()), keys.State, StateCompleted)
+	pruns, err := k.Run.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx,
+		metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	kept := map[string]int{}
+	for _, prun := range psort.PipelineRunSortByCompletionTime(pruns.Items) {
+		if !prun.IsDone() || prun.Status.CompletionTime == nil {
+			continue
+		}
+		branch := retentionBranch(&prun)
+		if kept[branch] < policy.KeepLast {
+			kept[branch]++
+			continue
+		}
+
+		retention, status := policy.Failed, "failed"
+		if prun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
+			retention, status = policy.Successful, "successful"
+		}
+		if retention == 0 || now.Sub(prun.Status.CompletionTime.Time) <= retention {
+			continue
+		}
+
+		logger.Infof("cleaning %s PipelineRun %s completed %s ago, past its retention of %s", status, prun.GetName(),
+			now.Sub(prun.Status.CompletionTime.Time).Round(time.Minute), retention)
+		if err := k.deletePipelineRun(ctx, logger, &prun); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,126 @@
+package kubeinteraction
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestApplyRetention(t *testing.T) {
+	ns := "namespace"
+	repoName := "retention"
+	clock := clockwork.NewFakeClock()
+	day := 24 * 60
+
+	retentionPR := func(name, branch, status string, completedMinutesAgo int) *tektonv1.PipelineRun {
+		labels := map[string]string{keys.Repository: repoName, keys.State: StateCompleted}
+		annotations := map[string]string{keys.Repository: repoName, keys.State: StateCompleted, keys.Branch: branch}
+		if name == "feature-old-success" {
+			annotations[keys.GitAuthSecret] = "pac-gitauth-feature"
+		}
+		return tektontest.MakePRCompletion(clock, name, ns, status, annotations, labels, completedMinutesAgo)
+	}
+	pruns := []*tektonv1.PipelineRun{
+		retentionPR("main-last-success", "main", tektonv1.PipelineRunReasonSuccessful.String(), 5*day),
+		retentionPR("main-old-success", "main", tektonv1.PipelineRunReasonSuccessful.String(), 6*day),
+		retentionPR("main-recent-failure", "main", tektonv1.PipelineRunReasonFailed.String(), 7*day),
+		retentionPR("main-old-failure", "main", tektonv1.PipelineRunReasonFailed.String(), 20*day),
+		retentionPR("feature-old-failure", "feature", tektonv1.PipelineRunReasonFailed.String(), 30*day),
+		retentionPR("feature-old-success", "feature", tektonv1.PipelineRunReasonSuccessful.String(), 31*day),
+		retentionPR("feature-recent-success", "feature", tektonv1.PipelineRunReasonSuccessful.String(), 32*day),
+	}
+	// the most recent PipelineRun of the feature branch
+	pruns[6].Status.CompletionTime = &metav1.Time{Time: clock.Now().Add(-time.Hour)}
+	running := tektontest.MakePRCompletion(clock, "running", ns, tektonv1.PipelineRunReasonRunning.String(), nil,
+		map[string]string{keys.Repository: repoName, keys.State: StateCompleted}, 40*day)
+	running.Status.CompletionTime = nil
+	pruns = append(pruns, running)
+
+	tests := []struct {
+		name        string
+		policy      RetentionPolicy
+		wantDeleted []string
+	}{
+		{
+			name:   "disabled",
+			policy: RetentionPolicy{KeepLast: 1},
+		},
+		{
+			name:        "successful and failed",
+			policy:      RetentionPolicy{Successful: 2 * 24 * time.Hour, Failed: 14 * 24 * time.Hour, KeepLast: 1},
+			wantDeleted: []string{"feature-old-failure", "feature-old-success", "main-old-failure", "main-old-success"},
+		},
+		{
+			name:        "only failed",
+			policy:      RetentionPolicy{Failed: 14 * 24 * time.Hour},
+			wantDeleted: []string{"feature-old-failure", "main-old-failure"},
+		},
+		{
+			name:        "keep the last ones of every branch",
+			policy:      RetentionPolicy{Successful: time.Minute, Failed: time.Minute, KeepLast: 2},
+			wantDeleted: []string{"feature-old-success", "main-old-failure", "main-recent-failure"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: repoName, Namespace: ns}}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				PipelineRuns: pruns,
+				Secret: []*corev1.Secret{
+					{ObjectMeta: metav1.ObjectMeta{Name: "pac-gitauth-feature", Namespace: ns}},
+				},
+			})
+			kint := Interaction{
+				Run: &params.Run{
+					Clients: clients.Clients{
+						Kube:   stdata.Kube,
+						Tekton: stdata.Pipeline,
+					},
+				},
+			}
+
+			deleted, err := kint.ApplyRetention(ctx, logger, repo, tt.policy, clock.Now())
+			assert.NilError(t, err)
+			assert.Equal(t, deleted, len(tt.wantDeleted))
+
+			left, err := stdata.Pipeline.TektonV1().PipelineRuns(ns).List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			remaining := map[string]bool{}
+			for _, pr := range left.Items {
+				remaining[pr.GetName()] = true
+			}
+			gotDeleted := []string{}
+			for _, pr := range pruns {
+				if !remaining[pr.GetName()] {
+					gotDeleted = append(gotDeleted, pr.GetName())
+				}
+			}
+			sort.Strings(gotDeleted)
+			if tt.wantDeleted == nil {
+				tt.wantDeleted = []string{}
+			}
+			assert.DeepEqual(t, gotDeleted, tt.wantDeleted)
+
+			_, err = stdata.Kube.CoreV1().Secrets(ns).Get(ctx, "pac-gitauth-feature", metav1.GetOptions{})
+			assert.Equal(t, err != nil, !remaining["feature-old-success"])
+		})
+	}
+}
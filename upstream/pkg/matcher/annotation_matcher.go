@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
@@ -27,6 +28,9 @@ import (
 )
 
 const (
+	// generatedNameSuffixLength is the length of the random suffix of the
+	// names generated by the API server
+	generatedNameSuffixLength = 5
 	// regex allows array of string or a single string
 	// eg. ["foo", "bar"], ["foo"] or "foo".
 	reValidateTag = `^\[(.*)\]$|^[^[\]\s]*$`
@@ -68,8 +72,10 @@ func branchMatch(prunBranch, baseBranch string) bool {
 	return matchGlob(prunBranch, baseBranch)
 }
 
+// GetAnnotationValues returns the values of an annotation which can either be
+// a single value or a list like "[foo, bar]".
 // TODO: move to another file since it's common to all annotations_* files.
-func getAnnotationValues(annotation string) ([]string, error) {
+func GetAnnotationValues(annotation string) ([]string, error) {
 	re := regexp.MustCompile(reValidateTag)
 	annotation = strings.TrimSpace(annotation)
 	match := re.MatchString(annotation)
@@ -97,10 +103,11 @@ func getAnnotationValues(annotation string) ([]string, error) {
 	return split, nil
 }
 
-func getTargetBranch(prun *tektonv1.PipelineRun, event *info.Event) (bool, string, string, error) {
+func getTargetBranch(prun *tektonv1.PipelineRun, event *info.Event, prReport *PipelineRunReport) (bool, string, string, error) {
 	var targetEvent, targetBranch string
 	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnEvent]; ok {
 		if key == "[]" {
+			prReport.check(keys.OnEvent, key, false, "the annotation is empty")
 			return false, "", "", fmt.Errorf("annotation %s is empty", keys.OnEvent)
 		}
 		targetEvents := []string{event.TriggerTarget.String()}
@@ -111,28 +118,40 @@ func getTargetBranch(prun *tektonv1.PipelineRun, event *info.Event) (bool, strin
 		matched, err := matchOnAnnotation(key, targetEvents, false)
 		targetEvent = key
 		if err != nil {
+			prReport.check(keys.OnEvent, key, false, "%v", err)
 			return false, "", "", err
 		}
 		if !matched {
+			prReport.check(keys.OnEvent, key, false, "the event %s is not in the annotation", strings.Join(targetEvents, "|"))
 			return false, "", "", nil
 		}
+		prReport.check(keys.OnEvent, key, true, "the event %s matches", strings.Join(targetEvents, "|"))
 	}
 	if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnTargetBranch]; ok {
 		if key == "[]" {
+			prReport.check(keys.OnTargetBranch, key, false, "the annotation is empty")
 			return false, "", "", fmt.Errorf("annotation %s is empty", keys.OnTargetBranch)
 		}
 		targetEvents := []string{event.BaseBranch}
 		matched, err := matchOnAnnotation(key, targetEvents, true)
 		targetBranch = key
 		if err != nil {
+			prReport.check(keys.OnTargetBranch, key, false, "%v", err)
 			return false, "", "", err
 		}
 		if !matched {
+			prReport.check(keys.OnTargetBranch, key, false, "the target branch %s does not match", event.BaseBranch)
 			return false, "", "", nil
 		}
+		prReport.check(keys.OnTargetBranch, key, true, "the target branch %s matches", event.BaseBranch)
 	}
 
-	if targetEvent == "" || targetBranch == "" {
+	if targetEvent == "" {
+		prReport.check(keys.OnEvent, "", false, "the annotation is missing, on-event and on-target-branch are both needed to match")
+		return false, "", "", nil
+	}
+	if targetBranch == "" {
+		prReport.check(keys.OnTargetBranch, "", false, "the annotation is missing, on-event and on-target-branch are both needed to match")
 		return false, "", "", nil
 	}
 	return true, targetEvent, targetBranch, nil
@@ -191,6 +210,19 @@ func checkPipelineRunAnnotation(prun *tektonv1.PipelineRun, eventEmitter *events
 }
 
 func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger, pruns []*tektonv1.PipelineRun, cs *params.Run, event *info.Event, vcx provider.Interface, eventEmitter *events.EventEmitter, repo *apipac.Repository) ([]Match, error) {
+	return matchPipelinerunByAnnotation(ctx, logger, pruns, cs, event, vcx, eventEmitter, repo, nil)
+}
+
+// MatchPipelinerunByAnnotationWithReport matches the PipelineRuns like
+// MatchPipelinerunByAnnotation and returns as well a report explaining why
+// each of them has matched or not.
+func MatchPipelinerunByAnnotationWithReport(ctx context.Context, logger *zap.SugaredLogger, pruns []*tektonv1.PipelineRun, cs *params.Run, event *info.Event, vcx provider.Interface, eventEmitter *events.EventEmitter, repo *apipac.Repository) ([]Match, *MatchReport, error) {
+	report := &MatchReport{PipelineRuns: []*PipelineRunReport{}}
+	matchedPRs, err := matchPipelinerunByAnnotation(ctx, logger, pruns, cs, event, vcx, eventEmitter, repo, report)
+	return matchedPRs, report, err
+}
+
+func matchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger, pruns []*tektonv1.PipelineRun, cs *params.Run, event *info.Event, vcx provider.Interface, eventEmitter *events.EventEmitter, repo *apipac.Repository, report *MatchReport) ([]Match, error) {
 	matchedPRs := []Match{}
 	infomsg := fmt.Sprintf("matching pipelineruns to event: URL=%s, target-branch=%s, source-branch=%s, target-event=%s",
 		event.URL,
@@ -218,14 +250,18 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		}
 
 		prName := getName(prun)
+		prReport := report.add(prun)
 		if event.TargetPipelineRun != "" && event.TargetPipelineRun == strings.TrimSuffix(prName, "-") {
 			logger.Infof("matched target pipelinerun with name: %s, target pipelinerun: %s", prName, event.TargetPipelineRun)
+			prReport.check("", "", true, "the PipelineRun is targeted by the incoming webhook")
+			prReport.matched()
 			matchedPRs = append(matchedPRs, prMatch)
 			continue
 		}
 
 		if prun.GetObjectMeta().GetAnnotations() == nil {
 			logger.Debugf("PipelineRun %s does not have any annotations", prName)
+			prReport.check("", "", false, "the PipelineRun does not have any annotations")
 			continue
 		}
 
@@ -238,6 +274,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			prMatch.Repo, _ = MatchEventURLRepo(ctx, cs, event, targetNS)
 			if prMatch.Repo == nil {
 				logger.Warnf("could not find Repository CRD in branch %s, the pipelineRun %s has a label that explicitly targets it", targetNS, prName)
+				prReport.check(keys.TargetNamespace, targetNS, false, "no Repository for %s has been found in the namespace", event.URL)
 				continue
 			}
 		}
@@ -246,6 +283,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			re, err := regexp.Compile(targetComment)
 			if err != nil {
 				logger.Warnf("could not compile regexp %s from pipelineRun %s", targetComment, prName)
+				prReport.check(keys.OnComment, targetComment, false, "the regexp cannot be compiled: %v", err)
 				continue
 			}
 
@@ -259,6 +297,8 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 					comment = comment[:maxCommentLogLength] + "..."
 				}
 				logger.Infof("matched pipelinerun with name: %s on gitops comment: %q", prName, comment)
+				prReport.check(keys.OnComment, targetComment, true, "the comment matches the regexp")
+				prReport.matched()
 
 				matchedPRs = append(matchedPRs, prMatch)
 				continue
@@ -266,6 +306,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		}
 		// if the event is a comment event, but we don't have any match from the keys.OnComment then skip the other evaluations
 		if event.EventType == opscomments.NoOpsCommentEventType.String() || event.EventType == opscomments.OnCommentEventType.String() {
+			prReport.check(keys.OnComment, prun.GetObjectMeta().GetAnnotations()[keys.OnComment], false, "the comment does not match any on-comment annotation")
 			continue
 		}
 
@@ -274,6 +315,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		_, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnLabel]
 		if event.TriggerTarget == triggertype.PullRequest && event.EventType == string(triggertype.PullRequestLabeled) && !ok {
 			logger.Infof("label update event, PipelineRun %s does not have a on-label for any of those labels: %s", prName, strings.Join(event.PullRequestLabel, "|"))
+			prReport.check(keys.OnLabel, "", false, "the event is a label update and the annotation is missing")
 			continue
 		}
 
@@ -283,6 +325,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			out, err := celEvaluate(ctx, celExpr, event, vcx)
 			if err != nil {
 				logger.Errorf("there was an error evaluating the CEL expression, skipping: %v", err)
+				prReport.check(keys.OnCelExpression, celExpr, false, "the expression cannot be evaluated: %v", err)
 				if checkIfCELEvaluateError(err) {
 					celValidationErrors = append(celValidationErrors, &pacerrors.PacYamlValidations{
 						Name: prName,
@@ -293,11 +336,13 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			}
 			if out != types.True {
 				logger.Infof("CEL expression for PipelineRun %s is not matching, skipping", prName)
+				prReport.check(keys.OnCelExpression, celExpr, false, "the expression evaluates to %v", out)
 				continue
 			}
 			logger.Infof("CEL expression has been evaluated and matched")
+			prReport.check(keys.OnCelExpression, celExpr, true, "the expression evaluates to true")
 		} else {
-			matched, targetEvent, targetBranch, err := getTargetBranch(prun, event)
+			matched, targetEvent, targetBranch, err := getTargetBranch(prun, event, prReport)
 			if err != nil {
 				return matchedPRs, err
 			}
@@ -307,10 +352,26 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 			prMatch.Config["target-branch"] = targetBranch
 			prMatch.Config["target-event"] = targetEvent
 
+			// GitOps commands are an explicit request from the user, so we
+			// don't skip the draft pull requests for them.
+			if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnDraft]; ok && event.PullRequestDraft &&
+				!opscomments.IsAnyOpsEventType(event.EventType) {
+				onDraft, err := strconv.ParseBool(strings.TrimSpace(key))
+				if err != nil {
+					logger.Warnf("could not parse the %s annotation value %q of pipelineRun %s, it should be true or false", keys.OnDraft, key, prName)
+					prReport.check(keys.OnDraft, key, true, "the value cannot be parsed as a boolean, ignoring it")
+				} else if !onDraft {
+					logger.Infof("Skipping pipelinerun with name: %s, pull request is a draft and annotation OnDraft: %q", prName, key)
+					prReport.check(keys.OnDraft, key, false, "the pull request is a draft")
+					continue
+				}
+			}
+
 			if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnPathChange]; ok {
 				changedFiles, err := vcx.GetFiles(ctx, event)
 				if err != nil {
 					logger.Errorf("error getting changed files: %v", err)
+					prReport.check(keys.OnPathChange, key, false, "cannot get the changed files: %v", err)
 					continue
 				}
 				// // TODO(chmou): we use the matchOnAnnotation function, it's
@@ -318,24 +379,30 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				// our own path changes. we may split up if needed to refine.
 				matched, err := matchOnAnnotation(key, changedFiles.All, true)
 				if err != nil {
+					prReport.check(keys.OnPathChange, key, false, "%v", err)
 					return matchedPRs, err
 				}
 				if !matched {
+					prReport.check(keys.OnPathChange, key, false, "none of the %d changed files matches", len(changedFiles.All))
 					continue
 				}
 				logger.Infof("matched PipelineRun with name: %s, annotation PathChange: %q", prName, key)
+				prReport.check(keys.OnPathChange, key, true, "a changed file matches")
 				prMatch.Config["path-change"] = key
 			}
 
 			if key, ok := prun.GetObjectMeta().GetAnnotations()[keys.OnLabel]; ok {
 				matched, err := matchOnAnnotation(key, event.PullRequestLabel, false)
 				if err != nil {
+					prReport.check(keys.OnLabel, key, false, "%v", err)
 					return matchedPRs, err
 				}
 				if !matched {
+					prReport.check(keys.OnLabel, key, false, "none of the labels %q matches", strings.Join(event.PullRequestLabel, "|"))
 					continue
 				}
 				logger.Infof("matched PipelineRun with name: %s, annotation Label: %q", prName, key)
+				prReport.check(keys.OnLabel, key, true, "a label matches")
 				prMatch.Config["label"] = key
 			}
 
@@ -343,6 +410,7 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				changedFiles, err := vcx.GetFiles(ctx, event)
 				if err != nil {
 					logger.Errorf("error getting changed files: %v", err)
+					prReport.check(keys.OnPathChangeIgnore, key, false, "cannot get the changed files: %v", err)
 					continue
 				}
 				// // TODO(chmou): we use the matchOnAnnotation function, it's
@@ -350,17 +418,21 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 				// our own path changes. we may split up if needed to refine.
 				matched, err := matchOnAnnotation(key, changedFiles.All, true)
 				if err != nil {
+					prReport.check(keys.OnPathChangeIgnore, key, false, "%v", err)
 					return matchedPRs, err
 				}
 				if matched {
 					logger.Infof("Skipping pipelinerun with name: %s, annotation PathChangeIgnore: %q", prName, key)
+					prReport.check(keys.OnPathChangeIgnore, key, false, "a changed file is ignored")
 					continue
 				}
+				prReport.check(keys.OnPathChangeIgnore, key, true, "none of the %d changed files is ignored", len(changedFiles.All))
 				prMatch.Config["path-change-ignore"] = key
 			}
 		}
 
 		logger.Infof("matched pipelinerun with name: %s, annotation Config: %q", prName, prMatch.Config)
+		prReport.matched()
 		matchedPRs = append(matchedPRs, prMatch)
 	}
 
@@ -372,7 +444,14 @@ func MatchPipelinerunByAnnotation(ctx context.Context, logger *zap.SugaredLogger
 		// Filter out templates that already have successful PipelineRuns for /retest and /ok-to-test
 		if event.EventType == opscomments.RetestAllCommentEventType.String() ||
 			event.EventType == opscomments.OkToTestCommentEventType.String() {
-			return filterSuccessfulTemplates(ctx, logger, cs, event, repo, matchedPRs), nil
+			filteredPRs := filterSuccessfulTemplates(ctx, logger, cs, event, repo, matchedPRs)
+			report.filtered(matchedPRs, filteredPRs, "the PipelineRun has already succeeded on this commit")
+			return filteredPRs, nil
+		}
+		if event.EventType == opscomments.RetestFailedCommentEventType.String() {
+			filteredPRs := filterFailedTemplates(ctx, logger, cs, event, repo, matchedPRs)
+			report.filtered(matchedPRs, filteredPRs, "the last PipelineRun on this commit has not failed")
+			return filteredPRs, nil
 		}
 		return matchedPRs, nil
 	}
@@ -440,6 +519,79 @@ func filterSuccessfulTemplates(ctx context.Context, logger *zap.SugaredLogger, c
 	return filteredPRs
 }
 
+// filterFailedTemplates keeps the templates whose last PipelineRun on the
+// commit has failed or has been cancelled, for the /retest-failed gitops
+// command. The templates whose PipelineRuns have been cleaned up are looked up
+// in the run statuses of the Repository, the templates which never ran or are
+// still running are skipped.
+func filterFailedTemplates(ctx context.Context, logger *zap.SugaredLogger, cs *params.Run, event *info.Event, repo *apipac.Repository, matchedPRs []Match) []Match {
+	if event.SHA == "" {
+		return nil
+	}
+
+	labelSelector := fmt.Sprintf("%s=%s", keys.SHA, formatting.CleanValueKubernetes(event.SHA))
+	existingPRs, err := cs.Clients.Tekton.TektonV1().PipelineRuns(repo.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		logger.Errorf("failed to list existing PipelineRuns for SHA %s: %v", event.SHA, err)
+	}
+
+	// the last PipelineRun of each template on the commit
+	lastRuns := map[string]*tektonv1.PipelineRun{}
+	if existingPRs != nil {
+		for i := range existingPRs.Items {
+			pr := &existingPRs.Items[i]
+			originalPRName, ok := pr.GetAnnotations()[keys.OriginalPRName]
+			if !ok {
+				continue
+			}
+			if last, exists := lastRuns[originalPRName]; !exists || pr.CreationTimestamp.After(last.CreationTimestamp.Time) {
+				lastRuns[originalPRName] = pr
+			}
+		}
+	}
+
+	var filteredPRs []Match
+	for _, match := range matchedPRs {
+		templateName := getName(match.PipelineRun)
+		var condition *apis.Condition
+		lastRunName := ""
+		if pr, ok := lastRuns[templateName]; ok {
+			condition, lastRunName = pr.Status.GetCondition(apis.ConditionSucceeded), pr.GetName()
+		} else if status := lastRunStatus(repo, templateName, event.SHA); status != nil {
+			condition, lastRunName = status.GetCondition(apis.ConditionSucceeded), status.PipelineRunName
+		}
+		switch {
+		case lastRunName == "":
+			logger.Infof("skipping template '%s' for sha %s as it has not run on it", templateName, event.SHA)
+		case condition.IsFalse():
+			logger.Infof("retesting template '%s' for sha %s as its last pipelinerun '%s' has failed", templateName, event.SHA, lastRunName)
+			filteredPRs = append(filteredPRs, match)
+		default:
+			logger.Infof("skipping template '%s' for sha %s as its last pipelinerun '%s' has not failed", templateName, event.SHA, lastRunName)
+		}
+	}
+	return filteredPRs
+}
+
+// lastRunStatus returns the last run status of the Repository for a template
+// on a commit, the PipelineRuns of a template are named after it with a
+// generated suffix.
+func lastRunStatus(repo *apipac.Repository, templateName, sha string) *apipac.RepositoryRunStatus {
+	for i := len(repo.Status) - 1; i >= 0; i-- {
+		status := &repo.Status[i]
+		if status.SHA == nil || *status.SHA != sha {
+			continue
+		}
+		suffix, ok := strings.CutPrefix(status.PipelineRunName, strings.TrimSuffix(templateName, "-")+"-")
+		if ok && len(suffix) == generatedNameSuffixLength && !strings.Contains(suffix, "-") {
+			return status
+		}
+	}
+	return nil
+}
+
 func buildAvailableMatchingAnnotationErr(event *info.Event, pruns []*tektonv1.PipelineRun) string {
 	errmsg := "available annotations of the PipelineRuns annotations in .tekton/ dir:"
 	for _, prun := range pruns {
@@ -471,7 +623,7 @@ func buildAvailableMatchingAnnotationErr(event *info.Event, pruns []*tektonv1.Pi
 }
 
 func matchOnAnnotation(annotations string, eventType []string, branchMatching bool) (bool, error) {
-	targets, err := getAnnotationValues(annotations)
+	targets, err := GetAnnotationValues(annotations)
 	if err != nil {
 		return false, err
 	}
@@ -306,6 +306,203 @@ func TestMatchPipelinerunAnnotationAndRepositories(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "cel/match draft pull request",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `event == "pull_request" && draft`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestDraft:  true,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "cel/no match on draft pull request",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnCelExpression: `event == "pull_request" && !draft`,
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestDraft:  true,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:    "ignored/on-draft/draft pull request",
+			wantLog: "Skipping pipelinerun with name: pipeline-target-ns, pull request is a draft",
+			wantErr: true,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnTargetBranch: mainBranch,
+								keys.OnEvent:        "[pull_request]",
+								keys.OnDraft:        "false",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestDraft:  true,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match/on-draft/draft pull request allowed",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnTargetBranch: mainBranch,
+								keys.OnEvent:        "[pull_request]",
+								keys.OnDraft:        "true",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         "pull_request",
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestDraft:  true,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
+		{
+			name:       "match/on-draft/gitops command on draft pull request",
+			wantPRName: pipelineTargetNSName,
+			args: annotationTestArgs{
+				pruns: []*tektonv1.PipelineRun{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: pipelineTargetNSName,
+							Annotations: map[string]string{
+								keys.OnTargetBranch: mainBranch,
+								keys.OnEvent:        "[pull_request]",
+								keys.OnDraft:        "false",
+							},
+						},
+					},
+				},
+				runevent: info.Event{
+					URL:               targetURL,
+					TriggerTarget:     "pull_request",
+					EventType:         opscomments.TestAllCommentEventType.String(),
+					BaseBranch:        mainBranch,
+					HeadBranch:        "unittests",
+					PullRequestNumber: 1000,
+					PullRequestDraft:  true,
+					Organization:      "mylittle",
+					Repository:        "pony",
+				},
+				data: testclient.Data{
+					Repositories: []*v1alpha1.Repository{
+						testnewrepo.NewRepo(
+							testnewrepo.RepoTestcreationOpts{
+								Name:             "test-good",
+								URL:              targetURL,
+								InstallNamespace: targetNamespace,
+							},
+						),
+					},
+				},
+			},
+		},
 		{
 			name:       "cel/match path title pr",
 			wantPRName: pipelineTargetNSName,
@@ -2084,13 +2281,13 @@ func Test_getAnnotationValues(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getAnnotationValues(tt.args.annotation)
+			got, err := GetAnnotationValues(tt.args.annotation)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("getAnnotationValues() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("GetAnnotationValues() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getAnnotationValues() got = %v, want %v", got, tt.want)
+				t.Errorf("GetAnnotationValues() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -2546,7 +2743,7 @@ func TestGetTargetBranch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			matched, targetEvent, targetBranch, err := getTargetBranch(tt.prun, tt.event)
+			matched, targetEvent, targetBranch, err := getTargetBranch(tt.prun, tt.event, nil)
 			if tt.expectedError != "" {
 				assert.Assert(t, err != nil)
 				assert.Error(t, err, tt.expectedError, err.Error())
@@ -2932,3 +3129,73 @@ func TestFilterSuccessfulTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterFailedTemplates(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	logger := zap.NewExample().Sugar()
+	now := metav1.Now()
+
+	makePR := func(name, template, sha string, created metav1.Time, status corev1.ConditionStatus) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test-ns",
+				Labels:            map[string]string{keys.SHA: sha, keys.OriginalPRName: template},
+				Annotations:       map[string]string{keys.OriginalPRName: template},
+				CreationTimestamp: created,
+			},
+			Status: tektonv1.PipelineRunStatus{
+				Status: knativeduckv1.Status{
+					Conditions: knativeduckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}},
+				},
+			},
+		}
+	}
+	runStatus := func(name, sha string, status corev1.ConditionStatus) v1alpha1.RepositoryRunStatus {
+		return v1alpha1.RepositoryRunStatus{
+			Status: knativeduckv1.Status{
+				Conditions: knativeduckv1.Conditions{{Type: apis.ConditionSucceeded, Status: status}},
+			},
+			PipelineRunName: name,
+			SHA:             github.Ptr(sha),
+		}
+	}
+
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-repo", Namespace: "test-ns"},
+		Status: []v1alpha1.RepositoryRunStatus{
+			runStatus("cleaned-failed-abcde", "test-sha", corev1.ConditionFalse),
+			runStatus("cleaned-succeeded-abcde", "test-sha", corev1.ConditionFalse),
+			runStatus("cleaned-succeeded-fghij", "test-sha", corev1.ConditionTrue),
+			runStatus("cleaned-other-sha-abcde", "other-sha", corev1.ConditionFalse),
+		},
+	}
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{
+			makePR("failed-abcde", "failed", "test-sha", now, corev1.ConditionFalse),
+			makePR("succeeded-abcde", "succeeded", "test-sha", now, corev1.ConditionTrue),
+			makePR("retried-abcde", "retried", "test-sha", metav1.NewTime(now.Add(-time.Hour)), corev1.ConditionTrue),
+			makePR("retried-fghij", "retried", "test-sha", now, corev1.ConditionFalse),
+			makePR("fixed-abcde", "fixed", "test-sha", metav1.NewTime(now.Add(-time.Hour)), corev1.ConditionFalse),
+			makePR("fixed-fghij", "fixed", "test-sha", now, corev1.ConditionTrue),
+			makePR("running-abcde", "running", "test-sha", now, corev1.ConditionUnknown),
+			makePR("other-sha-abcde", "other-sha", "other-sha", now, corev1.ConditionFalse),
+		},
+		Repositories: []*v1alpha1.Repository{repo},
+	})
+	cs := &params.Run{Clients: clients.Clients{Log: logger, Tekton: stdata.Pipeline, Kube: stdata.Kube}}
+
+	matchedPRs := []Match{}
+	for _, name := range []string{"failed", "succeeded", "retried", "fixed", "running", "other-sha", "never-ran", "cleaned-failed", "cleaned-succeeded", "cleaned-other-sha"} {
+		matchedPRs = append(matchedPRs, Match{PipelineRun: &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name}}})
+	}
+
+	filtered := filterFailedTemplates(ctx, logger, cs, &info.Event{SHA: "test-sha"}, repo, matchedPRs)
+	names := []string{}
+	for _, match := range filtered {
+		names = append(names, getName(match.PipelineRun))
+	}
+	assert.DeepEqual(t, names, []string{"failed", "retried", "cleaned-failed"})
+
+	assert.Equal(t, len(filterFailedTemplates(ctx, logger, cs, &info.Event{}, repo, matchedPRs)), 0)
+}
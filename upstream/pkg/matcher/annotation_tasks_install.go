@@ -172,7 +172,7 @@ func grabValuesFromAnnotations(annotations map[string]string, annotationReg stri
 		if !rtareg.MatchString(annotationK) {
 			continue
 		}
-		items, err := getAnnotationValues(annotationV)
+		items, err := GetAnnotationValues(annotationV)
 		if err != nil {
 			return ret, err
 		}
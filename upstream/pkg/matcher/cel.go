@@ -77,6 +77,7 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 		"source_branch": event.HeadBranch,
 		"target_url":    event.BaseURL,
 		"source_url":    event.HeadURL,
+		"draft":         event.PullRequestDraft,
 		"body":          jsonMap,
 		"headers":       headerMap,
 		"files": map[string]any{
@@ -87,8 +88,50 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 			"renamed":  changedFiles.Renamed,
 		},
 	}
-	env, err := cel.NewEnv(
-		cel.Lib(celPac{vcx, ctx, event}),
+	env, err := newCelEnv(celPac{vcx, ctx, event})
+	if err != nil {
+		return nil, err
+	}
+
+	checked, err := compileCelExpression(env, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	prg, err := env.Program(checked)
+	if err != nil {
+		return nil, fmt.Errorf("expression %#v failed to create a Program: %w", expr, err)
+	}
+
+	out, _, err := prg.Eval(data)
+	if err != nil {
+		return nil, fmt.Errorf("expression %#v failed to evaluate: %w", expr, err)
+	}
+	return out, nil
+}
+
+// ValidateCelExpression checks that an on-cel-expression annotation compiles
+// and returns a boolean, without evaluating it against an event.
+func ValidateCelExpression(expr string) error {
+	env, err := newCelEnv(celPac{})
+	if err != nil {
+		return err
+	}
+	checked, err := compileCelExpression(env, expr)
+	if err != nil {
+		return err
+	}
+	if outType := checked.OutputType(); outType != cel.BoolType && outType != cel.DynType {
+		return fmt.Errorf("expression %#v returns a %s instead of a bool", expr, outType)
+	}
+	return nil
+}
+
+// newCelEnv returns the environment with the variables and functions
+// available to the on-cel-expression annotations.
+func newCelEnv(lib celPac) (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Lib(lib),
 		cel.VariableDecls(
 			decls.NewVariable("event", types.StringType),
 			decls.NewVariable("headers", types.NewMapType(types.StringType, types.DynType)),
@@ -98,12 +141,12 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 			decls.NewVariable("source_branch", types.StringType),
 			decls.NewVariable("target_url", types.StringType),
 			decls.NewVariable("source_url", types.StringType),
+			decls.NewVariable("draft", types.BoolType),
 			decls.NewVariable("files", types.NewMapType(types.StringType, types.DynType)),
 		))
-	if err != nil {
-		return nil, err
-	}
+}
 
+func compileCelExpression(env *cel.Env, expr string) (*cel.Ast, error) {
 	parsed, issues := env.Parse(expr)
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("failed to parse expression %#v: %w", expr, issues.Err())
@@ -113,17 +156,7 @@ func celEvaluate(ctx context.Context, expr string, event *info.Event, vcx provid
 	if issues != nil && issues.Err() != nil {
 		return nil, fmt.Errorf("expression %#v check failed: %w", expr, issues.Err())
 	}
-
-	prg, err := env.Program(checked)
-	if err != nil {
-		return nil, fmt.Errorf("expression %#v failed to create a Program: %w", expr, err)
-	}
-
-	out, _, err := prg.Eval(data)
-	if err != nil {
-		return nil, fmt.Errorf("expression %#v failed to evaluate: %w", expr, err)
-	}
-	return out, nil
+	return checked, nil
 }
 
 type celPac struct {
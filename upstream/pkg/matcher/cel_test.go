@@ -0,0 +1,49 @@
+package matcher
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateCelExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr string
+	}{
+		{
+			name: "valid expression",
+			expr: `event == "pull_request" && target_branch == "main" && "docs/***".pathChanged()`,
+		},
+		{
+			name: "valid expression on the body",
+			expr: `body.pull_request.draft == false`,
+		},
+		{
+			name:    "syntax error",
+			expr:    `event == "push" &&`,
+			wantErr: "failed to parse expression",
+		},
+		{
+			name:    "unknown variable",
+			expr:    `branch == "main"`,
+			wantErr: "undeclared reference to 'branch'",
+		},
+		{
+			name:    "not a boolean",
+			expr:    `target_branch`,
+			wantErr: "returns a string instead of a bool",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCelExpression(tt.expr)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
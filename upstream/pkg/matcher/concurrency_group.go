@@ -0,0 +1,54 @@
+package matcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// ResolveConcurrencyGroup sets the concurrency-group annotation of the
+// PipelineRun from its concurrency-group-cel-expression annotation. The
+// expression has access to the same variables as on-cel-expression and has
+// to return a string, an empty one means the PipelineRun has no group. A
+// concurrency-group annotation without expression has already been templated
+// with the event and is only trimmed.
+func ResolveConcurrencyGroup(ctx context.Context, pr *tektonv1.PipelineRun, event *info.Event, vcx provider.Interface) error {
+	annotations := pr.GetAnnotations()
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	name := annotations[keys.ConcurrencyGroup]
+	if expr, ok := annotations[keys.ConcurrencyGroupCEL]; ok {
+		// the CEL evaluation strips the refs/heads/ prefix of the branches of
+		// the event, do it on a copy so the matching isn't affected.
+		celEvent := info.NewEvent()
+		event.DeepCopyInto(celEvent)
+		if celEvent.Request == nil {
+			celEvent.Request = &info.Request{}
+		}
+		out, err := celEvaluate(ctx, expr, celEvent, vcx)
+		if err != nil {
+			return fmt.Errorf("cannot evaluate the %s annotation: %w", keys.ConcurrencyGroupCEL, err)
+		}
+		group, ok := out.(types.String)
+		if !ok {
+			return fmt.Errorf("the %s annotation must return a string, got %s", keys.ConcurrencyGroupCEL, out.Type().TypeName())
+		}
+		name = string(group)
+	}
+
+	if name = strings.TrimSpace(name); name != "" {
+		annotations[keys.ConcurrencyGroup] = name
+	} else {
+		delete(annotations, keys.ConcurrencyGroup)
+	}
+	pr.SetAnnotations(annotations)
+	return nil
+}
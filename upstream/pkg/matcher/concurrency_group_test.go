@@ -0,0 +1,85 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestResolveConcurrencyGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		event       *info.Event
+		wantGroup   string
+		wantErr     string
+	}{
+		{
+			name:      "no annotations",
+			event:     &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantGroup: "",
+		},
+		{
+			name:        "templated group is trimmed",
+			annotations: map[string]string{keys.ConcurrencyGroup: " deploy-main "},
+			event:       &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantGroup:   "deploy-main",
+		},
+		{
+			name:        "cel expression",
+			annotations: map[string]string{keys.ConcurrencyGroupCEL: "'deploy-' + target_branch"},
+			event:       &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantGroup:   "deploy-main",
+		},
+		{
+			name:        "cel expression on push",
+			annotations: map[string]string{keys.ConcurrencyGroupCEL: "'deploy-' + target_branch"},
+			event:       &info.Event{TriggerTarget: triggertype.Push, BaseBranch: "refs/heads/main"},
+			wantGroup:   "deploy-main",
+		},
+		{
+			name: "cel expression overrides the group",
+			annotations: map[string]string{
+				keys.ConcurrencyGroup:    "deploy",
+				keys.ConcurrencyGroupCEL: "event == 'push' ? 'deploy' : ''",
+			},
+			event:     &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantGroup: "",
+		},
+		{
+			name:        "cel expression not returning a string",
+			annotations: map[string]string{keys.ConcurrencyGroupCEL: "target_branch == 'main'"},
+			event:       &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantErr:     "must return a string, got bool",
+		},
+		{
+			name:        "invalid cel expression",
+			annotations: map[string]string{keys.ConcurrencyGroupCEL: "'deploy-' +"},
+			event:       &info.Event{TriggerTarget: triggertype.PullRequest, BaseBranch: "main"},
+			wantErr:     "cannot evaluate the pipelinesascode.tekton.dev/concurrency-group-cel-expression annotation",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "deploy", Annotations: tt.annotations}}
+			baseBranch := tt.event.BaseBranch
+			err := ResolveConcurrencyGroup(ctx, pr, tt.event, &testprovider.TestProviderImp{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, pr.GetAnnotations()[keys.ConcurrencyGroup], tt.wantGroup)
+			// the event used for the matching is left untouched
+			assert.Equal(t, tt.event.BaseBranch, baseBranch)
+		})
+	}
+}
@@ -66,9 +66,14 @@ func reportCELValidationErrors(ctx context.Context, repo *apipac.Repository, val
 // newline or carriage-return characters with a single space so that the whole
 // error is kept on one row, preserving readability in the rendered comment.
 func sanitizeErrorAsMarkdown(err error) string {
-	errStr := err.Error()
-	errStr = strings.ReplaceAll(errStr, "|", "\\|")
-	errStr = strings.ReplaceAll(errStr, "\n", " ")
-	errStr = strings.ReplaceAll(errStr, "\r", " ")
-	return errStr
+	return sanitizeMarkdownCell(err.Error())
+}
+
+// sanitizeMarkdownCell escapes a string to be kept in a single cell of a
+// markdown table, see sanitizeErrorAsMarkdown.
+func sanitizeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", " ")
+	return s
 }
@@ -0,0 +1,169 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// MatchReportCommentMarker starts the comment of the match report on a pull
+// request, so the comment is updated instead of adding a new one on every
+// event.
+const MatchReportCommentMarker = "<!-- pipelines-as-code-match-report -->"
+
+// MatchReport explains the matching of an event against the PipelineRuns of
+// the .tekton directory, it records for each PipelineRun every annotation
+// checked and why it has matched or not.
+type MatchReport struct {
+	PipelineRuns []*PipelineRunReport `json:"pipelineruns"`
+}
+
+// PipelineRunReport is the result of the matching of a PipelineRun.
+type PipelineRunReport struct {
+	Name    string            `json:"name"`
+	Matched bool              `json:"matched"`
+	Checks  []AnnotationCheck `json:"checks"`
+}
+
+// AnnotationCheck is the result of an annotation or condition checked on a
+// PipelineRun, the annotation is empty when the check is not about a specific
+// annotation.
+type AnnotationCheck struct {
+	Annotation string `json:"annotation,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Matched    bool   `json:"matched"`
+	Message    string `json:"message"`
+}
+
+// add starts the report of a PipelineRun, all the methods are safe to be
+// called on a nil report so the matcher does not have to check if a report
+// has been asked.
+func (r *MatchReport) add(prun *tektonv1.PipelineRun) *PipelineRunReport {
+	if r == nil {
+		return nil
+	}
+	prReport := &PipelineRunReport{Name: getName(prun), Checks: []AnnotationCheck{}}
+	r.PipelineRuns = append(r.PipelineRuns, prReport)
+	return prReport
+}
+
+// filtered marks as not matched the PipelineRuns which have been removed by a
+// filter after matching their annotations.
+func (r *MatchReport) filtered(before, after []Match, message string) {
+	if r == nil {
+		return
+	}
+	kept := map[*tektonv1.PipelineRun]bool{}
+	for _, match := range after {
+		kept[match.PipelineRun] = true
+	}
+	for _, match := range before {
+		if kept[match.PipelineRun] {
+			continue
+		}
+		for _, prReport := range r.PipelineRuns {
+			if prReport.Matched && prReport.Name == getName(match.PipelineRun) {
+				prReport.check("", "", false, "%s", message)
+				prReport.Matched = false
+				break
+			}
+		}
+	}
+}
+
+// Matched returns the names of the PipelineRuns which have matched.
+func (r *MatchReport) Matched() []string {
+	names := []string{}
+	if r == nil {
+		return names
+	}
+	for _, prReport := range r.PipelineRuns {
+		if prReport.Matched {
+			names = append(names, prReport.Name)
+		}
+	}
+	return names
+}
+
+func (p *PipelineRunReport) check(annotation, value string, matched bool, format string, args ...any) {
+	if p == nil {
+		return
+	}
+	p.Checks = append(p.Checks, AnnotationCheck{
+		Annotation: strings.TrimPrefix(annotation, pipelinesascode.GroupName+"/"),
+		Value:      value,
+		Matched:    matched,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (p *PipelineRunReport) matched() {
+	if p == nil {
+		return
+	}
+	p.Matched = true
+}
+
+// String returns the report on a single line to be emitted as an event, only
+// the check which has rejected a PipelineRun is kept.
+func (r *MatchReport) String() string {
+	if r == nil {
+		return ""
+	}
+	lines := make([]string, 0, len(r.PipelineRuns))
+	for _, prReport := range r.PipelineRuns {
+		if prReport.Matched || len(prReport.Checks) == 0 {
+			lines = append(lines, fmt.Sprintf("%s %s", prReport.Name, prReport.status()))
+			continue
+		}
+		check := prReport.Checks[len(prReport.Checks)-1]
+		annotation := ""
+		if check.Annotation != "" {
+			annotation = fmt.Sprintf(" on %s %s", check.Annotation, check.Value)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s%s: %s", prReport.Name, prReport.status(), annotation, check.Message))
+	}
+	return fmt.Sprintf("matched %d of %d PipelineRun(s): %s", len(r.Matched()), len(r.PipelineRuns), strings.Join(lines, "; "))
+}
+
+// Markdown returns the report with every check as a collapsible markdown
+// table to be posted as a comment, it starts with MatchReportCommentMarker.
+func (r *MatchReport) Markdown() string {
+	var b strings.Builder
+	b.WriteString(MatchReportCommentMarker + "\n")
+	fmt.Fprintf(&b, "<details>\n<summary>Pipelines-as-Code has matched %d of %d PipelineRun(s) for this event</summary>\n\n",
+		len(r.Matched()), len(r.PipelineRuns))
+	b.WriteString("| PipelineRun | Annotation | Value | Result |\n|------|------|------|------|\n")
+	for _, prReport := range r.PipelineRuns {
+		name := fmt.Sprintf("%s **%s**", statusIcon(prReport.Matched), prReport.Name)
+		if len(prReport.Checks) == 0 {
+			fmt.Fprintf(&b, "| %s | | | %s |\n", name, prReport.status())
+		}
+		for _, check := range prReport.Checks {
+			value := ""
+			if check.Value != "" {
+				value = "`" + sanitizeMarkdownCell(check.Value) + "`"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s %s |\n", name, check.Annotation, value, statusIcon(check.Matched), sanitizeMarkdownCell(check.Message))
+			name = ""
+		}
+	}
+	b.WriteString("\n</details>")
+	return b.String()
+}
+
+func (p *PipelineRunReport) status() string {
+	if p.Matched {
+		return "matched"
+	}
+	return "did not match"
+}
+
+func statusIcon(matched bool) string {
+	if matched {
+		return "✅"
+	}
+	return "❌"
+}
@@ -0,0 +1,159 @@
+package matcher
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestMatchPipelinerunByAnnotationWithReport(t *testing.T) {
+	makePrun := func(name string, annotations map[string]string) *tektonv1.PipelineRun {
+		return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+	event := &info.Event{
+		TriggerTarget:    triggertype.PullRequest,
+		EventType:        triggertype.PullRequest.String(),
+		BaseBranch:       "main",
+		HeadBranch:       "feature",
+		PullRequestLabel: []string{"bug"},
+		Request:          &info.Request{Header: http.Header{}},
+	}
+
+	tests := []struct {
+		name        string
+		prun        *tektonv1.PipelineRun
+		wantMatched bool
+		wantChecks  []AnnotationCheck
+	}{
+		{
+			name: "matching event and branch",
+			prun: makePrun("pr", map[string]string{keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+				{Annotation: "on-target-branch", Value: "[main]", Matched: true, Message: "the target branch main matches"},
+			},
+			wantMatched: true,
+		},
+		{
+			name: "event mismatch",
+			prun: makePrun("push", map[string]string{keys.OnEvent: "[push]", keys.OnTargetBranch: "[main]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[push]", Message: "the event pull_request is not in the annotation"},
+			},
+		},
+		{
+			name: "branch glob mismatch",
+			prun: makePrun("release", map[string]string{keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[release-*]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+				{Annotation: "on-target-branch", Value: "[release-*]", Message: "the target branch main does not match"},
+			},
+		},
+		{
+			name: "missing target branch",
+			prun: makePrun("no-branch", map[string]string{keys.OnEvent: "[pull_request]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+				{Annotation: "on-target-branch", Message: "the annotation is missing, on-event and on-target-branch are both needed to match"},
+			},
+		},
+		{
+			name: "path change mismatch",
+			prun: makePrun("docs", map[string]string{keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]", keys.OnPathChange: "[docs/**]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+				{Annotation: "on-target-branch", Value: "[main]", Matched: true, Message: "the target branch main matches"},
+				{Annotation: "on-path-change", Value: "[docs/**]", Message: "none of the 1 changed files matches"},
+			},
+		},
+		{
+			name: "label match",
+			prun: makePrun("label", map[string]string{keys.OnEvent: "[pull_request]", keys.OnTargetBranch: "[main]", keys.OnLabel: "[bug]"}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+				{Annotation: "on-target-branch", Value: "[main]", Matched: true, Message: "the target branch main matches"},
+				{Annotation: "on-label", Value: "[bug]", Matched: true, Message: "a label matches"},
+			},
+			wantMatched: true,
+		},
+		{
+			name: "cel expression false",
+			prun: makePrun("cel", map[string]string{keys.OnCelExpression: `source_branch == "main"`}),
+			wantChecks: []AnnotationCheck{
+				{Annotation: "on-cel-expression", Value: `source_branch == "main"`, Message: "the expression evaluates to false"},
+			},
+		},
+		{
+			name: "no annotations",
+			prun: makePrun("bare", nil),
+			wantChecks: []AnnotationCheck{
+				{Message: "the PipelineRun does not have any annotations"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			vcx := &testprovider.TestProviderImp{WantAllChangedFiles: []string{"src/main.go"}}
+			ev := *event
+			_, report, _ := MatchPipelinerunByAnnotationWithReport(ctx, zap.NewNop().Sugar(),
+				[]*tektonv1.PipelineRun{tt.prun}, &params.Run{}, &ev, vcx, nil, nil)
+			assert.Equal(t, len(report.PipelineRuns), 1)
+			assert.Equal(t, report.PipelineRuns[0].Name, tt.prun.GetName())
+			assert.Equal(t, report.PipelineRuns[0].Matched, tt.wantMatched)
+			assert.DeepEqual(t, report.PipelineRuns[0].Checks, tt.wantChecks)
+		})
+	}
+}
+
+func TestMatchReportFiltered(t *testing.T) {
+	first := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+	second := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{GenerateName: "second-"}}
+	report := &MatchReport{}
+	report.add(first).matched()
+	report.add(second).matched()
+
+	report.filtered([]Match{{PipelineRun: first}, {PipelineRun: second}}, []Match{{PipelineRun: second}}, "filtered out")
+	assert.DeepEqual(t, report.Matched(), []string{"second-"})
+	assert.DeepEqual(t, report.PipelineRuns[0].Checks, []AnnotationCheck{{Message: "filtered out"}})
+
+	var nilReport *MatchReport
+	nilReport.add(first).check("", "", true, "ignored")
+	assert.DeepEqual(t, nilReport.Matched(), []string{})
+}
+
+func TestMatchReportFormat(t *testing.T) {
+	report := &MatchReport{PipelineRuns: []*PipelineRunReport{
+		{Name: "pr", Matched: true, Checks: []AnnotationCheck{
+			{Annotation: "on-event", Value: "[pull_request]", Matched: true, Message: "the event pull_request matches"},
+		}},
+		{Name: "push", Checks: []AnnotationCheck{
+			{Annotation: "on-event", Value: "[push]", Message: "the event pull_request is not in the annotation"},
+		}},
+		{Name: "cel", Checks: []AnnotationCheck{
+			{Annotation: "on-cel-expression", Value: "a || b", Message: "the expression cannot be evaluated: line 1\nline 2"},
+		}},
+		{Name: "retest", Checks: []AnnotationCheck{
+			{Message: "the PipelineRun has already succeeded on this commit"},
+		}},
+	}}
+
+	assert.Equal(t, report.String(), "matched 1 of 4 PipelineRun(s): pr matched; "+
+		"push did not match on on-event [push]: the event pull_request is not in the annotation; "+
+		"cel did not match on on-cel-expression a || b: the expression cannot be evaluated: line 1\nline 2; "+
+		"retest did not match: the PipelineRun has already succeeded on this commit")
+	golden.Assert(t, report.Markdown(), strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+}
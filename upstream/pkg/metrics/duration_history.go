@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// prDurationHistorySize is how many of the last durations of the
+// PipelineRuns of a repository are kept to estimate the next ones.
+const prDurationHistorySize = 10
+
+// durationHistory keeps the durations of the last completed PipelineRuns of
+// every repository.
+type durationHistory struct {
+	lock      sync.Mutex
+	durations map[string][]time.Duration
+}
+
+func newDurationHistory() *durationHistory {
+	return &durationHistory{durations: map[string][]time.Duration{}}
+}
+
+func (h *durationHistory) add(key string, duration time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	durations := append(h.durations[key], duration)
+	if len(durations) > prDurationHistorySize {
+		durations = durations[len(durations)-prDurationHistorySize:]
+	}
+	h.durations[key] = durations
+}
+
+func (h *durationHistory) average(key string) (time.Duration, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	durations := h.durations[key]
+	if len(durations) == 0 {
+		return 0, false
+	}
+	total := time.Duration(0)
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations)), true
+}
+
+// AveragePRDuration returns the average duration of the last PipelineRuns of
+// the repository recorded with CountPRDuration, the cancelled ones are not
+// taken into account. It returns false when none has completed since the
+// watcher started.
+func (r *Recorder) AveragePRDuration(namespace, repository string) (time.Duration, bool) {
+	if r == nil || r.durations == nil {
+		return 0, false
+	}
+	return r.durations.average(namespace + "/" + repository)
+}
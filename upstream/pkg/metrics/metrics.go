@@ -46,6 +46,7 @@ type Recorder struct {
 	status          tag.Key
 	reason          tag.Key
 	ReportingPeriod time.Duration
+	durations       *durationHistory
 }
 
 var (
@@ -63,6 +64,7 @@ func NewRecorder() (*Recorder, error) {
 
 			// Default to 30s intervals.
 			ReportingPeriod: 30 * time.Second,
+			durations:       newDurationHistory(),
 		}
 
 		provider, errRegistering := tag.NewKey("provider")
@@ -195,6 +197,9 @@ func (r *Recorder) CountPRDuration(namespace, repository, status, reason string,
 	}
 
 	metrics.Record(ctx, prDurationCount.M(duration.Seconds()))
+	if status != "cancelled" && duration > 0 {
+		r.durations.add(namespace+"/"+repository, duration)
+	}
 	return nil
 }
 
@@ -16,11 +16,15 @@ import (
 var (
 	testAllRegex      = regexp.MustCompile(`(?m)^/test\s*$`)
 	retestAllRegex    = regexp.MustCompile(`(?m)^/retest\s*$`)
+	retestFailedRegex = regexp.MustCompile(`(?m)^/retest-failed([ \t]+(branch|tag):\S+)?\s*$`)
 	testSingleRegex   = regexp.MustCompile(`(?m)^/test[ \t]+\S+`)
 	retestSingleRegex = regexp.MustCompile(`(?m)^/retest[ \t]+\S+`)
 	oktotestRegex     = regexp.MustCompile(`(?m)^/ok-to-test\s*$`)
 	cancelAllRegex    = regexp.MustCompile(`(?m)^(/cancel)\s*$`)
 	cancelSingleRegex = regexp.MustCompile(`(?m)^(/cancel)[ \t]+\S+`)
+	holdRegex         = regexp.MustCompile(`(?m)^/hold([ \t]+cancel)?\s*$`)
+	unholdRegex       = regexp.MustCompile(`(?m)^/unhold\s*$`)
+	helpRegex         = regexp.MustCompile(`(?m)^/help\s*$`)
 )
 
 type EventType string
@@ -35,10 +39,14 @@ var (
 	TestSingleCommentEventType   = EventType("test-comment")
 	RetestSingleCommentEventType = EventType("retest-comment")
 	RetestAllCommentEventType    = EventType("retest-all-comment")
+	RetestFailedCommentEventType = EventType("retest-failed-comment")
 	OnCommentEventType           = EventType("on-comment")
 	CancelCommentSingleEventType = EventType("cancel-comment")
 	CancelCommentAllEventType    = EventType("cancel-all-comment")
 	OkToTestCommentEventType     = EventType("ok-to-test-comment")
+	HoldCommentEventType         = EventType("hold-comment")
+	UnholdCommentEventType       = EventType("unhold-comment")
+	HelpCommentEventType         = EventType("help-comment")
 )
 
 const (
@@ -49,6 +57,8 @@ const (
 
 func CommentEventType(comment string) EventType {
 	switch {
+	case retestFailedRegex.MatchString(comment):
+		return RetestFailedCommentEventType
 	case retestAllRegex.MatchString(comment):
 		return RetestAllCommentEventType
 	case retestSingleRegex.MatchString(comment):
@@ -63,6 +73,12 @@ func CommentEventType(comment string) EventType {
 		return CancelCommentAllEventType
 	case cancelSingleRegex.MatchString(comment):
 		return CancelCommentSingleEventType
+	case holdRegex.MatchString(comment):
+		return HoldCommentEventType
+	case unholdRegex.MatchString(comment):
+		return UnholdCommentEventType
+	case helpRegex.MatchString(comment):
+		return HelpCommentEventType
 	default:
 		return NoOpsCommentEventType
 	}
@@ -80,6 +96,11 @@ func SetEventTypeAndTargetPR(event *info.Event, comment string) {
 	if commentType == CancelCommentSingleEventType {
 		event.TargetCancelPipelineRun = GetPipelineRunFromCancelComment(comment)
 	}
+	// `/hold cancel` cancels the running PipelineRuns of the pull request
+	// after putting it on hold.
+	if commentType == HoldCommentEventType && IsHoldCancelComment(comment) {
+		event.CancelPipelineRuns = true
+	}
 	event.EventType = commentType.String()
 	event.TriggerComment = comment
 }
@@ -88,6 +109,13 @@ func IsOkToTestComment(comment string) bool {
 	return oktotestRegex.MatchString(comment)
 }
 
+// IsHoldCancelComment returns true when the comment is a `/hold cancel`
+// command.
+func IsHoldCancelComment(comment string) bool {
+	matches := holdRegex.FindStringSubmatch(comment)
+	return len(matches) > 1 && matches[1] != ""
+}
+
 // EventTypeBackwardCompat handle the backward compatibility we need to keep until
 // we have done the deprecated notice
 //
@@ -113,23 +141,31 @@ func IsAnyOpsEventType(eventType string) bool {
 		eventType == TestAllCommentEventType.String() ||
 		eventType == RetestAllCommentEventType.String() ||
 		eventType == RetestSingleCommentEventType.String() ||
+		eventType == RetestFailedCommentEventType.String() ||
 		eventType == CancelCommentSingleEventType.String() ||
 		eventType == CancelCommentAllEventType.String() ||
 		eventType == OkToTestCommentEventType.String() ||
+		eventType == HoldCommentEventType.String() ||
+		eventType == UnholdCommentEventType.String() ||
+		eventType == HelpCommentEventType.String() ||
 		eventType == OnCommentEventType.String()
 }
 
 // AnyOpsKubeLabelInSelector will output a Kubernetes label out of all possible
 // CommentEvent Type for selection.
 func AnyOpsKubeLabelInSelector() string {
-	return fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s",
+	return fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s",
 		TestSingleCommentEventType.String(),
 		TestAllCommentEventType.String(),
 		RetestAllCommentEventType.String(),
 		RetestSingleCommentEventType.String(),
+		RetestFailedCommentEventType.String(),
 		CancelCommentSingleEventType.String(),
 		CancelCommentAllEventType.String(),
 		OkToTestCommentEventType.String(),
+		HoldCommentEventType.String(),
+		UnholdCommentEventType.String(),
+		HelpCommentEventType.String(),
 		OnCommentEventType.String())
 }
 
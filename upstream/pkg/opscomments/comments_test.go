@@ -127,6 +127,11 @@ func TestIsAnyOpsEventType(t *testing.T) {
 			eventType: RetestSingleCommentEventType.String(),
 			want:      true,
 		},
+		{
+			name:      "RetestFailedCommentEventType",
+			eventType: RetestFailedCommentEventType.String(),
+			want:      true,
+		},
 		{
 			name:      "CancelCommentSingleEventType",
 			eventType: CancelCommentSingleEventType.String(),
@@ -147,6 +152,21 @@ func TestIsAnyOpsEventType(t *testing.T) {
 			eventType: OnCommentEventType.String(),
 			want:      true,
 		},
+		{
+			name:      "HoldCommentEventType",
+			eventType: HoldCommentEventType.String(),
+			want:      true,
+		},
+		{
+			name:      "UnholdCommentEventType",
+			eventType: UnholdCommentEventType.String(),
+			want:      true,
+		},
+		{
+			name:      "HelpCommentEventType",
+			eventType: HelpCommentEventType.String(),
+			want:      true,
+		},
 		{
 			name:      "NoOpsCommentEventType",
 			eventType: NoOpsCommentEventType.String(),
@@ -183,6 +203,26 @@ func TestCommentEventTypeTest(t *testing.T) {
 			comment: "/retest prname",
 			want:    RetestSingleCommentEventType,
 		},
+		{
+			name:    "retest failed",
+			comment: "/retest-failed",
+			want:    RetestFailedCommentEventType,
+		},
+		{
+			name:    "retest failed in a comment",
+			comment: "flaky again\n/retest-failed\n",
+			want:    RetestFailedCommentEventType,
+		},
+		{
+			name:    "retest failed on a branch",
+			comment: "/retest-failed branch:nightly",
+			want:    RetestFailedCommentEventType,
+		},
+		{
+			name:    "retest failed with argument",
+			comment: "/retest-failed prname",
+			want:    NoOpsCommentEventType,
+		},
 		{
 			name:    "test all",
 			comment: "/test",
@@ -213,6 +253,36 @@ func TestCommentEventTypeTest(t *testing.T) {
 			comment: "/cancel prname",
 			want:    CancelCommentSingleEventType,
 		},
+		{
+			name:    "hold",
+			comment: "/hold",
+			want:    HoldCommentEventType,
+		},
+		{
+			name:    "hold and cancel",
+			comment: "/hold cancel",
+			want:    HoldCommentEventType,
+		},
+		{
+			name:    "hold with unknown argument",
+			comment: "/hold prname",
+			want:    NoOpsCommentEventType,
+		},
+		{
+			name:    "unhold",
+			comment: "please resume\n/unhold",
+			want:    UnholdCommentEventType,
+		},
+		{
+			name:    "help",
+			comment: "/help",
+			want:    HelpCommentEventType,
+		},
+		{
+			name:    "help with argument",
+			comment: "/help me",
+			want:    NoOpsCommentEventType,
+		},
 	}
 
 	for _, tt := range tests {
@@ -262,6 +332,22 @@ func TestSetEventTypeTestPipelineRun(t *testing.T) {
 			wantType:   CancelCommentAllEventType.String(),
 			wantCancel: true,
 		},
+		{
+			name:     "hold",
+			comment:  "/hold",
+			wantType: HoldCommentEventType.String(),
+		},
+		{
+			name:       "hold and cancel",
+			comment:    "/hold cancel",
+			wantType:   HoldCommentEventType.String(),
+			wantCancel: true,
+		},
+		{
+			name:     "unhold",
+			comment:  "/unhold",
+			wantType: UnholdCommentEventType.String(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -270,6 +356,8 @@ func TestSetEventTypeTestPipelineRun(t *testing.T) {
 			SetEventTypeAndTargetPR(event, tt.comment)
 			assert.Equal(t, tt.wantType, event.EventType)
 			assert.Equal(t, tt.wantTestPr, event.TargetTestPipelineRun)
+			assert.Equal(t, tt.wantCancelPr, event.TargetCancelPipelineRun)
+			assert.Equal(t, tt.wantCancel, event.CancelPipelineRuns)
 		})
 	}
 }
@@ -681,4 +769,6 @@ func TestGetPipelineRunAndBranchNameFromCancelComment(t *testing.T) {
 
 func TestAnyOpsKubeLabelInSelector(t *testing.T) {
 	assert.Assert(t, strings.Contains(AnyOpsKubeLabelInSelector(), RetestSingleCommentEventType.String()))
+	assert.Assert(t, strings.Contains(AnyOpsKubeLabelInSelector(), RetestFailedCommentEventType.String()))
+	assert.Assert(t, strings.Contains(AnyOpsKubeLabelInSelector(), UnholdCommentEventType.String()))
 }
@@ -36,6 +36,7 @@ type Clients struct {
 	HTTP              http.Client
 	Log               *zap.SugaredLogger
 	Dynamic           dynamic.Interface
+	RESTConfig        *rest.Config
 	consoleUIMutex    *sync.Mutex
 	consoleUI         consoleui.Interface
 }
@@ -160,6 +161,7 @@ func (c *Clients) NewClients(ctx context.Context, info *info.Info) error {
 	}
 	config.QPS = 50
 	config.Burst = 50
+	c.RESTConfig = config
 
 	c.Kube, err = c.kubeClient(config)
 	if err != nil {
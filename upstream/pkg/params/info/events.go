@@ -42,6 +42,7 @@ type Event struct {
 	PullRequestNumber int      // Pull or Merge Request number
 	PullRequestTitle  string   // Title of the pull Request
 	PullRequestLabel  []string // Labels of the pull Request
+	PullRequestDraft  bool     // Whether the pull Request is a draft
 	TriggerComment    string   // The comment triggering the pipelinerun when using on-comment annotation
 
 	// TODO: move forge specifics to each driver
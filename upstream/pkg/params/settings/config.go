@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/configutil"
 	hubType "github.com/openshift-pipelines/pipelines-as-code/pkg/hub/vars"
@@ -15,6 +17,16 @@ import (
 const (
 	PACApplicationNameDefaultValue = "Pipelines as Code CI"
 
+	ConcurrencyPriorityAgingDefaultValue = 5 * time.Minute
+	// ConcurrencyPriorityAgingMaxValue keeps the priority boost of a queued
+	// PipelineRun in range.
+	ConcurrencyPriorityAgingMaxValue = 30 * 24 * time.Hour
+
+	RetentionCheckIntervalDefaultValue = time.Hour
+	// RetentionCheckIntervalMinValue keeps the watcher from listing the
+	// PipelineRuns of every Repository too often.
+	RetentionCheckIntervalMinValue = time.Minute
+
 	HubURLKey                          = "hub-url"
 	HubCatalogNameKey                  = "hub-catalog-name"
 	HubCatalogTypeKey                  = "hub-catalog-type"
@@ -75,6 +87,22 @@ type Settings struct {
 
 	SkipPushEventForPRCommits bool `json:"skip-push-event-for-pr-commits" default:"true"` // nolint:tagalign
 
+	SkipCICommitMarkers     bool   `default:"false"                         json:"skip-ci-commit-markers"`
+	SkipCICommitMarkersList string `default:"[skip ci],[ci skip],[skip pac]" json:"skip-ci-commit-markers-list"`
+
+	ConcurrencyPriorityAging   string `default:"5m"                          json:"concurrency-priority-aging"`
+	ConcurrencyGlobalLimit     int    `json:"concurrency-global-limit"`
+	ConcurrencyNamespaceLimit  int    `json:"concurrency-namespace-limit"`
+	ConcurrencyNamespaceLimits string `json:"concurrency-namespace-limits"`
+
+	RetentionSuccessful    string `json:"retention-successful"`
+	RetentionFailed        string `json:"retention-failed"`
+	RetentionKeepLast      int    `json:"retention-keep-last"`
+	RetentionCheckInterval string `default:"1h"                          json:"retention-check-interval"`
+
+	ArchiveURL    string `json:"archive-url"`
+	ArchiveSecret string `json:"archive-secret"`
+
 	CustomConsoleName         string `json:"custom-console-name"`
 	CustomConsoleURL          string `json:"custom-console-url"`
 	CustomConsolePRdetail     string `json:"custom-console-url-pr-details"`
@@ -88,6 +116,129 @@ func (s *Settings) DeepCopy(out *Settings) {
 	*out = *s
 }
 
+// SkipCIMarkers returns the list of markers which, when found in a commit
+// title, skip the PipelineRuns for that commit. It returns an empty list
+// when the feature has been disabled.
+func (s *Settings) SkipCIMarkers() []string {
+	if !s.SkipCICommitMarkers {
+		return []string{}
+	}
+	markers := []string{}
+	for _, marker := range strings.Split(s.SkipCICommitMarkersList, ",") {
+		if marker = strings.TrimSpace(marker); marker != "" {
+			markers = append(markers, marker)
+		}
+	}
+	return markers
+}
+
+// PriorityAging returns how long a PipelineRun waits in a concurrency queue to
+// gain one level of priority, so the ones with a low priority are eventually
+// started.
+func (s *Settings) PriorityAging() time.Duration {
+	aging, err := time.ParseDuration(s.ConcurrencyPriorityAging)
+	if err != nil || aging <= 0 {
+		return ConcurrencyPriorityAgingDefaultValue
+	}
+	return min(aging, ConcurrencyPriorityAgingMaxValue)
+}
+
+// ConcurrencyQuotas are the limits of PipelineRuns running at the same time
+// on the whole cluster and per namespace, enforced on top of the
+// concurrency_limit of the Repositories. A zero limit means unlimited.
+type ConcurrencyQuotas struct {
+	Global     int
+	Namespace  int
+	Namespaces map[string]int
+}
+
+// NamespaceLimit returns the limit of running PipelineRuns of a namespace,
+// the limits set for a namespace take precedence over the default one.
+func (q ConcurrencyQuotas) NamespaceLimit(namespace string) int {
+	if limit, ok := q.Namespaces[namespace]; ok {
+		return limit
+	}
+	return q.Namespace
+}
+
+// Enabled checks if the PipelineRuns of a namespace are subject to a quota.
+func (q ConcurrencyQuotas) Enabled(namespace string) bool {
+	return q.Global > 0 || q.NamespaceLimit(namespace) > 0
+}
+
+// ConcurrencyQuotas returns the cluster-wide and per-namespace concurrency
+// quotas, the invalid namespace limits are skipped.
+func (s *Settings) ConcurrencyQuotas() ConcurrencyQuotas {
+	namespaces, _ := parseNamespaceLimits(s.ConcurrencyNamespaceLimits)
+	return ConcurrencyQuotas{
+		Global:     max(s.ConcurrencyGlobalLimit, 0),
+		Namespace:  max(s.ConcurrencyNamespaceLimit, 0),
+		Namespaces: namespaces,
+	}
+}
+
+// parseNamespaceLimits parses a comma separated list of namespace=limit, it
+// returns the valid limits along with an error for the invalid ones.
+func parseNamespaceLimits(value string) (map[string]int, error) {
+	limits := map[string]int{}
+	invalid := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		namespace, rawLimit, found := strings.Cut(entry, "=")
+		namespace = strings.TrimSpace(namespace)
+		limit, err := strconv.Atoi(strings.TrimSpace(rawLimit))
+		if !found || namespace == "" || err != nil || limit < 0 {
+			invalid = append(invalid, entry)
+			continue
+		}
+		limits[namespace] = limit
+	}
+	if len(invalid) > 0 {
+		return limits, fmt.Errorf("invalid namespace limits %q, must be namespace=limit", strings.Join(invalid, ","))
+	}
+	return limits, nil
+}
+
+// RetentionInterval returns how often the watcher deletes the completed
+// PipelineRuns which are past their retention.
+func (s *Settings) RetentionInterval() time.Duration {
+	interval, err := time.ParseDuration(s.RetentionCheckInterval)
+	if err != nil || interval <= 0 {
+		return RetentionCheckIntervalDefaultValue
+	}
+	return max(interval, RetentionCheckIntervalMinValue)
+}
+
+// ParseRetention parses how long a completed PipelineRun is kept, as a Go
+// duration or as a number of days like "14d". An empty value is a zero
+// duration, the PipelineRuns are kept forever.
+func ParseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	var retention time.Duration
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q, must be a duration like 48h or a number of days like 14d", value)
+		}
+		retention = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention %q, must be a duration like 48h or a number of days like 14d", value)
+		}
+		retention = d
+	}
+	if retention <= 0 {
+		return 0, fmt.Errorf("invalid retention %q, must be positive", value)
+	}
+	return retention, nil
+}
+
 func DefaultSettings() Settings {
 	newSettings := &Settings{}
 	hubCatalog := &sync.Map{}
@@ -110,6 +261,12 @@ func DefaultValidators() map[string]func(string) error {
 		"CustomConsoleURL":           isValidURL,
 		"CustomConsolePRTaskLog":     startWithHTTPorHTTPS,
 		"CustomConsolePRDetail":      startWithHTTPorHTTPS,
+		"ConcurrencyPriorityAging":   isValidPositiveDuration,
+		"ConcurrencyNamespaceLimits": isValidNamespaceLimits,
+		"RetentionSuccessful":        isValidRetention,
+		"RetentionFailed":            isValidRetention,
+		"RetentionCheckInterval":     isValidPositiveDuration,
+		"ArchiveURL":                 isValidArchiveURL,
 	}
 }
 
@@ -146,6 +303,33 @@ func isValidURL(rawURL string) error {
 	return nil
 }
 
+// isValidArchiveURL checks the URL of the archive sink, a directory with
+// file://, an S3 bucket with s3:// or a Tekton Results API with results+http://
+// or results+https://.
+func isValidArchiveURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid value for URL, error: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return fmt.Errorf("invalid archive URL %q, the directory is missing", rawURL)
+		}
+	case "s3":
+		if u.Host == "" {
+			return fmt.Errorf("invalid archive URL %q, the bucket is missing", rawURL)
+		}
+	case "results+http", "results+https":
+		if u.Host == "" {
+			return fmt.Errorf("invalid archive URL %q, the host is missing", rawURL)
+		}
+	default:
+		return fmt.Errorf("invalid archive URL %q, must start with file://, s3://, results+http:// or results+https://", rawURL)
+	}
+	return nil
+}
+
 func isValidRegex(regex string) error {
 	if _, err := regexp.Compile(regex); err != nil {
 		return fmt.Errorf("invalid regex: %w", err)
@@ -153,6 +337,27 @@ func isValidRegex(regex string) error {
 	return nil
 }
 
+func isValidPositiveDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid duration: must be positive")
+	}
+	return nil
+}
+
+func isValidRetention(value string) error {
+	_, err := ParseRetention(value)
+	return err
+}
+
+func isValidNamespaceLimits(value string) error {
+	_, err := parseNamespaceLimits(value)
+	return err
+}
+
 func startWithHTTPorHTTPS(url string) error {
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
 		return fmt.Errorf("invalid value, must start with http:// or https://")
@@ -3,6 +3,7 @@ package settings
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/logger"
 	"gotest.tools/v3/assert"
@@ -42,6 +43,18 @@ func TestSyncConfig(t *testing.T) {
 				EnableCancelInProgressOnPullRequests: false,
 				EnableCancelInProgressOnPush:         false,
 				SkipPushEventForPRCommits:            true,
+				SkipCICommitMarkers:                  false,
+				SkipCICommitMarkersList:              "[skip ci],[ci skip],[skip pac]",
+				ConcurrencyPriorityAging:             "5m",
+				ConcurrencyGlobalLimit:               0,
+				ConcurrencyNamespaceLimit:            0,
+				ConcurrencyNamespaceLimits:           "",
+				RetentionSuccessful:                  "",
+				RetentionFailed:                      "",
+				RetentionKeepLast:                    0,
+				RetentionCheckInterval:               "1h",
+				ArchiveURL:                           "",
+				ArchiveSecret:                        "",
 				CustomConsoleName:                    "",
 				CustomConsoleURL:                     "",
 				CustomConsolePRdetail:                "",
@@ -77,6 +90,18 @@ func TestSyncConfig(t *testing.T) {
 				"custom-console-url-namespace":            "https://custom-console-namespace",
 				"remember-ok-to-test":                     "false",
 				"skip-push-event-for-pr-commits":          "true",
+				"skip-ci-commit-markers":                  "true",
+				"skip-ci-commit-markers-list":             "[no ci]",
+				"concurrency-priority-aging":              "10m",
+				"concurrency-global-limit":                "50",
+				"concurrency-namespace-limit":             "10",
+				"concurrency-namespace-limits":            "team-a=20,team-b=0",
+				"retention-successful":                    "2d",
+				"retention-failed":                        "336h",
+				"retention-keep-last":                     "3",
+				"retention-check-interval":                "30m",
+				"archive-url":                             "s3://archive/pac?region=eu-west-1",
+				"archive-secret":                          "archive-credentials",
 			},
 			expectedStruct: Settings{
 				ApplicationName:                      "pac-pac",
@@ -101,6 +126,18 @@ func TestSyncConfig(t *testing.T) {
 				EnableCancelInProgressOnPullRequests: false,
 				EnableCancelInProgressOnPush:         false,
 				SkipPushEventForPRCommits:            true,
+				SkipCICommitMarkers:                  true,
+				SkipCICommitMarkersList:              "[no ci]",
+				ConcurrencyPriorityAging:             "10m",
+				ConcurrencyGlobalLimit:               50,
+				ConcurrencyNamespaceLimit:            10,
+				ConcurrencyNamespaceLimits:           "team-a=20,team-b=0",
+				RetentionSuccessful:                  "2d",
+				RetentionFailed:                      "336h",
+				RetentionKeepLast:                    3,
+				RetentionCheckInterval:               "30m",
+				ArchiveURL:                           "s3://archive/pac?region=eu-west-1",
+				ArchiveSecret:                        "archive-credentials",
 				CustomConsoleName:                    "custom-console",
 				CustomConsoleURL:                     "https://custom-console",
 				CustomConsolePRdetail:                "https://custom-console-pr-details",
@@ -144,6 +181,34 @@ func TestSyncConfig(t *testing.T) {
 			},
 			expectedError: "custom validation failed for field CustomConsolePRTaskLog: invalid value, must start with http:// or https://",
 		},
+		{
+			name: "invalid value for concurrency priority aging",
+			configMap: map[string]string{
+				"concurrency-priority-aging": "0s",
+			},
+			expectedError: "custom validation failed for field ConcurrencyPriorityAging: invalid duration: must be positive",
+		},
+		{
+			name: "invalid value for concurrency namespace limits",
+			configMap: map[string]string{
+				"concurrency-namespace-limits": "team-a=20,team-b",
+			},
+			expectedError: "custom validation failed for field ConcurrencyNamespaceLimits: invalid namespace limits \"team-b\", must be namespace=limit",
+		},
+		{
+			name: "invalid value for archive url",
+			configMap: map[string]string{
+				"archive-url": "ftp://archive",
+			},
+			expectedError: "custom validation failed for field ArchiveURL: invalid archive URL \"ftp://archive\", must start with file://, s3://, results+http:// or results+https://",
+		},
+		{
+			name: "invalid value for retention",
+			configMap: map[string]string{
+				"retention-failed": "two weeks",
+			},
+			expectedError: "custom validation failed for field RetentionFailed: invalid retention \"two weeks\", must be a duration like 48h or a number of days like 14d",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -179,3 +244,136 @@ func TestDefaultSettings(t *testing.T) {
 	assert.Assert(t, ok)
 	assert.Equal(t, catalog.Index, "default")
 }
+
+func TestSkipCIMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     []string
+	}{
+		{
+			name:     "disabled by default",
+			settings: DefaultSettings(),
+			want:     []string{},
+		},
+		{
+			name: "default markers",
+			settings: Settings{
+				SkipCICommitMarkers:     true,
+				SkipCICommitMarkersList: DefaultSettings().SkipCICommitMarkersList,
+			},
+			want: []string{"[skip ci]", "[ci skip]", "[skip pac]"},
+		},
+		{
+			name: "disabled",
+			settings: Settings{
+				SkipCICommitMarkers:     false,
+				SkipCICommitMarkersList: "[skip ci]",
+			},
+			want: []string{},
+		},
+		{
+			name: "custom markers with spaces and empty entries",
+			settings: Settings{
+				SkipCICommitMarkers:     true,
+				SkipCICommitMarkersList: " [no ci] ,, [skip tests]",
+			},
+			want: []string{"[no ci]", "[skip tests]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.settings.SkipCIMarkers(), tt.want)
+		})
+	}
+}
+
+func TestPriorityAging(t *testing.T) {
+	tests := []struct {
+		name  string
+		aging string
+		want  time.Duration
+	}{
+		{
+			name:  "default",
+			aging: DefaultSettings().ConcurrencyPriorityAging,
+			want:  5 * time.Minute,
+		},
+		{
+			name:  "custom",
+			aging: "1h30m",
+			want:  90 * time.Minute,
+		},
+		{
+			name:  "invalid falls back to the default",
+			aging: "soon",
+			want:  ConcurrencyPriorityAgingDefaultValue,
+		},
+		{
+			name:  "too long",
+			aging: "8760h",
+			want:  ConcurrencyPriorityAgingMaxValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Settings{ConcurrencyPriorityAging: tt.aging}
+			assert.Equal(t, s.PriorityAging(), tt.want)
+		})
+	}
+}
+
+func TestConcurrencyQuotas(t *testing.T) {
+	s := Settings{
+		ConcurrencyGlobalLimit:     50,
+		ConcurrencyNamespaceLimit:  10,
+		ConcurrencyNamespaceLimits: "team-a=20, team-b=0,invalid",
+	}
+	quotas := s.ConcurrencyQuotas()
+	assert.Equal(t, quotas.Global, 50)
+	assert.Equal(t, quotas.NamespaceLimit("team-a"), 20)
+	// a namespace can be exempted from the default limit
+	assert.Equal(t, quotas.NamespaceLimit("team-b"), 0)
+	assert.Equal(t, quotas.NamespaceLimit("other"), 10)
+	assert.Assert(t, quotas.Enabled("team-b"))
+
+	quotas = (&Settings{ConcurrencyNamespaceLimits: "team-a=5"}).ConcurrencyQuotas()
+	assert.Assert(t, quotas.Enabled("team-a"))
+	assert.Assert(t, !quotas.Enabled("other"))
+	defaults := DefaultSettings()
+	assert.Assert(t, !defaults.ConcurrencyQuotas().Enabled("other"))
+}
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "empty keeps forever", want: 0},
+		{name: "days", value: "14d", want: 14 * 24 * time.Hour},
+		{name: "duration", value: " 36h ", want: 36 * time.Hour},
+		{name: "invalid days", value: "twod", wantErr: "invalid retention \"twod\""},
+		{name: "invalid", value: "forever", wantErr: "invalid retention \"forever\""},
+		{name: "zero", value: "0d", wantErr: "must be positive"},
+		{name: "negative", value: "-1h", wantErr: "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRetention(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestRetentionInterval(t *testing.T) {
+	assert.Equal(t, (&Settings{RetentionCheckInterval: "30m"}).RetentionInterval(), 30*time.Minute)
+	assert.Equal(t, (&Settings{RetentionCheckInterval: "1s"}).RetentionInterval(), RetentionCheckIntervalMinValue)
+	assert.Equal(t, (&Settings{RetentionCheckInterval: "bad"}).RetentionInterval(), RetentionCheckIntervalDefaultValue)
+}
@@ -40,6 +40,12 @@ func StringToType(s string) Trigger {
 		return Comment
 	case PullRequestLabeled.String():
 		return PullRequestLabeled
+	case MergeGroup.String():
+		return MergeGroup
+	case Hold.String():
+		return Hold
+	case Help.String():
+		return Help
 	}
 	return ""
 }
@@ -49,7 +55,10 @@ const (
 	CheckRunRerequested   Trigger = "check-run-rerequested"
 	CheckSuiteRerequested Trigger = "check-suite-rerequested"
 	Comment               Trigger = "comment"
+	Help                  Trigger = "help"
+	Hold                  Trigger = "hold"
 	Incoming              Trigger = "incoming"
+	MergeGroup            Trigger = "merge_group"
 	PullRequestLabeled    Trigger = "pull_request_labeled"
 	OkToTest              Trigger = "ok-to-test"
 	PullRequestClosed     Trigger = "pull_request_closed"
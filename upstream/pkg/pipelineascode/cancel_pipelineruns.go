@@ -91,6 +91,7 @@ func (p *PacRun) cancelAllInProgressBelongingToClosedPullRequest(ctx context.Con
 // cancellation is not supported with concurrency limits. It then retrieves the original pull request name
 // from the annotations and lists all PipelineRuns with matching labels. For each PipelineRun that is not
 // already done, cancelled, or gracefully stopped, it patches the PipelineRun to cancel it.
+// A PipelineRun of a concurrency group cancels the other PipelineRuns of its group instead.
 func (p *PacRun) cancelInProgressMatchingPipelineRun(ctx context.Context, matchPR *tektonv1.PipelineRun, repo *v1alpha1.Repository) error {
 	if matchPR == nil {
 		return nil
@@ -117,6 +118,10 @@ func (p *PacRun) cancelInProgressMatchingPipelineRun(ctx context.Context, matchP
 
 	p.run.Clients.Log.Infof("cancel-in-progress for event %s is enabled %s", string(p.event.TriggerTarget), cancellingVia)
 
+	if group := matchPR.GetAnnotations()[keys.ConcurrencyGroup]; group != "" {
+		return p.cancelInProgressConcurrencyGroup(ctx, matchPR, repo, group)
+	}
+
 	// As PipelineRuns are filtered by name, OriginalPRName should be taken from
 	// labels instead of annotations because of constraints imposed by kube API.
 	prName, ok := matchPR.GetLabels()[keys.OriginalPRName]
@@ -166,6 +171,33 @@ func (p *PacRun) cancelInProgressMatchingPipelineRun(ctx context.Context, matchP
 	return nil
 }
 
+// cancelInProgressConcurrencyGroup cancels the PipelineRuns of the repository
+// created before the given PipelineRun in the same concurrency group, whatever
+// the pull request or the branch they come from, the queued ones included.
+// The PipelineRuns of the same commit don't cancel each other, they are
+// queued one after the other. The group has its own queue so this works
+// with a concurrency limit.
+func (p *PacRun) cancelInProgressConcurrencyGroup(ctx context.Context, matchPR *tektonv1.PipelineRun, repo *v1alpha1.Repository, group string) error {
+	labelSelector := getLabelSelector(map[string]string{
+		keys.URLRepository: formatting.CleanValueKubernetes(p.event.Repository),
+	}, selection.Equals)
+	p.run.Clients.Log.Infof("cancel-in-progress: selecting pipelineRuns of concurrency group %s to cancel with labels: %v", group, labelSelector)
+	prs, err := p.run.Clients.Tekton.TektonV1().PipelineRuns(matchPR.GetNamespace()).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list pipelineRuns : %w", err)
+	}
+
+	p.cancelPipelineRuns(ctx, prs, repo, func(pr tektonv1.PipelineRun) bool {
+		if pr.GetAnnotations()[keys.ConcurrencyGroup] != group || pr.GetAnnotations()[keys.SHA] == matchPR.GetAnnotations()[keys.SHA] {
+			return false
+		}
+		return pr.CreationTimestamp.Before(&matchPR.CreationTimestamp)
+	})
+	return nil
+}
+
 // cancelPipelineRunsOpsComment cancels all PipelineRuns associated with a given repository and pull request.
 // when the user issue a cancel comment.
 func (p *PacRun) cancelPipelineRunsOpsComment(ctx context.Context, repo *v1alpha1.Repository) error {
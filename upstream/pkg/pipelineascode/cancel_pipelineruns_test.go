@@ -1120,6 +1120,82 @@ func TestCancelInProgressMatchingPipelineRun(t *testing.T) {
 			wantErrString:         "cancel in progress is not supported with concurrency limit",
 			wantLog:               "cancel-in-progress: cancelling pipelinerun foo/",
 		},
+		{
+			name: "cancel/concurrency group with concurrency limit",
+			event: &info.Event{
+				Repository:        "foo",
+				SHA:               "newsha",
+				HeadBranch:        "head",
+				EventType:         string(triggertype.PullRequest),
+				TriggerTarget:     triggertype.PullRequest,
+				PullRequestNumber: pullReqNumber,
+			},
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pr-new",
+						Namespace:         "foo",
+						Labels:            fooRepoLabels,
+						CreationTimestamp: metav1.Unix(100, 0),
+						Annotations: map[string]string{
+							keys.CancelInProgress: "true",
+							keys.ConcurrencyGroup: "deploy-main",
+							keys.SHA:              "newsha",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pr-old",
+						Namespace:         "foo",
+						Labels:            fooRepoLabels,
+						CreationTimestamp: metav1.Unix(10, 0),
+						Annotations: map[string]string{
+							keys.ConcurrencyGroup: "deploy-main",
+							keys.SHA:              "oldsha",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pr-same-commit",
+						Namespace:         "foo",
+						Labels:            fooRepoLabels,
+						CreationTimestamp: metav1.Unix(10, 0),
+						Annotations: map[string]string{
+							keys.ConcurrencyGroup: "deploy-main",
+							keys.SHA:              "newsha",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "pr-other-group",
+						Namespace:         "foo",
+						Labels:            fooRepoLabels,
+						CreationTimestamp: metav1.Unix(10, 0),
+						Annotations: map[string]string{
+							keys.ConcurrencyGroup: "deploy-release",
+							keys.SHA:              "oldsha",
+						},
+					},
+				},
+			},
+			repo: &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "foo",
+				},
+				Spec: v1alpha1.RepositorySpec{
+					URL:              "https://github.com/fooorg/foo",
+					ConcurrencyLimit: github.Ptr(1),
+				},
+			},
+			cancelledPipelineRuns: map[string]bool{
+				"pr-old": true,
+			},
+			wantLog: "cancel-in-progress: selecting pipelineRuns of concurrency group deploy-main to cancel",
+		},
 	}
 
 	for _, tt := range tests {
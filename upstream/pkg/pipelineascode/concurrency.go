@@ -2,8 +2,10 @@ package pipelineascode
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sort"
 	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -85,3 +87,42 @@ func getOrderByName(runs []*v1.PipelineRun) string {
 	}
 	return order
 }
+
+// getOrderByGroup returns the execution order of the PipelineRuns of every
+// concurrency group, keyed by the group name, since each group has its own
+// queue. The PipelineRuns without a group share the order of the queue of the
+// Repository under the empty name.
+func getOrderByGroup(runs []*v1.PipelineRun) map[string]string {
+	groups := map[string][]*v1.PipelineRun{}
+	for _, run := range runs {
+		group := run.GetAnnotations()[keys.ConcurrencyGroup]
+		groups[group] = append(groups[group], run)
+	}
+	orders := map[string]string{}
+	for group, groupRuns := range groups {
+		orders[group] = getOrderByName(groupRuns)
+	}
+	return orders
+}
+
+// throttledByQuotas checks if the PipelineRuns of the namespace are limited by
+// the cluster-wide or per-namespace concurrency quotas.
+func (p *PacRun) throttledByQuotas(namespace string) bool {
+	if p.pacInfo == nil {
+		return false
+	}
+	return p.pacInfo.ConcurrencyQuotas().Enabled(namespace)
+}
+
+// hasConcurrencyGroup checks if the PipelineRun is queued in a concurrency
+// group, the group computed with a CEL expression may still turn out empty.
+func hasConcurrencyGroup(pr *v1.PipelineRun) bool {
+	if pr == nil {
+		return false
+	}
+	annotations := pr.GetAnnotations()
+	if _, ok := annotations[keys.ConcurrencyGroupCEL]; ok {
+		return true
+	}
+	return strings.TrimSpace(annotations[keys.ConcurrencyGroup]) != ""
+}
@@ -3,6 +3,7 @@ package pipelineascode
 import (
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"gotest.tools/v3/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,3 +45,34 @@ func TestExecutionOrder_SinglePRun(t *testing.T) {
 	assert.Equal(t, order, "test/abc")
 	assert.Equal(t, len(runs), 1)
 }
+
+func TestGetOrderByGroup(t *testing.T) {
+	testNs := "test"
+	group := func(name string) map[string]string {
+		return map[string]string{keys.ConcurrencyGroup: name}
+	}
+	runs := []*tektonv1.PipelineRun{
+		{ObjectMeta: metav1.ObjectMeta{Name: "abc", Namespace: testNs}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "def", Namespace: testNs, Annotations: group("deploy-main")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "mno", Namespace: testNs}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pqr", Namespace: testNs, Annotations: group("deploy-main")}},
+	}
+	assert.DeepEqual(t, getOrderByGroup(runs), map[string]string{
+		"":            "test/abc,test/mno",
+		"deploy-main": "test/def,test/pqr",
+	})
+}
+
+func TestHasConcurrencyGroup(t *testing.T) {
+	assert.Assert(t, !hasConcurrencyGroup(nil))
+	assert.Assert(t, !hasConcurrencyGroup(&tektonv1.PipelineRun{}))
+	assert.Assert(t, !hasConcurrencyGroup(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{keys.ConcurrencyGroup: " "},
+	}}))
+	assert.Assert(t, hasConcurrencyGroup(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{keys.ConcurrencyGroup: "deploy-main"},
+	}}))
+	assert.Assert(t, hasConcurrencyGroup(&tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{keys.ConcurrencyGroupCEL: "'deploy-' + target_branch"},
+	}}))
+}
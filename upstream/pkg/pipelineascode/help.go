@@ -0,0 +1,196 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/resolve"
+)
+
+type helpCommand struct {
+	command     string
+	description string
+}
+
+// helpCommands are the GitOps commands available on a pull request, in the
+// order they are listed in the /help reply.
+var helpCommands = []helpCommand{
+	{"/test", "Run all the PipelineRuns matching this pull request."},
+	{"/test <pipelinerun>", "Run a single PipelineRun, even if it doesn't match this pull request."},
+	{"/retest", "Run again the PipelineRuns which have failed on the current commit."},
+	{"/retest <pipelinerun>", "Run again a single PipelineRun."},
+	{"/retest-failed", "Run again only the PipelineRuns whose last run on the current commit has failed or has been cancelled."},
+	{"/cancel", "Cancel all the running PipelineRuns of this pull request."},
+	{"/cancel <pipelinerun>", "Cancel a single running PipelineRun."},
+	{"/ok-to-test", "Allow the CI to run on a pull request from an external contributor."},
+	{"/hold", "Put the CI of this pull request on hold, use `/hold cancel` to cancel the running PipelineRuns as well."},
+	{"/unhold", "Resume the CI of this pull request and run the PipelineRuns on the current commit."},
+	{"/help", "Show this message."},
+}
+
+// helpCommentMarker is in the /help replies to the senders who are not
+// allowed to trigger the CI, there is only one of them per pull request which
+// is updated on every /help so they can't be used to spam the pull request.
+const helpCommentMarker = "<!-- pipelines-as-code-help -->"
+
+// helpPipelineRun is a PipelineRun listed in the /help reply, matched is set
+// when it matches the pull request and onComment is the regexp of the
+// on-comment annotation when it is triggered by a comment.
+type helpPipelineRun struct {
+	name      string
+	matched   bool
+	onComment string
+}
+
+// replyHelpComment replies to a /help comment with the PipelineRuns matching
+// the pull request and the GitOps commands the sender is allowed to use. The
+// .tekton directory of the pull request is not trusted when the sender is
+// not allowed to trigger the CI, it is not read and only the commands they
+// can use are listed.
+func (p *PacRun) replyHelpComment(ctx context.Context, repo *v1alpha1.Repository) error {
+	allowed, err := p.vcx.IsAllowed(ctx, p.event)
+	if err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryHelp",
+			fmt.Sprintf("cannot verify the permissions of %s, only listing the help command: %s", p.event.Sender, err.Error()))
+		allowed = false
+	}
+
+	var pipelineRuns []helpPipelineRun
+	var params []string
+	if allowed {
+		pipelineRuns, params = p.helpPipelineRuns(ctx, repo), customParamsNames(repo)
+	}
+	comment := formatHelpComment(p.event.Sender, pipelineRuns, params, allowed)
+	updateMarker := ""
+	if !allowed {
+		comment = helpCommentMarker + "\n" + comment
+		updateMarker = helpCommentMarker
+	}
+	if err := p.vcx.CreateComment(ctx, p.event, comment, updateMarker); err != nil {
+		return fmt.Errorf("cannot reply to the /help comment: %w", err)
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryHelp",
+		fmt.Sprintf("replied to the /help comment of %s on pull request %d", p.event.Sender, p.event.PullRequestNumber))
+	return nil
+}
+
+// helpPipelineRuns returns the PipelineRuns of the .tekton directory matching
+// a pull request event, followed by the ones only triggered by a comment.
+func (p *PacRun) helpPipelineRuns(ctx context.Context, repo *v1alpha1.Repository) []helpPipelineRun {
+	provenance := "source"
+	if repo.Spec.Settings != nil && repo.Spec.Settings.PipelineRunProvenance != "" {
+		provenance = repo.Spec.Settings.PipelineRunProvenance
+	}
+	rawTemplates, err := p.vcx.GetTektonDir(ctx, p.event, tektonDir, provenance)
+	if err != nil || rawTemplates == "" {
+		return nil
+	}
+	types, err := resolve.ReadTektonTypes(ctx, p.logger, rawTemplates)
+	if err != nil {
+		return nil
+	}
+	pipelineRuns, err := resolve.MetadataResolve(types.PipelineRuns)
+	if err != nil {
+		return nil
+	}
+
+	// match as if the pull request had been updated
+	event := info.NewEvent()
+	p.event.DeepCopyInto(event)
+	event.EventType = triggertype.PullRequest.String()
+	event.TriggerComment = ""
+	matches, _ := matcher.MatchPipelinerunByAnnotation(ctx, p.logger, pipelineRuns, p.run, event, p.vcx, p.eventEmitter, repo)
+
+	ret := []helpPipelineRun{}
+	for _, match := range matches {
+		ret = append(ret, helpPipelineRun{name: match.PipelineRun.GetAnnotations()[keys.OriginalPRName], matched: true})
+	}
+	for _, pr := range pipelineRuns {
+		onComment, ok := pr.GetAnnotations()[keys.OnComment]
+		if !ok {
+			continue
+		}
+		name := pr.GetAnnotations()[keys.OriginalPRName]
+		if i := slices.IndexFunc(ret, func(h helpPipelineRun) bool { return h.name == name }); i >= 0 {
+			ret[i].onComment = onComment
+			continue
+		}
+		ret = append(ret, helpPipelineRun{name: name, onComment: onComment})
+	}
+	return ret
+}
+
+// customParamsNames returns the names of the custom parameters of the
+// Repository, they can be overridden with key=value arguments of the GitOps
+// commands.
+func customParamsNames(repo *v1alpha1.Repository) []string {
+	names := []string{}
+	if repo.Spec.Params == nil {
+		return names
+	}
+	for _, param := range *repo.Spec.Params {
+		if param.Name != "" && !slices.Contains(names, param.Name) {
+			names = append(names, param.Name)
+		}
+	}
+	return names
+}
+
+func formatHelpComment(sender string, pipelineRuns []helpPipelineRun, params []string, allowed bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@%s, here are the GitOps commands you can use on this pull request.\n\n", sender)
+
+	// the PipelineRuns and their parameters are only listed to the senders
+	// allowed to run them
+	if allowed {
+		b.WriteString("**PipelineRuns**\n\n")
+		if len(pipelineRuns) == 0 {
+			fmt.Fprintf(&b, "No PipelineRun in the `%s` directory matches this pull request.\n\n", tektonDir)
+		} else {
+			b.WriteString("| PipelineRun | Triggered by |\n|-------------|--------------|\n")
+			for _, pr := range pipelineRuns {
+				triggers := []string{}
+				if pr.matched {
+					triggers = append(triggers, "this pull request")
+				}
+				if pr.onComment != "" {
+					triggers = append(triggers, fmt.Sprintf("a comment matching `%s`", strings.ReplaceAll(pr.onComment, "|", "\\|")))
+				}
+				fmt.Fprintf(&b, "| `%s` | %s |\n", pr.name, strings.Join(triggers, " or "))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if allowed && len(params) > 0 {
+		b.WriteString("**Parameters**\n\n")
+		quoted := make([]string, 0, len(params))
+		for _, param := range params {
+			quoted = append(quoted, fmt.Sprintf("`%s`", param))
+		}
+		fmt.Fprintf(&b, "The parameters %s can be set with `key=value` arguments, for example `/test <pipelinerun> %s=value`.\n\n",
+			strings.Join(quoted, ", "), params[0])
+	}
+
+	b.WriteString("**Commands**\n\n")
+	if !allowed {
+		b.WriteString("You are not allowed to trigger the CI on this repository, a maintainer can comment `/ok-to-test` to let it run on this pull request.\n\n")
+	}
+	b.WriteString("| Command | Description |\n|---------|-------------|\n")
+	for _, cmd := range helpCommands {
+		if !allowed && cmd.command != "/help" {
+			continue
+		}
+		fmt.Fprintf(&b, "| `%s` | %s |\n", cmd.command, cmd.description)
+	}
+	return b.String()
+}
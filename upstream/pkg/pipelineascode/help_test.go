@@ -0,0 +1,235 @@
+package pipelineascode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+const helpTektonDir = `---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: pull-request
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: "echo hello"
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: push
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[push]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: "echo hello"
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: deploy
+  annotations:
+    pipelinesascode.tekton.dev/on-comment: "^/deploy"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: "echo hello"
+---
+apiVersion: tekton.dev/v1
+kind: PipelineRun
+metadata:
+  name: lint
+  annotations:
+    pipelinesascode.tekton.dev/on-event: "[pull_request]"
+    pipelinesascode.tekton.dev/on-target-branch: "[main]"
+    pipelinesascode.tekton.dev/on-comment: "^/lint"
+spec:
+  pipelineSpec:
+    tasks:
+      - name: task
+        taskSpec:
+          steps:
+            - name: step
+              image: registry.access.redhat.com/ubi9/ubi-micro
+              script: "echo hello"
+`
+
+func TestHelpPipelineRuns(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	cs := &params.Run{
+		Clients: clients.Clients{
+			Log:  logger,
+			Kube: stdata.Kube,
+		},
+	}
+	event := &info.Event{
+		URL:               "https://github.com/fooorg/foo",
+		TriggerTarget:     triggertype.PullRequest,
+		EventType:         "help-comment",
+		BaseBranch:        "main",
+		HeadBranch:        "feature",
+		PullRequestNumber: pullReqNumber,
+		TriggerComment:    "/help",
+	}
+	vcx := &testprovider.TestProviderImp{TektonDirTemplate: helpTektonDir}
+	pac := NewPacs(event, vcx, cs, &info.PacOpts{}, nil, logger, nil)
+
+	got := pac.helpPipelineRuns(ctx, newHeldRepo(""))
+	// lint matches the pull request and has an on-comment, it is only listed once
+	assert.DeepEqual(t, got, []helpPipelineRun{
+		{name: "pull-request", matched: true},
+		{name: "lint", matched: true, onComment: "^/lint"},
+		{name: "deploy", onComment: "^/deploy"},
+	}, cmp.AllowUnexported(helpPipelineRun{}))
+	// the event of the comment has been left untouched
+	assert.Equal(t, event.EventType, "help-comment")
+}
+
+func TestCustomParamsNames(t *testing.T) {
+	repo := newHeldRepo("")
+	assert.DeepEqual(t, customParamsNames(repo), []string{})
+
+	repo.Spec.Params = &[]v1alpha1.Params{
+		{Name: "image"},
+		{Name: ""},
+		{Name: "env", Filter: "pac.event_type == 'pull_request'"},
+		{Name: "env"},
+	}
+	assert.DeepEqual(t, customParamsNames(repo), []string{"image", "env"})
+}
+
+func TestFormatHelpComment(t *testing.T) {
+	tests := []struct {
+		name         string
+		pipelineRuns []helpPipelineRun
+		params       []string
+		allowed      bool
+		contains     []string
+		notContains  []string
+	}{
+		{
+			name: "allowed with pipelineruns and params",
+			pipelineRuns: []helpPipelineRun{
+				{name: "pull-request", matched: true},
+				{name: "lint", matched: true, onComment: "^/lint"},
+				{name: "deploy", onComment: "^/(deploy|ship)"},
+			},
+			params:  []string{"image", "env"},
+			allowed: true,
+			contains: []string{
+				"@sender, here are the GitOps commands",
+				"| `pull-request` | this pull request |",
+				"| `lint` | this pull request or a comment matching `^/lint` |",
+				"| `deploy` | a comment matching `^/(deploy\\|ship)` |",
+				"The parameters `image`, `env` can be set with `key=value` arguments, for example `/test <pipelinerun> image=value`.",
+				"| `/test <pipelinerun>` |",
+				"| `/hold` |",
+				"| `/help` |",
+			},
+			notContains: []string{"You are not allowed"},
+		},
+		{
+			name:    "allowed without pipelineruns",
+			allowed: true,
+			contains: []string{
+				"No PipelineRun in the `.tekton` directory matches this pull request.",
+				"| `/test` |",
+			},
+			notContains: []string{"**Parameters**"},
+		},
+		{
+			name:         "not allowed",
+			pipelineRuns: []helpPipelineRun{{name: "pull-request", matched: true}},
+			params:       []string{"image"},
+			allowed:      false,
+			contains: []string{
+				"You are not allowed to trigger the CI on this repository",
+				"| `/help` |",
+			},
+			notContains: []string{"**PipelineRuns**", "`pull-request`", "| `/test` |", "| `/hold` |", "**Parameters**"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatHelpComment("sender", tt.pipelineRuns, tt.params, tt.allowed)
+			for _, s := range tt.contains {
+				assert.Assert(t, strings.Contains(got, s), "%q not found in %s", s, got)
+			}
+			for _, s := range tt.notContains {
+				assert.Assert(t, !strings.Contains(got, s), "%q found in %s", s, got)
+			}
+		})
+	}
+}
+
+func TestReplyHelpComment(t *testing.T) {
+	for _, allowed := range []bool{true, false} {
+		ctx, _ := rtesting.SetupFakeContext(t)
+		observer, _ := zapobserver.New(zap.InfoLevel)
+		logger := zap.New(observer).Sugar()
+		stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+		cs := &params.Run{Clients: clients.Clients{Log: logger, Kube: stdata.Kube}}
+		event := &info.Event{
+			TriggerTarget:     triggertype.PullRequest,
+			EventType:         "help-comment",
+			BaseBranch:        "main",
+			PullRequestNumber: pullReqNumber,
+			Sender:            "sender",
+		}
+		vcx := &testprovider.TestProviderImp{AllowIT: allowed, TektonDirTemplate: helpTektonDir}
+		pac := NewPacs(event, vcx, cs, &info.PacOpts{}, nil, logger, nil)
+		pac.eventEmitter = events.NewEventEmitter(stdata.Kube, logger)
+
+		assert.NilError(t, pac.replyHelpComment(ctx, newHeldRepo("")))
+		assert.NilError(t, pac.replyHelpComment(ctx, newHeldRepo("")))
+		if allowed {
+			// a new reply for every /help
+			assert.DeepEqual(t, vcx.CommentMarkers, []string{"", ""})
+			assert.Assert(t, strings.Contains(vcx.Comments[0], "| `pull-request` |"), vcx.Comments[0])
+		} else {
+			// the same reply is updated for the senders who are not allowed
+			assert.DeepEqual(t, vcx.CommentMarkers, []string{helpCommentMarker, helpCommentMarker})
+			// and the .tekton directory of the pull request is not listed
+			assert.Assert(t, !strings.Contains(vcx.Comments[0], "**PipelineRuns**"), vcx.Comments[0])
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package pipelineascode
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// onHoldTitle starts with Pending so the GitHub check run gets reused by the
+// PipelineRuns once the pull request is released, like the pending approval
+// one.
+const onHoldTitle = "Pending, on hold until an /unhold"
+
+// heldPullRequests returns the pull request numbers put on hold with the
+// /hold GitOps command, they are stored as a comma separated list in an
+// annotation of the Repository CR so the state survives a controller restart.
+func heldPullRequests(repo *v1alpha1.Repository) []string {
+	value := strings.TrimSpace(repo.GetAnnotations()[keys.HeldPullRequests])
+	if value == "" {
+		return []string{}
+	}
+	held := []string{}
+	for _, number := range strings.Split(value, ",") {
+		if number = strings.TrimSpace(number); number != "" {
+			held = append(held, number)
+		}
+	}
+	return held
+}
+
+// isPullRequestOnHold checks if the pull request of the event has been put on
+// hold.
+func (p *PacRun) isPullRequestOnHold(repo *v1alpha1.Repository) bool {
+	if p.event.TriggerTarget != triggertype.PullRequest || p.event.PullRequestNumber == 0 {
+		return false
+	}
+	// comments which are not a GitOps command are matched later on against
+	// the on-comment annotation, they don't trigger anything by themselves.
+	if p.event.EventType == opscomments.NoOpsCommentEventType.String() {
+		return false
+	}
+	return slices.Contains(heldPullRequests(repo), strconv.Itoa(p.event.PullRequestNumber))
+}
+
+// setPullRequestHold adds or removes the pull request of the event from the
+// held pull requests annotation of the Repository CR. The Repository is
+// fetched again since the one we have may have been merged with the global
+// Repository settings.
+func (p *PacRun) setPullRequestHold(ctx context.Context, repo *v1alpha1.Repository, hold bool) error {
+	number := strconv.Itoa(p.event.PullRequestNumber)
+	if slices.Contains(heldPullRequests(repo), number) == hold {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		lastrepo, err := p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(repo.GetNamespace()).Get(
+			ctx, repo.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		held := heldPullRequests(lastrepo)
+		isHeld := slices.Contains(held, number)
+		switch {
+		case hold && !isHeld:
+			held = append(held, number)
+		case !hold && isHeld:
+			held = slices.DeleteFunc(held, func(n string) bool { return n == number })
+		default:
+			return nil
+		}
+
+		annotations := lastrepo.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if len(held) == 0 {
+			delete(annotations, keys.HeldPullRequests)
+		} else {
+			annotations[keys.HeldPullRequests] = strings.Join(held, ",")
+		}
+		lastrepo.SetAnnotations(annotations)
+		_, err = p.run.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories(lastrepo.GetNamespace()).Update(
+			ctx, lastrepo, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// holdPullRequest puts the pull request on hold when a user issue a /hold
+// comment, the running PipelineRuns of the pull request are cancelled when
+// using `/hold cancel`.
+func (p *PacRun) holdPullRequest(ctx context.Context, repo *v1alpha1.Repository) error {
+	if err := p.setPullRequestHold(ctx, repo, true); err != nil {
+		return fmt.Errorf("cannot put pull request %d on hold: %w", p.event.PullRequestNumber, err)
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryHold",
+		fmt.Sprintf("pull request %d has been put on hold by %s", p.event.PullRequestNumber, p.event.Sender))
+
+	if p.event.CancelPipelineRuns {
+		if err := p.cancelPipelineRunsOpsComment(ctx, repo); err != nil {
+			return err
+		}
+	}
+	p.createOnHoldStatus(ctx, repo)
+	return nil
+}
+
+// unholdPullRequest removes the hold of the pull request when a user issue a
+// /unhold comment, the event is then processed as a pull request update to
+// run the PipelineRuns for the current head SHA. Like the other GitOps
+// commands it overrides the skip CI markers of the commit title.
+func (p *PacRun) unholdPullRequest(ctx context.Context, repo *v1alpha1.Repository) error {
+	if err := p.setPullRequestHold(ctx, repo, false); err != nil {
+		return fmt.Errorf("cannot remove the hold on pull request %d: %w", p.event.PullRequestNumber, err)
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryUnhold",
+		fmt.Sprintf("pull request %d has been released from hold by %s", p.event.PullRequestNumber, p.event.Sender))
+	p.event.EventType = triggertype.PullRequest.String()
+	p.unheld = true
+	return nil
+}
+
+// createOnHoldStatus reports a pending status on the commit while the pull
+// request is on hold.
+func (p *PacRun) createOnHoldStatus(ctx context.Context, repo *v1alpha1.Repository) {
+	status := provider.StatusOpts{
+		Status:     queuedStatus,
+		Title:      onHoldTitle,
+		Text:       "The CI of this pull request is on hold, no PipelineRun will be run until someone comments `/unhold`.",
+		Conclusion: pendingConclusion,
+		DetailsURL: p.event.URL,
+	}
+	if err := p.vcx.CreateStatus(ctx, p.event, status); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus", fmt.Sprintf("cannot create on hold status: %s", err))
+	}
+}
@@ -0,0 +1,245 @@
+package pipelineascode
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	testprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/test/provider"
+
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func newHeldRepo(held string) *v1alpha1.Repository {
+	repo := &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "foo",
+		},
+		Spec: v1alpha1.RepositorySpec{
+			URL: "https://github.com/fooorg/foo",
+		},
+	}
+	if held != "" {
+		repo.SetAnnotations(map[string]string{keys.HeldPullRequests: held})
+	}
+	return repo
+}
+
+func TestHeldPullRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		repo *v1alpha1.Repository
+		want []string
+	}{
+		{
+			name: "no annotation",
+			repo: newHeldRepo(""),
+			want: []string{},
+		},
+		{
+			name: "single pull request",
+			repo: newHeldRepo("11"),
+			want: []string{"11"},
+		},
+		{
+			name: "multiple pull requests with spaces",
+			repo: newHeldRepo(" 11, 12,,13 "),
+			want: []string{"11", "12", "13"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, heldPullRequests(tt.repo), tt.want)
+		})
+	}
+}
+
+func TestIsPullRequestOnHold(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *info.Event
+		repo  *v1alpha1.Repository
+		want  bool
+	}{
+		{
+			name: "pull request on hold",
+			event: &info.Event{
+				TriggerTarget:     triggertype.PullRequest,
+				EventType:         triggertype.PullRequest.String(),
+				PullRequestNumber: 11,
+			},
+			repo: newHeldRepo("10,11"),
+			want: true,
+		},
+		{
+			name: "retest on a pull request on hold",
+			event: &info.Event{
+				TriggerTarget:     triggertype.PullRequest,
+				EventType:         opscomments.RetestAllCommentEventType.String(),
+				PullRequestNumber: 11,
+			},
+			repo: newHeldRepo("11"),
+			want: true,
+		},
+		{
+			name: "another pull request on hold",
+			event: &info.Event{
+				TriggerTarget:     triggertype.PullRequest,
+				EventType:         triggertype.PullRequest.String(),
+				PullRequestNumber: 12,
+			},
+			repo: newHeldRepo("11"),
+		},
+		{
+			name: "no-ops comment on a pull request on hold",
+			event: &info.Event{
+				TriggerTarget:     triggertype.PullRequest,
+				EventType:         opscomments.NoOpsCommentEventType.String(),
+				PullRequestNumber: 11,
+			},
+			repo: newHeldRepo("11"),
+		},
+		{
+			name: "push event",
+			event: &info.Event{
+				TriggerTarget: triggertype.Push,
+				EventType:     triggertype.Push.String(),
+			},
+			repo: newHeldRepo("11"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			pac := NewPacs(tt.event, nil, &params.Run{}, &info.PacOpts{}, nil, logger, nil)
+			assert.Equal(t, pac.isPullRequestOnHold(tt.repo), tt.want)
+		})
+	}
+}
+
+func TestHoldUnholdPullRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		comment       string
+		held          string
+		pipelineRuns  []*pipelinev1.PipelineRun
+		wantHeld      string
+		wantCancelled bool
+		wantEventType string
+	}{
+		{
+			name:          "hold a pull request",
+			comment:       "/hold",
+			wantHeld:      "11",
+			wantEventType: opscomments.HoldCommentEventType.String(),
+		},
+		{
+			name:          "hold an already held pull request",
+			comment:       "/hold",
+			held:          "10,11",
+			wantHeld:      "10,11",
+			wantEventType: opscomments.HoldCommentEventType.String(),
+		},
+		{
+			name:          "hold another pull request",
+			comment:       "/hold",
+			held:          "10",
+			wantHeld:      "10,11",
+			wantEventType: opscomments.HoldCommentEventType.String(),
+		},
+		{
+			name:    "hold and cancel the running pipelineruns",
+			comment: "/hold cancel",
+			pipelineRuns: []*pipelinev1.PipelineRun{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pr-foo",
+						Namespace: "foo",
+						Labels:    fooRepoLabels,
+					},
+				},
+			},
+			wantHeld:      "11",
+			wantCancelled: true,
+			wantEventType: opscomments.HoldCommentEventType.String(),
+		},
+		{
+			name:          "unhold a pull request",
+			comment:       "/unhold",
+			held:          "10,11",
+			wantHeld:      "10",
+			wantEventType: triggertype.PullRequest.String(),
+		},
+		{
+			name:          "unhold the last pull request",
+			comment:       "/unhold",
+			held:          "11",
+			wantEventType: triggertype.PullRequest.String(),
+		},
+		{
+			name:          "unhold a pull request not on hold",
+			comment:       "/unhold",
+			wantEventType: triggertype.PullRequest.String(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			repo := newHeldRepo(tt.held)
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{repo},
+				PipelineRuns: tt.pipelineRuns,
+			})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					Log:            logger,
+					Tekton:         stdata.Pipeline,
+					Kube:           stdata.Kube,
+					PipelineAsCode: stdata.PipelineAsCode,
+				},
+			}
+			event := &info.Event{
+				Repository:        "foo",
+				SHA:               "foosha",
+				TriggerTarget:     triggertype.PullRequest,
+				PullRequestNumber: pullReqNumber,
+			}
+			opscomments.SetEventTypeAndTargetPR(event, tt.comment)
+			pac := NewPacs(event, &testprovider.TestProviderImp{}, cs, &info.PacOpts{}, nil, logger, nil)
+
+			if event.EventType == opscomments.HoldCommentEventType.String() {
+				assert.NilError(t, pac.holdPullRequest(ctx, repo))
+			} else {
+				assert.NilError(t, pac.unholdPullRequest(ctx, repo))
+			}
+			assert.Equal(t, event.EventType, tt.wantEventType)
+
+			got, err := cs.Clients.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("foo").Get(ctx, "foo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			held, ok := got.GetAnnotations()[keys.HeldPullRequests]
+			assert.Equal(t, ok, tt.wantHeld != "")
+			assert.Equal(t, held, tt.wantHeld)
+
+			for _, pr := range tt.pipelineRuns {
+				gotPR, err := cs.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, pr.GetName(), metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, string(gotPR.Spec.Status) == pipelinev1.PipelineRunSpecStatusCancelledRunFinally, tt.wantCancelled)
+			}
+		})
+	}
+}
@@ -31,10 +31,46 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 		return nil, nil, nil
 	}
 
+	if p.event.EventType == opscomments.HelpCommentEventType.String() {
+		// GitOps commands are only listed on pull requests
+		if p.event.TriggerTarget != triggertype.PullRequest {
+			return nil, repo, nil
+		}
+		return nil, repo, p.replyHelpComment(ctx, repo)
+	}
+
+	if p.event.TriggerTarget == triggertype.PullRequest {
+		switch p.event.EventType {
+		case opscomments.HoldCommentEventType.String():
+			return nil, repo, p.holdPullRequest(ctx, repo)
+		case opscomments.UnholdCommentEventType.String():
+			if err := p.unholdPullRequest(ctx, repo); err != nil {
+				return nil, repo, err
+			}
+		default:
+			if p.isPullRequestOnHold(repo) && !p.event.CancelPipelineRuns {
+				p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryOnHold",
+					fmt.Sprintf("skipping PipelineRuns for pull request %d, it is on hold", p.event.PullRequestNumber))
+				p.createOnHoldStatus(ctx, repo)
+				return nil, repo, nil
+			}
+		}
+	}
+
 	if p.event.CancelPipelineRuns {
 		return nil, repo, p.cancelPipelineRunsOpsComment(ctx, repo)
 	}
 
+	if marker := p.skipCIMarker(); marker != "" {
+		msg := fmt.Sprintf("skipping PipelineRuns for commit %s, the commit title contains the %s marker", p.event.SHA, marker)
+		p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositorySkipCI", msg)
+		text := fmt.Sprintf("The commit title contains the `%s` marker, no PipelineRun has been run for this commit. Use the `/test` GitOps command to run them anyway.", marker)
+		if err := p.createNeutralStatus(ctx, "Skipped", text); err != nil {
+			p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryCreateStatus", err.Error())
+		}
+		return nil, repo, nil
+	}
+
 	matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
 	if err != nil {
 		return nil, repo, err
@@ -43,6 +79,26 @@ func (p *PacRun) matchRepoPR(ctx context.Context) ([]matcher.Match, *v1alpha1.Re
 	return matchedPRs, repo, nil
 }
 
+// skipCIMarker returns the skip CI marker found in the commit title when the
+// event is a push or a pull request. GitOps commands like /test are an
+// explicit request from the user and always override the markers.
+func (p *PacRun) skipCIMarker() string {
+	if p.event.TriggerTarget != triggertype.Push && p.event.TriggerTarget != triggertype.PullRequest {
+		return ""
+	}
+	if p.event.EventType == "incoming" || p.event.EventType == opscomments.NoOpsCommentEventType.String() ||
+		opscomments.IsAnyOpsEventType(p.event.EventType) || p.unheld {
+		return ""
+	}
+	title := strings.ToLower(p.event.SHATitle)
+	for _, marker := range p.pacInfo.SkipCIMarkers() {
+		if strings.Contains(title, strings.ToLower(marker)) {
+			return marker
+		}
+	}
+	return ""
+}
+
 // verifyRepoAndUser verifies if the Repo CR exists for the Git Repository,
 // if the user has permission to run CI  and also initialise provider client.
 func (p *PacRun) verifyRepoAndUser(ctx context.Context) (*v1alpha1.Repository, error) {
@@ -136,7 +192,9 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 
 	// Verify whether the sender of the GitOps command (e.g., /test) has the appropriate permissions to
 	// trigger CI on the repository, as any user is able to comment on a pushed commit in open-source repositories.
-	if p.event.TriggerTarget == triggertype.Push && opscomments.IsAnyOpsEventType(p.event.EventType) {
+	// /help is answered to everyone, it is only listing the commands the sender can use.
+	if p.event.TriggerTarget == triggertype.Push && opscomments.IsAnyOpsEventType(p.event.EventType) &&
+		p.event.EventType != opscomments.HelpCommentEventType.String() {
 		status := provider.StatusOpts{
 			Status:       CompletedStatus,
 			Title:        "Permission denied",
@@ -151,8 +209,11 @@ is that what you want? make sure you use -n when generating the secret, eg: echo
 
 	// Check if the submitter is allowed to run this.
 	// on push we don't need to check the policy since the user has pushed to the repo so it has access to it.
+	// on merge group the pull request has already been allowed and only a user with write access can queue it.
 	// on comment we skip it for now, we are going to check later on
-	if p.event.TriggerTarget != triggertype.Push && p.event.EventType != opscomments.NoOpsCommentEventType.String() {
+	// on /help we reply to everyone and only list the commands the sender is allowed to use
+	if p.event.TriggerTarget != triggertype.Push && p.event.TriggerTarget != triggertype.MergeGroup &&
+		p.event.EventType != opscomments.NoOpsCommentEventType.String() && p.event.EventType != opscomments.HelpCommentEventType.String() {
 		status := provider.StatusOpts{
 			Status:       queuedStatus,
 			Title:        "Pending approval, waiting for an /ok-to-test",
@@ -279,7 +340,10 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	// Match the PipelineRun with annotation
 	var matchedPRs []matcher.Match
 	if p.event.TargetTestPipelineRun == "" {
-		if matchedPRs, err = matcher.MatchPipelinerunByAnnotation(ctx, p.logger, pipelineRuns, p.run, p.event, p.vcx, p.eventEmitter, repo); err != nil {
+		var report *matcher.MatchReport
+		matchedPRs, report, err = matcher.MatchPipelinerunByAnnotationWithReport(ctx, p.logger, pipelineRuns, p.run, p.event, p.vcx, p.eventEmitter, repo)
+		p.reportMatch(ctx, repo, report)
+		if err != nil {
 			// Don't fail when you don't have a match between pipeline and annotations
 			p.eventEmitter.EmitMessage(nil, zap.WarnLevel, "RepositoryNoMatch", err.Error())
 			// In a scenario where an external user submits a pull request and the repository owner uses the
@@ -287,7 +351,7 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 			// a neutral check-run will be created on the pull request to indicate that no PipelineRun was triggered
 			if p.event.EventType == opscomments.OkToTestCommentEventType.String() && len(matchedPRs) == 0 {
 				text := fmt.Sprintf("No matching PipelineRun found for the '%s' event in .tekton/ directory. Please ensure that PipelineRun is configured for '%s' event.", p.event.TriggerTarget.String(), p.event.TriggerTarget.String())
-				err = p.createNeutralStatus(ctx, "No PipelineRun matched", text)
+				err = p.createNeutralStatus(ctx, "No PipelineRun matched", text+"\n\n"+report.Markdown())
 				if err != nil {
 					p.eventEmitter.EmitMessage(nil, zap.WarnLevel, "RepositoryCreateStatus", err.Error())
 				}
@@ -379,6 +443,24 @@ func (p *PacRun) getPipelineRunsFromRepo(ctx context.Context, repo *v1alpha1.Rep
 	return matchedPRs, nil
 }
 
+// reportMatch emits the match report as an event on the Repository and posts
+// it as a comment on the pull request when the Repository asks for it.
+func (p *PacRun) reportMatch(ctx context.Context, repo *v1alpha1.Repository, report *matcher.MatchReport) {
+	if len(report.PipelineRuns) == 0 {
+		return
+	}
+	p.eventEmitter.EmitMessage(repo, zap.InfoLevel, "RepositoryMatchReport", report.String())
+
+	if repo.Spec.Settings == nil || repo.Spec.Settings.MatchReportComment == nil || !*repo.Spec.Settings.MatchReportComment ||
+		p.event.TriggerTarget != triggertype.PullRequest || p.event.PullRequestNumber == 0 {
+		return
+	}
+	if err := p.vcx.CreateComment(ctx, p.event, report.Markdown(), matcher.MatchReportCommentMarker); err != nil {
+		p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "PipelineRunCommentCreationError",
+			fmt.Sprintf("failed to create the match report comment: %s", err.Error()))
+	}
+}
+
 func filterRunningPipelineRunOnTargetTest(testPipeline string, prs []*tektonv1.PipelineRun) *tektonv1.PipelineRun {
 	for _, pr := range prs {
 		if prName, ok := pr.GetAnnotations()[apipac.OriginalPRName]; ok {
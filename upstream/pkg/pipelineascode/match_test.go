@@ -4,6 +4,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/matcher"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
@@ -104,6 +106,120 @@ func TestFilterRunningPipelineRunOnTargetTest(t *testing.T) {
 	assert.Assert(t, ret == nil)
 }
 
+func TestSkipCIMarker(t *testing.T) {
+	enabled := settings.DefaultSettings()
+	enabled.SkipCICommitMarkers = true
+	tests := []struct {
+		name          string
+		title         string
+		eventType     string
+		triggerTarget triggertype.Trigger
+		unheld        bool
+		settings      settings.Settings
+		want          string
+	}{
+		{
+			name:          "push with skip ci",
+			title:         "fix typo [skip ci]",
+			eventType:     "push",
+			triggerTarget: triggertype.Push,
+			settings:      enabled,
+			want:          "[skip ci]",
+		},
+		{
+			name:          "pull request with ci skip in another case",
+			title:         "[CI SKIP] update docs",
+			eventType:     "pull_request",
+			triggerTarget: triggertype.PullRequest,
+			settings:      enabled,
+			want:          "[ci skip]",
+		},
+		{
+			name:          "pull request with skip pac",
+			title:         "update docs [skip pac]",
+			eventType:     "pull_request",
+			triggerTarget: triggertype.PullRequest,
+			settings:      enabled,
+			want:          "[skip pac]",
+		},
+		{
+			name:          "no marker",
+			title:         "a normal commit",
+			eventType:     "push",
+			triggerTarget: triggertype.Push,
+			settings:      enabled,
+		},
+		{
+			name:          "test comment overrides the marker",
+			title:         "fix typo [skip ci]",
+			eventType:     opscomments.TestAllCommentEventType.String(),
+			triggerTarget: triggertype.PullRequest,
+			settings:      enabled,
+		},
+		{
+			name:          "unhold comment overrides the marker",
+			title:         "fix typo [skip ci]",
+			eventType:     "pull_request",
+			triggerTarget: triggertype.PullRequest,
+			unheld:        true,
+			settings:      enabled,
+		},
+		{
+			name:          "incoming webhook is never skipped",
+			title:         "fix typo [skip ci]",
+			eventType:     "incoming",
+			triggerTarget: triggertype.Push,
+			settings:      enabled,
+		},
+		{
+			name:          "other events are not skipped",
+			title:         "fix typo [skip ci]",
+			eventType:     "pull_request",
+			triggerTarget: triggertype.PullRequestClosed,
+			settings:      enabled,
+		},
+		{
+			name:          "disabled by default",
+			title:         "fix typo [skip ci]",
+			eventType:     "push",
+			triggerTarget: triggertype.Push,
+			settings:      settings.DefaultSettings(),
+		},
+		{
+			name:          "disabled",
+			title:         "fix typo [skip ci]",
+			eventType:     "push",
+			triggerTarget: triggertype.Push,
+			settings: settings.Settings{
+				SkipCICommitMarkers:     false,
+				SkipCICommitMarkersList: "[skip ci]",
+			},
+		},
+		{
+			name:          "custom markers",
+			title:         "fix typo [no ci]",
+			eventType:     "push",
+			triggerTarget: triggertype.Push,
+			settings: settings.Settings{
+				SkipCICommitMarkers:     true,
+				SkipCICommitMarkersList: "[no ci]",
+			},
+			want: "[no ci]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := info.NewEvent()
+			event.SHATitle = tt.title
+			event.EventType = tt.eventType
+			event.TriggerTarget = tt.triggerTarget
+			p := NewPacs(event, nil, &params.Run{Clients: clients.Clients{}}, &info.PacOpts{Settings: tt.settings}, nil, nil, nil)
+			p.unheld = tt.unheld
+			assert.Equal(t, p.skipCIMarker(), tt.want)
+		})
+	}
+}
+
 func TestGetPipelineRunsFromRepo(t *testing.T) {
 	pullRequestEvent := &info.Event{
 		SHA:           "principale",
@@ -302,6 +418,112 @@ func TestGetPipelineRunsFromRepo(t *testing.T) {
 	}
 }
 
+func TestGetPipelineRunsFromRepoMatchReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    *v1alpha1.Settings
+		wantComment bool
+	}{
+		{
+			name: "event only",
+		},
+		{
+			name:        "event and comment",
+			settings:    &v1alpha1.Settings{MatchReportComment: github.Ptr(true)},
+			wantComment: true,
+		},
+		{
+			name:     "comment disabled",
+			settings: &v1alpha1.Settings{MatchReportComment: github.Ptr(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observerCore, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observerCore).Sugar()
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+
+			event := &info.Event{
+				SHA:               "principale",
+				Organization:      "organizationes",
+				Repository:        "lagaffe",
+				URL:               "https://service/documentation",
+				HeadBranch:        "main",
+				BaseBranch:        "main",
+				Sender:            "fantasio",
+				EventType:         "pull_request",
+				TriggerTarget:     "pull_request",
+				PullRequestNumber: 10,
+			}
+			ghtesthelper.SetupGitTree(t, mux, "testdata/no-match", event, false)
+			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/pulls/%d/files", event.Organization, event.Repository, event.PullRequestNumber),
+				func(rw http.ResponseWriter, _ *http.Request) {
+					fmt.Fprint(rw, "[]")
+				})
+			comment := ""
+			mux.HandleFunc(fmt.Sprintf("/repos/%s/%s/issues/%d/comments", event.Organization, event.Repository, event.PullRequestNumber),
+				func(rw http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet {
+						fmt.Fprint(rw, "[]")
+						return
+					}
+					body := &github.IssueComment{}
+					assert.NilError(t, json.NewDecoder(r.Body).Decode(body))
+					comment = body.GetBody()
+					fmt.Fprint(rw, "{}")
+				})
+
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "testrepo", Namespace: "test"},
+				Spec:       v1alpha1.RepositorySpec{Settings: tt.settings},
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+			cs := &params.Run{
+				Clients: clients.Clients{
+					PipelineAsCode: stdata.PipelineAsCode,
+					Log:            logger,
+					Kube:           stdata.Kube,
+					Tekton:         stdata.Pipeline,
+				},
+			}
+			cs.Clients.SetConsoleUI(consoleui.FallBackConsole{})
+			vcx := &ghprovider.Provider{
+				Token:  github.Ptr("None"),
+				Logger: logger,
+			}
+			vcx.SetGithubClient(fakeclient)
+			pacInfo := &info.PacOpts{Settings: settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+			vcx.SetPacInfo(pacInfo)
+			p := NewPacs(event, vcx, cs, pacInfo, &kitesthelper.KinterfaceTest{}, logger, nil)
+			p.eventEmitter = events.NewEventEmitter(stdata.Kube, logger)
+
+			matchedPRs, err := p.getPipelineRunsFromRepo(ctx, repo)
+			assert.NilError(t, err)
+			assert.Equal(t, len(matchedPRs), 2)
+
+			kevents, err := stdata.Kube.CoreV1().Events(repo.GetNamespace()).List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			reports := []string{}
+			for _, kevent := range kevents.Items {
+				if kevent.Reason == "RepositoryMatchReport" {
+					reports = append(reports, kevent.Message)
+				}
+			}
+			assert.Equal(t, len(reports), 1)
+			assert.Assert(t, strings.HasPrefix(reports[0], "matched 2 of 3 PipelineRun(s): "), reports[0])
+
+			if tt.wantComment {
+				assert.Assert(t, strings.HasPrefix(comment, matcher.MatchReportCommentMarker), comment)
+				assert.Assert(t, strings.Contains(comment, "has matched 2 of 3 PipelineRun(s)"), comment)
+			} else {
+				assert.Equal(t, comment, "")
+			}
+		})
+	}
+}
+
 func TestVerifyRepoAndUser(t *testing.T) {
 	observerCore, _ := zapobserver.New(zap.InfoLevel)
 	logger := zap.New(observerCore).Sugar()
@@ -45,6 +45,9 @@ type PacRun struct {
 	manager      *ConcurrencyManager
 	pacInfo      *info.PacOpts
 	globalRepo   *v1alpha1.Repository
+	// unheld is set when the event is an /unhold comment processed as a
+	// pull request update.
+	unheld bool
 }
 
 func NewPacs(event *info.Event, vcx provider.Interface, run *params.Run, pacInfo *info.PacOpts, k8int kubeinteraction.Interface, logger *zap.SugaredLogger, globalRepo *v1alpha1.Repository) PacRun {
@@ -66,6 +69,10 @@ func (p *PacRun) Run(ctx context.Context) error {
 			if err := p.cancelAllInProgressBelongingToClosedPullRequest(ctx, repo); err != nil {
 				return fmt.Errorf("error cancelling in progress pipelineRuns belonging to pull request %d: %w", p.event.PullRequestNumber, err)
 			}
+			if err := p.setPullRequestHold(ctx, repo, false); err != nil {
+				p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryUnhold",
+					fmt.Sprintf("cannot remove the hold on closed pull request %d: %s", p.event.PullRequestNumber, err))
+			}
 		}
 		return nil
 	}
@@ -86,9 +93,14 @@ func (p *PacRun) Run(ctx context.Context) error {
 	if len(matchedPRs) == 0 {
 		return nil
 	}
-	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0 {
+	if (repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0) || p.throttledByQuotas(repo.GetNamespace()) {
 		p.manager.Enable()
 	}
+	for _, match := range matchedPRs {
+		if hasConcurrencyGroup(match.PipelineRun) {
+			p.manager.Enable()
+		}
+	}
 
 	// set params for the console driver, only used for the custom console ones
 	cp := customparams.NewCustomParams(p.event, repo, p.run, p.k8int, p.eventEmitter, p.vcx)
@@ -144,6 +156,7 @@ func (p *PacRun) Run(ctx context.Context) error {
 
 	order, prs := p.manager.GetExecutionOrder()
 	if order != "" {
+		orders := getOrderByGroup(prs)
 		for _, pr := range prs {
 			wg.Add(1)
 
@@ -154,7 +167,7 @@ func (p *PacRun) Run(ctx context.Context) error {
 					p.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "RepositoryPipelineRun", errMsg)
 					return
 				}
-			}(order, *pr)
+			}(orders[pr.GetAnnotations()[keys.ConcurrencyGroup]], *pr)
 		}
 	}
 	wg.Wait()
@@ -198,8 +211,15 @@ func (p *PacRun) startPR(ctx context.Context, match matcher.Match) (*tektonv1.Pi
 		p.logger.Errorf("Error adding labels/annotations to PipelineRun '%s' in namespace '%s': %v", match.PipelineRun.GetName(), match.Repo.GetNamespace(), err)
 	}
 
-	// if concurrency is defined then start the pipelineRun in pending state
-	if match.Repo.Spec.ConcurrencyLimit != nil && *match.Repo.Spec.ConcurrencyLimit != 0 {
+	if err := matcher.ResolveConcurrencyGroup(ctx, match.PipelineRun, p.event, p.vcx); err != nil {
+		return nil, err
+	}
+
+	// if concurrency is defined then start the pipelineRun in pending state,
+	// the PipelineRuns of a concurrency group are always queued in their group
+	// and the ones of a namespace under concurrency quotas wait for a free slot
+	if (match.Repo.Spec.ConcurrencyLimit != nil && *match.Repo.Spec.ConcurrencyLimit != 0) || match.PipelineRun.GetAnnotations()[keys.ConcurrencyGroup] != "" ||
+		p.throttledByQuotas(match.Repo.GetNamespace()) {
 		// pending status
 		match.PipelineRun.Spec.Status = tektonv1.PipelineRunSpecStatusPending
 	}
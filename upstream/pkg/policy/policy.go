@@ -41,14 +41,16 @@ func (p *Policy) checkAllowed(ctx context.Context, tType triggertype.Trigger) (R
 	var sType []string
 	switch tType {
 	// NOTE: This make /retest /ok-to-test /test bound to the same policy, which is fine from a security standpoint but maybe we want to refine this in the future.
-	case triggertype.OkToTest, triggertype.Retest:
+	// /hold and /unhold are bound to it as well since they start or stop the CI of a pull request,
+	// and /help since it lists the GitOps commands allowed by this policy.
+	case triggertype.OkToTest, triggertype.Retest, triggertype.Hold, triggertype.Help:
 		sType = settings.Policy.OkToTest
 	// apply the same policy for PullRequest and comment
 	// we don't support comments on PRs yet but if we do on the future we will need our own policy
 	case triggertype.PullRequest, triggertype.Comment, triggertype.PullRequestLabeled, triggertype.PullRequestClosed:
 		sType = settings.Policy.PullRequest
 	// NOTE: not supported yet, will imp if it gets requested and reasonable to implement
-	case triggertype.Push, triggertype.Cancel, triggertype.CheckSuiteRerequested, triggertype.CheckRunRerequested, triggertype.Incoming:
+	case triggertype.Push, triggertype.Cancel, triggertype.CheckSuiteRerequested, triggertype.CheckRunRerequested, triggertype.Incoming, triggertype.MergeGroup:
 		return ResultNotSet, ""
 	default:
 		return ResultNotSet, ""
@@ -135,6 +135,31 @@ func TestPolicy_IsAllowed(t *testing.T) {
 			vcsReplyAllowed: true,
 			want:            ResultAllowed,
 		},
+		{
+			name: "allowed/hold same as ok-to-test",
+			fields: fields{
+				repository: newRepoWithPolicy(&v1alpha1.Policy{OkToTest: []string{"ok-to-test"}}),
+				event:      info.NewEvent(),
+			},
+			args: args{
+				tType: triggertype.Hold,
+			},
+			vcsReplyAllowed: true,
+			want:            ResultAllowed,
+		},
+		{
+			name: "disallowed/hold member not in team",
+			fields: fields{
+				repository: newRepoWithPolicy(&v1alpha1.Policy{OkToTest: []string{"ok-to-test"}}),
+				event:      info.NewEvent(),
+			},
+			args: args{
+				tType: triggertype.Hold,
+			},
+			want:                 ResultDisallowed,
+			wantErr:              true,
+			expectedLogsSnippets: []string{"policy check: hold, policy disallowing"},
+		},
 		{
 			name:                 "disallowed/policy set with empty list",
 			expectedLogsSnippets: []string{"policy set and empty with no groups"},
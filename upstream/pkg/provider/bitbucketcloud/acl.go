@@ -2,6 +2,10 @@ package bitbucketcloud
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -9,10 +13,211 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
+	"go.uber.org/zap"
 )
 
+// permissionLevels orders the Bitbucket Cloud project and repository
+// permissions, a `project:` or `repo:` policy entry allows the permission it
+// names and every permission above it.
+var permissionLevels = map[string]int{
+	"read":        1,
+	"write":       2,
+	"create-repo": 3,
+	"admin":       4,
+}
+
+// parsePolicyPermission splits a policy entry in the form of
+// `project:<permission>` or `repo:<permission>` into the scope and the
+// minimum permission level, isPermission is false when the entry is the slug
+// of a workspace group.
+func parsePolicyPermission(entry string) (string, int, bool, error) {
+	scope, permission, found := strings.Cut(strings.TrimSpace(entry), ":")
+	scope = strings.ToLower(scope)
+	if !found || (scope != "project" && scope != "repo") {
+		return "", 0, false, nil
+	}
+	level, ok := permissionLevels[strings.ToLower(strings.TrimSpace(permission))]
+	if !ok {
+		return "", 0, true, fmt.Errorf("invalid permission %q for policy entry: %s", permission, entry)
+	}
+	return scope, level, true, nil
+}
+
+// getAPI does a GET on the Bitbucket Cloud API and decodes the JSON response
+// into out, it is used for the endpoints not covered by the go-bitbucket
+// library. The HTTP status code is returned so callers can handle a not found.
+func (v *Provider) getAPI(ctx context.Context, urlStr string, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
+	if err != nil {
+		return 0, err
+	}
+	if v.Username != nil && v.Token != nil {
+		req.SetBasicAuth(*v.Username, *v.Token)
+	}
+	resp, err := v.Client().HttpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("%s returned status code %d", req.URL.Path, resp.StatusCode)
+	}
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// groupsAPIURL returns the base URL of the 1.0 API, the only one exposing the
+// workspace groups. The 1.0 API is deprecated by Atlassian.
+func (v *Provider) groupsAPIURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(v.Client().GetApiBaseURL(), "/"), "/2.0") + "/1.0"
+}
+
+// isGroupsAPIUnavailable tells if a not found or gone status code returned
+// for the members of a group comes from the 1.0 groups API having been
+// removed rather than from a group that does not exist, the groups of the
+// workspace are listed to tell them apart.
+func (v *Provider) isGroupsAPIUnavailable(ctx context.Context, event *info.Event, status int) bool {
+	if status == http.StatusGone {
+		return true
+	}
+	groups := []any{}
+	status, _ = v.getAPI(ctx, fmt.Sprintf("%s/groups/%s", v.groupsAPIURL(), url.PathEscape(event.Organization)), &groups)
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// isGroupMember checks the membership of the sender in a workspace group,
+// groups are only exposed by the 1.0 API.
+func (v *Provider) isGroupMember(ctx context.Context, event *info.Event, group string) (bool, int, error) {
+	members := []types.User{}
+	status, err := v.getAPI(ctx, fmt.Sprintf("%s/groups/%s/%s/members", v.groupsAPIURL(),
+		url.PathEscape(event.Organization), url.PathEscape(group)), &members)
+	if err != nil {
+		return false, status, err
+	}
+	for _, member := range members {
+		if member.AccountID == event.AccountID {
+			return true, status, nil
+		}
+	}
+	return false, status, nil
+}
+
+// getPermission returns the level of the permission the sender has on the
+// repository or on the project of the repository, zero if none.
+func (v *Provider) getPermission(ctx context.Context, event *info.Event, scope string) (int, error) {
+	apiURL := strings.TrimSuffix(v.Client().GetApiBaseURL(), "/")
+	if scope == "repo" {
+		// this is the highest permission of the user on the repository,
+		// including the one granted through a group or the project.
+		permissions := struct {
+			Values []struct {
+				Permission string `json:"permission"`
+			} `json:"values"`
+		}{}
+		query := url.Values{"q": []string{fmt.Sprintf("user.account_id=%q", event.AccountID)}}
+		if _, err := v.getAPI(ctx, fmt.Sprintf("%s/workspaces/%s/permissions/repositories/%s?%s", apiURL,
+			url.PathEscape(event.Organization), url.PathEscape(event.Repository), query.Encode()), &permissions); err != nil {
+			return 0, err
+		}
+		if len(permissions.Values) == 0 {
+			return 0, nil
+		}
+		return permissionLevels[permissions.Values[0].Permission], nil
+	}
+
+	repo, err := v.Client().Repositories.Repository.Get(&bitbucket.RepositoryOptions{
+		Owner:    event.Organization,
+		RepoSlug: event.Repository,
+	})
+	if err != nil {
+		return 0, err
+	}
+	permission := struct {
+		Permission string `json:"permission"`
+	}{}
+	status, err := v.getAPI(ctx, fmt.Sprintf("%s/workspaces/%s/projects/%s/permissions-config/users/%s", apiURL,
+		url.PathEscape(event.Organization), url.PathEscape(repo.Project.Key), url.PathEscape(event.AccountID)), &permission)
+	if status == http.StatusNotFound {
+		// no explicit permission on the project for this user
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return permissionLevels[permission.Permission], nil
+}
+
+// CheckPolicyAllowing check that policy is allowing the event to be processed
+// a policy entry is either the slug of a workspace group the sender has to be
+// a member of, or `project:<permission>` or `repo:<permission>` for the
+// minimum permission (read, write, create-repo or admin) the sender needs to
+// have on the project or on the repository.
+// if the group is not found we explicitly disallow the policy, user have to correct the setting.
+// if the 1.0 groups API is not available anymore an error event is emitted on
+// the Repository since no group policy can be checked.
+func (v *Provider) CheckPolicyAllowing(ctx context.Context, event *info.Event, allowedGroups []string) (bool, string) {
+	for _, allowedGroup := range allowedGroups {
+		scope, minLevel, isPermission, err := parsePolicyPermission(allowedGroup)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		// TODO: caching
+		if isPermission {
+			level, err := v.getPermission(ctx, event, scope)
+			if err != nil {
+				return false, fmt.Sprintf("error while getting %s permission for user: %s, error: %s", scope, event.Sender, err.Error())
+			}
+			if level >= minLevel {
+				return true, fmt.Sprintf("allowing user: %s with the %s permission", event.Sender, allowedGroup)
+			}
+			continue
+		}
+
+		member, status, err := v.isGroupMember(ctx, event, allowedGroup)
+		if status == http.StatusNotFound || status == http.StatusGone {
+			if v.isGroupsAPIUnavailable(ctx, event, status) {
+				msg := fmt.Sprintf("cannot check the membership of user: %s in group: %s, the Bitbucket Cloud 1.0 groups API returned status code %d and may have been removed, use a project: or repo: permission in the policy instead",
+					event.Sender, allowedGroup, status)
+				if v.eventEmitter != nil {
+					v.eventEmitter.EmitMessage(v.repo, zap.ErrorLevel, "RepositoryPolicyGroupsAPIUnavailable", msg)
+				}
+				return false, msg
+			}
+			return false, fmt.Sprintf("group: %s is not found", allowedGroup)
+		}
+		if err != nil {
+			// probably a 500 or another api error, no need to try again and again with other groups
+			return false, fmt.Sprintf("error while getting group membership for user: %s in group: %s, error: %s", event.Sender, allowedGroup, err.Error())
+		}
+		if member {
+			return true, fmt.Sprintf("allowing user: %s as a member of the group: %s", event.Sender, allowedGroup)
+		}
+	}
+
+	return false, fmt.Sprintf("user: %s is not a member of any of the allowed groups: %v", event.Sender, allowedGroups)
+}
+
 func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	aclPolicy := policy.Policy{
+		Repository:   v.repo,
+		EventEmitter: v.eventEmitter,
+		Event:        event,
+		VCX:          v,
+		Logger:       v.Logger,
+	}
+
+	// Try to detect a policy rule allowing this
+	policyAllowed, policyReason := aclPolicy.IsAllowed(ctx, detectTriggerTypeFromEvent(event))
+	switch policyAllowed {
+	case policy.ResultAllowed:
+		return true, nil
+	case policy.ResultDisallowed:
+		return false, nil
+	case policy.ResultNotSet: // this is to make golangci-lint happy
+	}
+
 	// Check first if the user is in the owner file or part of the workspace
 	allowed, err := v.checkMember(ctx, event)
 	if err != nil {
@@ -23,7 +228,16 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 	}
 
 	// Check then from comment if there is a approved user that has done a /ok-to-test
-	return v.checkOkToTestCommentFromApprovedMember(ctx, event)
+	allowed, err = v.checkOkToTestCommentFromApprovedMember(ctx, event)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	// error with the policy reason if it was set
+	if policyReason != "" {
+		return false, fmt.Errorf("%s", policyReason)
+	}
+	return false, nil
 }
 
 func (v *Provider) isWorkspaceMember(event *info.Event) (bool, error) {
@@ -1,11 +1,18 @@
 package bitbucketcloud
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -191,3 +198,173 @@ func TestIsAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPolicyAllowing(t *testing.T) {
+	groups := map[string][]string{
+		"developers": {"sender", "other"},
+		"admins":     {"admin"},
+		"empty":      {},
+	}
+	projectPerms := map[string]string{"sender": "read", "writer": "write"}
+	repoPerms := map[string]string{"sender": "write", "admin": "admin"}
+	tests := []struct {
+		name          string
+		accountID     string
+		allowedGroups []string
+		// groupsAPIStatus is the status code the 1.0 groups API answers with
+		// when it is not available anymore
+		groupsAPIStatus int
+		wantAllowed     bool
+		wantReason      string
+		wantEvent       string
+	}{
+		{
+			name:          "allowed as member of the group",
+			accountID:     "sender",
+			allowedGroups: []string{"empty", "developers"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: nickname as a member of the group: developers",
+		},
+		{
+			name:          "allowed with the repository permission",
+			accountID:     "sender",
+			allowedGroups: []string{"project:write", "repo:Write"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: nickname with the repo:Write permission",
+		},
+		{
+			name:          "allowed with a higher project permission",
+			accountID:     "writer",
+			allowedGroups: []string{"project:read"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: nickname with the project:read permission",
+		},
+		{
+			name:          "disallowed not a member",
+			accountID:     "sender",
+			allowedGroups: []string{"admins", "repo:admin"},
+			wantReason:    "user: nickname is not a member of any of the allowed groups: [admins repo:admin]",
+		},
+		{
+			name:          "disallowed without any permission",
+			accountID:     "nobody",
+			allowedGroups: []string{"project:read", "repo:read"},
+			wantReason:    "user: nickname is not a member of any of the allowed groups: [project:read repo:read]",
+		},
+		{
+			name:          "disallowed group not found",
+			accountID:     "sender",
+			allowedGroups: []string{"notfound"},
+			wantReason:    "group: notfound is not found",
+		},
+		{
+			name:            "disallowed groups api not found",
+			accountID:       "sender",
+			allowedGroups:   []string{"developers", "repo:write"},
+			groupsAPIStatus: http.StatusNotFound,
+			wantReason:      "cannot check the membership of user: nickname in group: developers, the Bitbucket Cloud 1.0 groups API returned status code 404 and may have been removed, use a project: or repo: permission in the policy instead",
+			wantEvent:       "RepositoryPolicyGroupsAPIUnavailable",
+		},
+		{
+			name:            "disallowed groups api gone",
+			accountID:       "sender",
+			allowedGroups:   []string{"developers"},
+			groupsAPIStatus: http.StatusGone,
+			wantReason:      "cannot check the membership of user: nickname in group: developers, the Bitbucket Cloud 1.0 groups API returned status code 410 and may have been removed, use a project: or repo: permission in the policy instead",
+			wantEvent:       "RepositoryPolicyGroupsAPIUnavailable",
+		},
+		{
+			name:          "disallowed invalid permission",
+			accountID:     "sender",
+			allowedGroups: []string{"project:superadmin"},
+			wantReason:    `invalid permission "superadmin" for policy entry: project:superadmin`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+			event := bbcloudtest.MakeEvent(&info.Event{Sender: "nickname", AccountID: tt.accountID})
+			if tt.groupsAPIStatus != 0 {
+				bbcloudtest.MuxGroupsAPIUnavailable(t, mux, event, tt.groupsAPIStatus)
+			} else {
+				bbcloudtest.MuxGroupMembers(t, mux, event, groups)
+			}
+			bbcloudtest.MuxPermissions(t, mux, event, "PROJ", projectPerms, repoPerms)
+
+			kclient := fake.NewSimpleClientset()
+			v := &Provider{
+				bbClient:     bbclient,
+				repo:         &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}},
+				eventEmitter: events.NewEventEmitter(kclient, zap.NewNop().Sugar()),
+			}
+			gotAllowed, gotReason := v.CheckPolicyAllowing(ctx, event, tt.allowedGroups)
+			assert.Equal(t, tt.wantAllowed, gotAllowed)
+			assert.Equal(t, tt.wantReason, gotReason)
+
+			kevents, err := kclient.CoreV1().Events("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			if tt.wantEvent == "" {
+				assert.Equal(t, len(kevents.Items), 0)
+				return
+			}
+			assert.Equal(t, len(kevents.Items), 1)
+			assert.Equal(t, kevents.Items[0].Reason, tt.wantEvent)
+			assert.Equal(t, kevents.Items[0].Type, "Warning")
+		})
+	}
+}
+
+func TestIsAllowedWithPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountID   string
+		comment     string
+		policy      *v1alpha1.Policy
+		wantAllowed bool
+	}{
+		{
+			name:        "allowed on pull request by group policy",
+			accountID:   "sender",
+			policy:      &v1alpha1.Policy{PullRequest: []string{"developers"}},
+			wantAllowed: true,
+		},
+		{
+			name:      "disallowed on pull request by group policy",
+			accountID: "other",
+			policy:    &v1alpha1.Policy{PullRequest: []string{"developers"}},
+		},
+		{
+			name:      "ok-to-test comment disallowed by permission policy",
+			accountID: "other",
+			comment:   "/ok-to-test",
+			policy:    &v1alpha1.Policy{OkToTest: []string{"repo:write"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+			prEvent := bbcloudtest.MakePREvent(tt.accountID, "nickname", "", tt.comment)
+			event := bbcloudtest.MakeEvent(&info.Event{Sender: "nickname", AccountID: tt.accountID, Event: &prEvent})
+			bbcloudtest.MuxGroupMembers(t, mux, event, map[string][]string{"developers": {"sender"}})
+			bbcloudtest.MuxPermissions(t, mux, event, "PROJ", nil, map[string]string{"sender": "write"})
+
+			v := &Provider{
+				bbClient: bbclient,
+				repo: &v1alpha1.Repository{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec: v1alpha1.RepositorySpec{
+						Settings: &v1alpha1.Settings{Policy: tt.policy},
+					},
+				},
+				eventEmitter: events.NewEventEmitter(fake.NewSimpleClientset(), zap.NewNop().Sugar()),
+			}
+			got, err := v.IsAllowed(ctx, event)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, got)
+		})
+	}
+}
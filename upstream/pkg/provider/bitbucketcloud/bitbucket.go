@@ -3,6 +3,7 @@ package bitbucketcloud
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -22,6 +23,8 @@ import (
 
 var _ provider.Interface = (*Provider)(nil)
 
+const apiResponseLimit = 100
+
 type Provider struct {
 	bbClient      *bitbucket.Client
 	Logger        *zap.SugaredLogger
@@ -45,18 +48,53 @@ func (v *Provider) Client() *bitbucket.Client {
 	return v.bbClient
 }
 
-func (v *Provider) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
-	return nil
-}
+func (v *Provider) CreateComment(_ context.Context, event *info.Event, commit, updateMarker string) error {
+	if v.bbClient == nil {
+		return fmt.Errorf("no bitbucket cloud client has been initialized")
+	}
 
-// CheckPolicyAllowing TODO: Implement ME.
-func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
-	return false, ""
-}
+	if event.PullRequestNumber == 0 {
+		return fmt.Errorf("create comment only works on pull requests")
+	}
+	prID := strconv.Itoa(event.PullRequestNumber)
+
+	// List comments of the PR
+	if updateMarker != "" {
+		commentsIntf, err := v.Client().Repositories.PullRequests.GetComments(&bitbucket.PullRequestsOptions{
+			Owner:    event.Organization,
+			RepoSlug: event.Repository,
+			ID:       prID,
+		})
+		if err != nil {
+			return err
+		}
+		comments := &types.Comments{}
+		if err := mapstructure.Decode(commentsIntf, comments); err != nil {
+			return err
+		}
+
+		re := regexp.MustCompile(updateMarker)
+		for _, comment := range comments.Values {
+			if re.MatchString(comment.Content.Raw) {
+				_, err := v.Client().Repositories.PullRequests.UpdateComment(&bitbucket.PullRequestCommentOptions{
+					Owner:         event.Organization,
+					RepoSlug:      event.Repository,
+					PullRequestID: prID,
+					CommentId:     strconv.Itoa(comment.ID),
+					Content:       commit,
+				})
+				return err
+			}
+		}
+	}
 
-// GetTaskURI TODO: Implement ME.
-func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
-	return false, "", nil
+	_, err := v.Client().Repositories.PullRequests.AddComment(&bitbucket.PullRequestCommentOptions{
+		Owner:         event.Organization,
+		RepoSlug:      event.Repository,
+		PullRequestID: prID,
+		Content:       commit,
+	})
+	return err
 }
 
 func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
@@ -132,6 +170,15 @@ func (v *Provider) CreateStatus(_ context.Context, event *info.Event, statusopts
 			"cannot set status with the Bitbucket Cloud token because of: "+err.Error())
 	}
 
+	var commentStrategy string
+	if v.repo != nil && v.repo.Spec.Settings != nil && v.repo.Spec.Settings.Bitbucket != nil {
+		commentStrategy = v.repo.Spec.Settings.Bitbucket.CommentStrategy
+	}
+	if commentStrategy == "disable_all" {
+		v.Logger.Warn("bitbucket-cloud: comments related to PipelineRuns status have been disabled for Bitbucket Cloud pull requests")
+		return nil
+	}
+
 	eventType := triggertype.IsPullRequestType(event.EventType)
 	if statusopts.Conclusion != "STOPPED" && statusopts.Status == "completed" &&
 		statusopts.Text != "" &&
@@ -316,8 +363,78 @@ func (v *Provider) getBlob(runevent *info.Event, ref, path string) (string, erro
 	return blob.String(), nil
 }
 
-func (v *Provider) GetFiles(_ context.Context, _ *info.Event) (changedfiles.ChangedFiles, error) {
-	return changedfiles.ChangedFiles{}, nil
+// GetFiles gets the files changed by a pull request or a push from the Bitbucket Cloud diffstat API.
+func (v *Provider) GetFiles(_ context.Context, runevent *info.Event) (changedfiles.ChangedFiles, error) {
+	opts := &bitbucket.DiffStatOptions{
+		Owner:    runevent.Organization,
+		RepoSlug: runevent.Repository,
+		Renames:  true,
+		// go-bitbucket sends merge=false when Merge is unset, which the API refuses in combination with topic.
+		Merge:   true,
+		PageNum: 1,
+		Pagelen: apiResponseLimit,
+	}
+	switch runevent.TriggerTarget {
+	case triggertype.PullRequest:
+		// source..destination with topic gives us the three dot diff of the pull request, same as the UI shows.
+		opts.Spec = fmt.Sprintf("%s..%s", runevent.SHA, pullRequestDestination(runevent))
+		opts.Topic = true
+		opts.FromPullRequestID = runevent.PullRequestNumber
+	case triggertype.Push:
+		opts.Spec = runevent.SHA
+	default:
+		return changedfiles.ChangedFiles{}, nil
+	}
+
+	changedFiles := changedfiles.ChangedFiles{}
+	for {
+		diffStats, err := v.Client().Repositories.Diff.GetDiffStat(opts)
+		if err != nil {
+			return changedfiles.ChangedFiles{}, fmt.Errorf("failed to get diffstat for %s: %w", opts.Spec, err)
+		}
+		for _, diffStat := range diffStats.DiffStats {
+			filename := diffStatPath(diffStat.New)
+			if diffStat.Status == "removed" {
+				filename = diffStatPath(diffStat.Old)
+			}
+			if filename == "" {
+				continue
+			}
+			changedFiles.All = append(changedFiles.All, filename)
+			switch diffStat.Status {
+			case "added":
+				changedFiles.Added = append(changedFiles.Added, filename)
+			case "removed":
+				changedFiles.Deleted = append(changedFiles.Deleted, filename)
+			case "modified":
+				changedFiles.Modified = append(changedFiles.Modified, filename)
+			case "renamed":
+				changedFiles.Renamed = append(changedFiles.Renamed, filename)
+			}
+		}
+		if diffStats.Next == "" {
+			break
+		}
+		opts.PageNum++
+	}
+	return changedFiles, nil
+}
+
+// pullRequestDestination returns the destination commit of the pull request
+// from the original payload if we have it or fallback to the base branch.
+func pullRequestDestination(runevent *info.Event) string {
+	if prEvent, ok := runevent.Event.(*types.PullRequestEvent); ok && prEvent.PullRequest.Destination.Commit.Hash != "" {
+		return prEvent.PullRequest.Destination.Commit.Hash
+	}
+	return runevent.BaseBranch
+}
+
+func diffStatPath(file map[string]any) string {
+	if file == nil {
+		return ""
+	}
+	path, _ := file["path"].(string)
+	return path
 }
 
 func (v *Provider) CreateToken(_ context.Context, _ []string, _ *info.Event) (string, error) {
@@ -2,10 +2,12 @@ package bitbucketcloud
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/ktrysmt/go-bitbucket"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -252,6 +254,7 @@ func TestCreateStatus(t *testing.T) {
 		status                provider.StatusOpts
 		expectedDescSubstr    string
 		expectedCommentSubstr string
+		commentStrategy       string
 	}{
 		{
 			name: "skipped",
@@ -277,6 +280,16 @@ func TestCreateStatus(t *testing.T) {
 			expectedDescSubstr:    "validated",
 			expectedCommentSubstr: "Happy as a bunny",
 		},
+		{
+			name: "completed with comment disabled",
+			status: provider.StatusOpts{
+				Conclusion: "success",
+				Status:     "completed",
+				Text:       "Happy as a bunny",
+			},
+			expectedDescSubstr: "validated",
+			commentStrategy:    "disable_all",
+		},
 		{
 			name: "failed",
 			status: provider.StatusOpts{
@@ -322,21 +335,220 @@ func TestCreateStatus(t *testing.T) {
 			v := &Provider{
 				bbClient: bbclient,
 				run:      params.New(),
+				Logger:   zap.NewNop().Sugar(),
 				pacInfo: &info.PacOpts{
 					Settings: settings.Settings{
 						ApplicationName: settings.PACApplicationNameDefaultValue,
 					},
 				},
+				repo: &v1alpha1.Repository{
+					Spec: v1alpha1.RepositorySpec{
+						Settings: &v1alpha1.Settings{
+							Bitbucket: &v1alpha1.BitbucketSettings{
+								CommentStrategy: tt.commentStrategy,
+							},
+						},
+					},
+				},
 			}
 			event := bbcloudtest.MakeEvent(nil)
 			event.EventType = "pull_request"
 			event.Provider.Token = "token"
 
 			bbcloudtest.MuxCreateCommitstatus(t, mux, event, tt.expectedDescSubstr, tt.status)
-			bbcloudtest.MuxCreateComment(t, mux, event, tt.expectedCommentSubstr)
+			if tt.commentStrategy == "disable_all" {
+				mux.HandleFunc(fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments", event.Organization, event.Repository, event.PullRequestNumber),
+					func(_ http.ResponseWriter, _ *http.Request) {
+						t.Error("comment should not have been created when comments are disabled")
+					})
+			} else {
+				bbcloudtest.MuxCreateComment(t, mux, event, tt.expectedCommentSubstr)
+			}
 
 			err := v.CreateStatus(ctx, event, tt.status)
 			assert.NilError(t, err)
 		})
 	}
 }
+
+func TestGetFiles(t *testing.T) {
+	diffStat := func(status, oldPath, newPath string) *bitbucket.DiffStat {
+		ds := &bitbucket.DiffStat{Status: status}
+		if oldPath != "" {
+			ds.Old = map[string]any{"path": oldPath}
+		}
+		if newPath != "" {
+			ds.New = map[string]any{"path": newPath}
+		}
+		return ds
+	}
+	tests := []struct {
+		name         string
+		event        *info.Event
+		spec         string
+		pages        [][]*bitbucket.DiffStat
+		wantAll      []string
+		wantAdded    []string
+		wantDeleted  []string
+		wantModified []string
+		wantRenamed  []string
+		wantErr      string
+	}{
+		{
+			name: "pull request with pagination",
+			event: bbcloudtest.MakeEvent(&info.Event{
+				TriggerTarget: triggertype.PullRequest,
+				Event: &types.PullRequestEvent{PullRequest: types.PullRequest{
+					ID:          666,
+					Destination: types.Destination{Commit: types.Commit{Hash: "abcd"}},
+				}},
+			}),
+			spec: "1234..abcd",
+			pages: [][]*bitbucket.DiffStat{
+				{
+					diffStat("added", "", "added.txt"),
+					diffStat("removed", "deleted.txt", ""),
+				},
+				{
+					diffStat("modified", "modified.txt", "modified.txt"),
+					diffStat("renamed", "old.txt", "renamed.txt"),
+				},
+			},
+			wantAll:      []string{"added.txt", "deleted.txt", "modified.txt", "renamed.txt"},
+			wantAdded:    []string{"added.txt"},
+			wantDeleted:  []string{"deleted.txt"},
+			wantModified: []string{"modified.txt"},
+			wantRenamed:  []string{"renamed.txt"},
+		},
+		{
+			name:  "pull request fallback to base branch",
+			event: bbcloudtest.MakeEvent(&info.Event{TriggerTarget: triggertype.PullRequest}),
+			spec:  "1234..main",
+			pages: [][]*bitbucket.DiffStat{
+				{diffStat("modified", "modified.txt", "modified.txt")},
+			},
+			wantAll:      []string{"modified.txt"},
+			wantModified: []string{"modified.txt"},
+		},
+		{
+			name:  "push",
+			event: bbcloudtest.MakeEvent(&info.Event{TriggerTarget: triggertype.Push}),
+			spec:  "1234",
+			pages: [][]*bitbucket.DiffStat{
+				{
+					diffStat("added", "", "added.txt"),
+					diffStat("modified", "modified.txt", "modified.txt"),
+				},
+			},
+			wantAll:      []string{"added.txt", "modified.txt"},
+			wantAdded:    []string{"added.txt"},
+			wantModified: []string{"modified.txt"},
+		},
+		{
+			name:    "error from diffstat",
+			event:   bbcloudtest.MakeEvent(&info.Event{TriggerTarget: triggertype.Push}),
+			spec:    "notthesha",
+			wantErr: "failed to get diffstat for 1234",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+			v := &Provider{bbClient: bbclient}
+			bbcloudtest.MuxDiffStat(t, mux, tt.event, tt.spec, tt.pages)
+
+			changedFiles, err := v.GetFiles(ctx, tt.event)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.wantAll, changedFiles.All)
+			assert.DeepEqual(t, tt.wantAdded, changedFiles.Added)
+			assert.DeepEqual(t, tt.wantDeleted, changedFiles.Deleted)
+			assert.DeepEqual(t, tt.wantModified, changedFiles.Modified)
+			assert.DeepEqual(t, tt.wantRenamed, changedFiles.Renamed)
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	tests := []struct {
+		name          string
+		event         *info.Event
+		comments      []types.Comment
+		updateMarker  string
+		commentBody   string
+		nilClient     bool
+		wantUpdate    bool
+		wantErrSubstr string
+	}{
+		{
+			name:          "nil client",
+			event:         bbcloudtest.MakeEvent(nil),
+			nilClient:     true,
+			wantErrSubstr: "no bitbucket cloud client has been initialized",
+		},
+		{
+			name:          "not a pull request",
+			event:         &info.Event{},
+			wantErrSubstr: "create comment only works on pull requests",
+		},
+		{
+			name:        "create new comment",
+			event:       bbcloudtest.MakeEvent(nil),
+			commentBody: "New Comment",
+		},
+		{
+			name:         "create new comment when marker does not match",
+			event:        bbcloudtest.MakeEvent(nil),
+			comments:     []types.Comment{{ID: 1, Content: types.Content{Raw: "Hello there"}}},
+			updateMarker: "MARKER",
+			commentBody:  "New Comment MARKER",
+		},
+		{
+			name:  "update existing comment",
+			event: bbcloudtest.MakeEvent(nil),
+			comments: []types.Comment{
+				{ID: 1, Content: types.Content{Raw: "Hello there"}},
+				{ID: 42, Content: types.Content{Raw: "Old Comment MARKER"}},
+			},
+			updateMarker: "MARKER",
+			commentBody:  "Updated Comment MARKER",
+			wantUpdate:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+			if tt.nilClient {
+				bbclient = nil
+			}
+			v := &Provider{bbClient: bbclient}
+
+			if tt.event.Event != nil {
+				bbcloudtest.MuxComments(t, mux, tt.event, tt.comments)
+				bbcloudtest.MuxUpdateComment(t, mux, tt.event, 42, tt.commentBody)
+				if tt.wantUpdate {
+					mux.HandleFunc(fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments", tt.event.Organization, tt.event.Repository, tt.event.PullRequestNumber),
+						func(_ http.ResponseWriter, _ *http.Request) {
+							t.Error("comment should have been updated instead of created")
+						})
+				} else {
+					bbcloudtest.MuxCreateComment(t, mux, tt.event, tt.commentBody)
+				}
+			}
+
+			err := v.CreateComment(ctx, tt.event, tt.commentBody, tt.updateMarker)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/types"
 	"go.uber.org/zap"
@@ -64,6 +66,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Content.Raw) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHoldComment(e.Comment.Content.Raw) || provider.IsHelpComment(e.Comment.Content.Raw) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a valid gitops comment: \"%s\"", event), nil)
 
@@ -79,3 +84,36 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		return setLoggerAndProceed(false, "", fmt.Errorf("bitbucket-cloud: event \"%s\" is not supported", event))
 	}
 }
+
+// detectTriggerTypeFromEvent returns the trigger type of a parsed Bitbucket
+// Cloud event, this is used to know which policy rule applies to it.
+func detectTriggerTypeFromEvent(event *info.Event) triggertype.Trigger {
+	switch e := event.Event.(type) {
+	case *types.PullRequestEvent:
+		if event.TriggerTarget == triggertype.PullRequestClosed {
+			return triggertype.PullRequestClosed
+		}
+		// only the pullrequest:comment_created events carry a comment
+		if e.Comment.Content.Raw == "" {
+			return triggertype.PullRequest
+		}
+		switch {
+		case provider.IsTestRetestComment(e.Comment.Content.Raw):
+			return triggertype.Retest
+		case provider.IsOkToTestComment(e.Comment.Content.Raw):
+			return triggertype.OkToTest
+		case provider.IsCancelComment(e.Comment.Content.Raw):
+			return triggertype.Cancel
+		case provider.IsHoldComment(e.Comment.Content.Raw):
+			return triggertype.Hold
+		case provider.IsHelpComment(e.Comment.Content.Raw):
+			return triggertype.Help
+		}
+		return triggertype.Comment
+	case string:
+		if e == "push" {
+			return triggertype.Push
+		}
+	}
+	return ""
+}
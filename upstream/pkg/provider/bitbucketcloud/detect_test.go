@@ -130,6 +130,32 @@ func TestProvider_Detect(t *testing.T) {
 			isBC:       true,
 			processReq: true,
 		},
+		{
+			name: "hold comment",
+			event: types.PullRequestEvent{
+				Comment: types.Comment{
+					Content: types.Content{
+						Raw: "/hold",
+					},
+				},
+			},
+			eventType:  "pullrequest:comment_created",
+			isBC:       true,
+			processReq: true,
+		},
+		{
+			name: "help comment",
+			event: types.PullRequestEvent{
+				Comment: types.Comment{
+					Content: types.Content{
+						Raw: "/help",
+					},
+				},
+			},
+			eventType:  "pullrequest:comment_created",
+			isBC:       true,
+			processReq: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,61 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+type bitbucketCloudInfo struct {
+	Workspace  string
+	Repository string
+	Revision   string
+	FilePath   string
+}
+
+// extractBitbucketCloudInfo splits a browse URL
+// (https://bitbucket.org/workspace/repo/src/main/task.yaml) or a raw URL
+// (https://bitbucket.org/workspace/repo/raw/main/task.yaml) into its parts.
+// The revision is the first path element after src or raw, branches
+// containing a slash have to be referenced by their commit.
+func extractBitbucketCloudInfo(uri string) (*bitbucketCloudInfo, error) {
+	pURL, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("URL %s is not a valid provider URL: %w", uri, err)
+	}
+	split := strings.Split(strings.TrimPrefix(pURL.Path, "/"), "/")
+	if len(split) < 5 || (split[2] != "src" && split[2] != "raw") {
+		return nil, fmt.Errorf("cannot recognize task as a Bitbucket Cloud URL to fetch: %s", uri)
+	}
+	return &bitbucketCloudInfo{
+		Workspace:  split[0],
+		Repository: split[1],
+		Revision:   split[3],
+		FilePath:   strings.Join(split[4:], "/"),
+	}, nil
+}
+
+// GetTaskURI if we are getting a URL from the same URL where the provider is,
+// it means we can try to get the file with the provider token.
+func (v *Provider) GetTaskURI(_ context.Context, event *info.Event, uri string) (bool, string, error) {
+	if ret := provider.CompareHostOfURLS(uri, event.URL); !ret {
+		return false, "", nil
+	}
+	extracted, err := extractBitbucketCloudInfo(uri)
+	if err != nil {
+		return false, "", err
+	}
+
+	nEvent := info.NewEvent()
+	nEvent.Organization = extracted.Workspace
+	nEvent.Repository = extracted.Repository
+	ret, err := v.getBlob(nEvent, extracted.Revision, extracted.FilePath)
+	if err != nil {
+		return false, "", err
+	}
+	return true, ret, nil
+}
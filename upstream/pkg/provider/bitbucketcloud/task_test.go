@@ -0,0 +1,112 @@
+package bitbucketcloud
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	bbcloudtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketcloud/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestExtractBitbucketCloudInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *bitbucketCloudInfo
+		wantErr  bool
+	}{
+		{
+			name: "browse url",
+			url:  "https://bitbucket.org/workspace/repo/src/main/tasks/task.yaml",
+			expected: &bitbucketCloudInfo{
+				Workspace:  "workspace",
+				Repository: "repo",
+				Revision:   "main",
+				FilePath:   "tasks/task.yaml",
+			},
+		},
+		{
+			name: "raw url",
+			url:  "https://bitbucket.org/workspace/repo/raw/abcdef/task.yaml",
+			expected: &bitbucketCloudInfo{
+				Workspace:  "workspace",
+				Repository: "repo",
+				Revision:   "abcdef",
+				FilePath:   "task.yaml",
+			},
+		},
+		{
+			name:    "not a file url",
+			url:     "https://bitbucket.org/workspace/repo/pull-requests/1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := extractBitbucketCloudInfo(tt.url)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, info, tt.expected)
+		})
+	}
+}
+
+func TestGetTaskURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		uri         string
+		wantAllowed bool
+		wantErr     bool
+		wantContent string
+	}{
+		{
+			name:        "fetch from the same host",
+			uri:         "https://bitbucket.org/workspace/tasks/raw/main/task.yaml",
+			wantAllowed: true,
+			wantContent: "hello world",
+		},
+		{
+			name: "not the same host",
+			uri:  "https://other.example.com/workspace/tasks/raw/main/task.yaml",
+		},
+		{
+			name:    "unrecognized url",
+			uri:     "https://bitbucket.org/workspace/tasks",
+			wantErr: true,
+		},
+		{
+			name:    "file not found",
+			uri:     "https://bitbucket.org/workspace/tasks/src/main/notfound.yaml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			bbclient, mux, tearDown := bbcloudtest.SetupBBCloudClient(t)
+			defer tearDown()
+			mux.HandleFunc("/repositories/workspace/tasks/src/main/task.yaml", func(rw http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(rw, "hello world")
+			})
+
+			v := &Provider{bbClient: bbclient}
+			event := info.NewEvent()
+			event.URL = "https://bitbucket.org/owner/repo"
+			allowed, content, err := v.GetTaskURI(ctx, event, tt.uri)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Equal(t, tt.wantContent, content)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -19,13 +20,18 @@ import (
 	"gotest.tools/v3/env"
 )
 
-const bbBaseURLPath = "/2.0"
+const (
+	bbBaseURLPath   = "/2.0"
+	bbBaseURLPathV1 = "/1.0"
+)
 
 func SetupBBCloudClient(t *testing.T) (*bitbucket.Client, *http.ServeMux, func()) {
 	t.Helper()
 	mux := http.NewServeMux()
 	apiHandler := http.NewServeMux()
 	apiHandler.Handle(bbBaseURLPath+"/", http.StripPrefix(bbBaseURLPath, mux))
+	// the groups are only available on the 1.0 api
+	apiHandler.Handle(bbBaseURLPathV1+"/", http.StripPrefix(bbBaseURLPathV1, mux))
 	apiHandler.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
 		fmt.Fprintln(os.Stderr, "FAIL: Client.BaseURL path prefix is not preserved in the request URL:")
 		fmt.Fprintln(os.Stderr)
@@ -61,7 +67,8 @@ func MuxComments(t *testing.T, mux *http.ServeMux, event *info.Event, comments [
 	assert.Assert(t, ok)
 	prID := fmt.Sprintf("%d", pr.PullRequest.ID)
 	mux.HandleFunc("/repositories/"+event.Organization+"/"+event.Repository+"/pullrequests/"+prID+"/comments/",
-		func(rw http.ResponseWriter, _ *http.Request) {
+		func(rw http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, r.Method, http.MethodGet)
 			members := &types.Comments{
 				Values: comments,
 			}
@@ -71,6 +78,23 @@ func MuxComments(t *testing.T, mux *http.ServeMux, event *info.Event, comments [
 		})
 }
 
+func MuxUpdateComment(t *testing.T, mux *http.ServeMux, event *info.Event, commentID int, expectedCommentSubstr string) {
+	t.Helper()
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments/%d", event.Organization, event.Repository, event.PullRequestNumber, commentID)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPut)
+		cso := &types.Comment{}
+		bit, _ := io.ReadAll(r.Body)
+		err := json.Unmarshal(bit, cso)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(cso.Content.Raw, expectedCommentSubstr), "comment: %s doesn't have: %s",
+			cso.Content.Raw, expectedCommentSubstr)
+
+		fmt.Fprintf(rw, "{}")
+	})
+}
+
 func MuxOrgMember(t *testing.T, mux *http.ServeMux, event *info.Event, members []types.Member) {
 	t.Helper()
 	mux.HandleFunc("/workspaces/"+event.Organization+"/members",
@@ -134,6 +158,33 @@ func MuxCommits(t *testing.T, mux *http.ServeMux, event *info.Event, commits []t
 	})
 }
 
+// MuxDiffStat serves the diffstat of spec, one entry of pages per page with
+// the next link set until we reach the last one.
+func MuxDiffStat(t *testing.T, mux *http.ServeMux, event *info.Event, spec string, pages [][]*bitbucket.DiffStat) {
+	t.Helper()
+
+	path := fmt.Sprintf("/repositories/%s/%s/diffstat/%s", event.Organization, event.Repository, spec)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			var err error
+			page, err = strconv.Atoi(p)
+			assert.NilError(t, err)
+		}
+		assert.Assert(t, page >= 1 && page <= len(pages), "unexpected page %d", page)
+		res := &bitbucket.DiffStatRes{
+			Page:      page,
+			DiffStats: pages[page-1],
+		}
+		if page < len(pages) {
+			res.Next = fmt.Sprintf("%s?page=%d", path, page+1)
+		}
+		b, err := json.Marshal(res)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
 func MuxRepoInfo(t *testing.T, mux *http.ServeMux, event *info.Event, repo *bitbucket.Repository) {
 	t.Helper()
 
@@ -371,3 +422,74 @@ func MakeEvent(event *info.Event) *info.Event {
 	rev.PullRequestNumber = 666
 	return rev
 }
+
+// MuxGroupMembers mocks the 1.0 workspace groups api, groups maps a group slug
+// to the account ids of its members, other groups are not found.
+func MuxGroupMembers(t *testing.T, mux *http.ServeMux, event *info.Event, groups map[string][]string) {
+	t.Helper()
+
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/", event.Organization), func(rw http.ResponseWriter, r *http.Request) {
+		group := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/groups/%s/", event.Organization)), "/members")
+		accountIDs, ok := groups[group]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		members := []types.User{}
+		for _, accountID := range accountIDs {
+			members = append(members, types.User{AccountID: accountID})
+		}
+		b, err := json.Marshal(members)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+	mux.HandleFunc(fmt.Sprintf("/groups/%s", event.Organization), func(rw http.ResponseWriter, _ *http.Request) {
+		list := []map[string]string{}
+		for group := range groups {
+			list = append(list, map[string]string{"slug": group})
+		}
+		b, err := json.Marshal(list)
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxGroupsAPIUnavailable mocks the 1.0 groups API answering with status for
+// every request, as it would when it has been removed.
+func MuxGroupsAPIUnavailable(t *testing.T, mux *http.ServeMux, event *info.Event, status int) {
+	t.Helper()
+
+	handler := func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(status)
+	}
+	mux.HandleFunc(fmt.Sprintf("/groups/%s/", event.Organization), handler)
+	mux.HandleFunc(fmt.Sprintf("/groups/%s", event.Organization), handler)
+}
+
+// MuxPermissions mocks the project and repository permissions of the users,
+// the maps are keyed by the account id of the users and a missing user has no
+// permission.
+func MuxPermissions(t *testing.T, mux *http.ServeMux, event *info.Event, projectKey string, projectPerms, repoPerms map[string]string) {
+	t.Helper()
+
+	MuxRepoInfo(t, mux, event, &bitbucket.Repository{Project: bitbucket.Project{Key: projectKey}})
+	mux.HandleFunc(fmt.Sprintf("/workspaces/%s/projects/%s/permissions-config/users/", event.Organization, projectKey), func(rw http.ResponseWriter, r *http.Request) {
+		permission, ok := projectPerms[filepath.Base(r.URL.Path)]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(rw, `{"permission": %q}`, permission)
+	})
+	mux.HandleFunc(fmt.Sprintf("/workspaces/%s/permissions/repositories/%s", event.Organization, event.Repository), func(rw http.ResponseWriter, r *http.Request) {
+		values := []map[string]string{}
+		for accountID, permission := range repoPerms {
+			if r.URL.Query().Get("q") == fmt.Sprintf("user.account_id=%q", accountID) {
+				values = append(values, map[string]string{"permission": permission})
+			}
+		}
+		b, err := json.Marshal(map[string]any{"values": values})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
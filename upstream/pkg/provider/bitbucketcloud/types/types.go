@@ -57,6 +57,7 @@ type PullRequest struct {
 	Links       Links
 	Title       string `json:"title"`
 	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
 }
 
 type PullRequestEvent struct {
@@ -117,6 +118,7 @@ type Content struct {
 }
 
 type Comment struct {
+	ID      int     `json:"id"`
 	Content Content `json:"content"`
 	User    User
 }
@@ -2,16 +2,158 @@ package bitbucketdatacenter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
 
 	"github.com/jenkins-x/go-scm/scm"
 )
 
+// permissionLevels maps the permission names allowed in a `project:` or
+// `repo:` policy entry to the suffix of the Bitbucket Data Center permission.
+var permissionLevels = map[string]string{
+	"read":  "READ",
+	"write": "WRITE",
+	"admin": "ADMIN",
+}
+
+// pagedUsers is the paged list of users returned by the users and group
+// members endpoints.
+type pagedUsers struct {
+	Values        []types.User `json:"values"`
+	IsLastPage    bool         `json:"isLastPage"`
+	NextPageStart int          `json:"nextPageStart"`
+}
+
+// parsePolicyPermission splits a policy entry in the form of
+// `project:<permission>` or `repo:<permission>` into the scope and the
+// Bitbucket Data Center permission (ie: PROJECT_WRITE), isPermission is
+// false when the entry is a user group name.
+func parsePolicyPermission(entry string) (string, string, bool, error) {
+	scope, level, found := strings.Cut(strings.TrimSpace(entry), ":")
+	scope = strings.ToLower(scope)
+	if !found || (scope != "project" && scope != "repo") {
+		return "", "", false, nil
+	}
+	permission, ok := permissionLevels[strings.ToLower(strings.TrimSpace(level))]
+	if !ok {
+		return "", "", true, fmt.Errorf("invalid permission %q for policy entry: %s", level, entry)
+	}
+	return scope, strings.ToUpper(scope) + "_" + permission, true, nil
+}
+
+// hasUser pages through a users endpoint and returns true if the user is
+// part of the results, the endpoints filter on a substring of the name so we
+// need to check for an exact match.
+func (v *Provider) hasUser(ctx context.Context, path string, query url.Values, user string) (bool, *scm.Response, error) {
+	start := 0
+	for {
+		query.Set("start", fmt.Sprintf("%d", start))
+		query.Set("limit", fmt.Sprintf("%d", apiResponseLimit))
+		resp, err := v.Client().Do(ctx, &scm.Request{
+			Method: http.MethodGet,
+			Path:   fmt.Sprintf("%s?%s", path, query.Encode()),
+		})
+		if err != nil {
+			return false, resp, err
+		}
+		users := &pagedUsers{}
+		err = json.NewDecoder(resp.Body).Decode(users)
+		resp.Body.Close()
+		if resp.Status != http.StatusOK {
+			return false, resp, fmt.Errorf("%s returned status code %d", path, resp.Status)
+		}
+		if err != nil {
+			return false, resp, err
+		}
+		for _, u := range users.Values {
+			if u.Name == user || u.Slug == user {
+				return true, resp, nil
+			}
+		}
+		if users.IsLastPage || len(users.Values) == 0 {
+			return false, resp, nil
+		}
+		start = users.NextPageStart
+	}
+}
+
+// CheckPolicyAllowing check that policy is allowing the event to be processed
+// a policy entry is either the name of a Bitbucket Data Center user group the
+// sender has to be a member of, or `project:<permission>` or
+// `repo:<permission>` for the minimum permission (read, write or admin) the
+// sender needs to have on the project or the repository, permissions granted
+// through a group are taken into account.
+// if the group is not found we explicitly disallow the policy, user have to correct the setting.
+func (v *Provider) CheckPolicyAllowing(ctx context.Context, event *info.Event, allowedGroups []string) (bool, string) {
+	for _, allowedGroup := range allowedGroups {
+		scope, permission, isPermission, err := parsePolicyPermission(allowedGroup)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		// TODO: caching
+		var allowed bool
+		var resp *scm.Response
+		if isPermission {
+			query := url.Values{}
+			query.Set("filter", event.Sender)
+			query.Set("permission", permission)
+			query.Set("permission.projectKey", event.Organization)
+			if scope == "repo" {
+				query.Set("permission.repositorySlug", event.Repository)
+			}
+			allowed, _, err = v.hasUser(ctx, "rest/api/1.0/users", query, event.Sender)
+			if err == nil && allowed {
+				return true, fmt.Sprintf("allowing user: %s with the %s permission", event.Sender, permission)
+			}
+		} else {
+			query := url.Values{}
+			query.Set("context", allowedGroup)
+			query.Set("filter", event.Sender)
+			allowed, resp, err = v.hasUser(ctx, "rest/api/1.0/admin/groups/more-members", query, event.Sender)
+			if resp != nil && resp.Status == http.StatusNotFound {
+				return false, fmt.Sprintf("group: %s is not found", allowedGroup)
+			}
+		}
+		if err != nil {
+			// probably a 500 or another api error, no need to try again and again with other groups
+			return false, fmt.Sprintf("error while checking policy for user: %s with: %s, error: %s", event.Sender, allowedGroup, err.Error())
+		}
+		if allowed {
+			return true, fmt.Sprintf("allowing user: %s as a member of the group: %s", event.Sender, allowedGroup)
+		}
+	}
+
+	return false, fmt.Sprintf("user: %s is not a member of any of the allowed groups: %v", event.Sender, allowedGroups)
+}
+
 func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, error) {
+	aclPolicy := policy.Policy{
+		Repository:   v.repo,
+		EventEmitter: v.eventEmitter,
+		Event:        event,
+		VCX:          v,
+		Logger:       v.Logger,
+	}
+
+	// Try to detect a policy rule allowing this
+	policyAllowed, policyReason := aclPolicy.IsAllowed(ctx, detectTriggerTypeFromEvent(event.Event))
+	switch policyAllowed {
+	case policy.ResultAllowed:
+		return true, nil
+	case policy.ResultDisallowed:
+		return false, nil
+	case policy.ResultNotSet: // this is to make golangci-lint happy
+	}
+
 	allowed, err := v.checkMemberShip(ctx, event)
 	if err != nil {
 		return false, err
@@ -21,7 +163,16 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 	}
 
 	// Check then from comment if there is a approved user that has done a /ok-to-test
-	return v.checkOkToTestCommentFromApprovedMember(ctx, event)
+	allowed, err = v.checkOkToTestCommentFromApprovedMember(ctx, event)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	// error with the policy reason if it was set
+	if policyReason != "" {
+		return false, fmt.Errorf("%s", policyReason)
+	}
+	return false, nil
 }
 
 // IsAllowedOwnersFile get the owner files (OWNERS, OWNERS_ALIASES) from main branch
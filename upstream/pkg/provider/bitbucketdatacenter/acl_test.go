@@ -19,11 +19,16 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	bbv1test "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/test"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
 
+	"go.uber.org/zap"
 	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -234,3 +239,133 @@ func TestIsAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPolicyAllowing(t *testing.T) {
+	groups := map[string][]string{
+		"devs":   {"sender", "other"},
+		"admins": {"sender-admin"},
+		"empty":  {},
+	}
+	users := map[string][]string{
+		"PROJECT_READ":  {"sender"},
+		"PROJECT_WRITE": {"sender-writer"},
+		"REPO_WRITE":    {"sender"},
+	}
+	tests := []struct {
+		name          string
+		sender        string
+		allowedGroups []string
+		wantAllowed   bool
+		wantReason    string
+	}{
+		{
+			name:          "allowed as member of the group",
+			sender:        "sender",
+			allowedGroups: []string{"empty", "devs"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender as a member of the group: devs",
+		},
+		{
+			name:          "allowed with the repository permission",
+			sender:        "sender",
+			allowedGroups: []string{"project:write", "repo:Write"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender with the REPO_WRITE permission",
+		},
+		{
+			name:          "allowed with the project permission",
+			sender:        "sender",
+			allowedGroups: []string{"project:read"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender with the PROJECT_READ permission",
+		},
+		{
+			name:          "disallowed not a member",
+			sender:        "sender",
+			allowedGroups: []string{"admins", "project:admin"},
+			wantReason:    "user: sender is not a member of any of the allowed groups: [admins project:admin]",
+		},
+		{
+			name:          "disallowed group not found",
+			sender:        "sender",
+			allowedGroups: []string{"notfound"},
+			wantReason:    "group: notfound is not found",
+		},
+		{
+			name:          "disallowed invalid permission",
+			sender:        "sender",
+			allowedGroups: []string{"repo:superadmin"},
+			wantReason:    `invalid permission "superadmin" for policy entry: repo:superadmin`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown, _ := bbv1test.SetupBBDataCenterClient()
+			defer tearDown()
+			event := bbv1test.MakeEvent(&info.Event{Sender: tt.sender})
+			bbv1test.MuxGroupMembers(t, mux, groups)
+			bbv1test.MuxUsersWithPermission(t, mux, event, users)
+
+			v := &Provider{client: client, projectKey: event.Organization}
+			gotAllowed, gotReason := v.CheckPolicyAllowing(ctx, event, tt.allowedGroups)
+			assert.Equal(t, tt.wantAllowed, gotAllowed)
+			assert.Equal(t, tt.wantReason, gotReason)
+		})
+	}
+}
+
+func TestIsAllowedWithPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		sender      string
+		comment     string
+		policy      *v1alpha1.Policy
+		wantAllowed bool
+	}{
+		{
+			name:        "allowed on pull request by group policy",
+			sender:      "sender",
+			policy:      &v1alpha1.Policy{PullRequest: []string{"devs"}},
+			wantAllowed: true,
+		},
+		{
+			name:   "disallowed on pull request by group policy",
+			sender: "other",
+			policy: &v1alpha1.Policy{PullRequest: []string{"devs"}},
+		},
+		{
+			name:    "ok-to-test comment disallowed by permission policy",
+			sender:  "other",
+			comment: "/ok-to-test",
+			policy:  &v1alpha1.Policy{OkToTest: []string{"repo:write"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown, tURL := bbv1test.SetupBBDataCenterClient()
+			defer tearDown()
+			event := bbv1test.MakeEvent(&info.Event{Sender: tt.sender})
+			event.Event = bbv1test.MakePREvent(event, tt.comment)
+			bbv1test.MuxGroupMembers(t, mux, map[string][]string{"devs": {"sender"}})
+			bbv1test.MuxUsersWithPermission(t, mux, event, map[string][]string{"REPO_WRITE": {"sender"}})
+
+			v := &Provider{
+				baseURL:    tURL,
+				client:     client,
+				projectKey: event.Organization,
+				repo: &v1alpha1.Repository{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec: v1alpha1.RepositorySpec{
+						Settings: &v1alpha1.Settings{Policy: tt.policy},
+					},
+				},
+				eventEmitter: events.NewEventEmitter(fake.NewSimpleClientset(), zap.NewNop().Sugar()),
+			}
+			got, err := v.IsAllowed(ctx, event)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, got)
+		})
+	}
+}
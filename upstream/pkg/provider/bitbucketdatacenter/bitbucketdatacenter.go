@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/google/go-github/v74/github"
@@ -42,6 +43,7 @@ type Provider struct {
 	projectKey                string
 	repo                      *v1alpha1.Repository
 	triggerEvent              string
+	eventEmitter              *events.EventEmitter
 }
 
 func (v Provider) Client() *scm.Client {
@@ -54,21 +56,51 @@ func (v Provider) Client() *scm.Client {
 	return v.client
 }
 
-func (v *Provider) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
-	return nil
-}
+func (v *Provider) CreateComment(ctx context.Context, event *info.Event, commit, updateMarker string) error {
+	if v.client == nil {
+		return fmt.Errorf("no bitbucket data center client has been initialized")
+	}
 
-func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
-	v.pacInfo = pacInfo
-}
+	if event.PullRequestNumber == 0 {
+		return fmt.Errorf("create comment only works on pull requests")
+	}
+	OrgAndRepo := fmt.Sprintf("%s/%s", event.Organization, event.Repository)
 
-func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
-	return false, ""
+	// List comments of the PR, they come from the activities endpoint and
+	// only the commented activities are kept, so a page can have less
+	// comments than what we asked for. We go through the pages until the
+	// last one, go-scm only sets the next page when it's not the last.
+	if updateMarker != "" {
+		re := regexp.MustCompile(updateMarker)
+		opts := &scm.ListOptions{Page: 1, Size: apiResponseLimit}
+		for {
+			comments, res, err := v.Client().PullRequests.ListComments(ctx, OrgAndRepo, event.PullRequestNumber, opts)
+			if err != nil {
+				return err
+			}
+			for _, comment := range comments {
+				if re.MatchString(comment.Body) {
+					_, _, err := v.Client().PullRequests.EditComment(ctx, OrgAndRepo, event.PullRequestNumber, comment.ID, &scm.CommentInput{
+						Body: commit,
+					})
+					return err
+				}
+			}
+			if res == nil || res.Page.Next == 0 {
+				break
+			}
+			opts.Page = res.Page.Next
+		}
+	}
+
+	_, _, err := v.Client().PullRequests.CreateComment(ctx, OrgAndRepo, event.PullRequestNumber, &scm.CommentInput{
+		Body: commit,
+	})
+	return err
 }
 
-// GetTaskURI TODO: Implement ME.
-func (v *Provider) GetTaskURI(_ context.Context, _ *info.Event, _ string) (bool, string, error) {
-	return false, "", nil
+func (v *Provider) SetPacInfo(pacInfo *info.PacOpts) {
+	v.pacInfo = pacInfo
 }
 
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
@@ -142,6 +174,15 @@ func (v *Provider) CreateStatus(ctx context.Context, event *info.Event, statusOp
 		return err
 	}
 
+	var commentStrategy string
+	if v.repo != nil && v.repo.Spec.Settings != nil && v.repo.Spec.Settings.Bitbucket != nil {
+		commentStrategy = v.repo.Spec.Settings.Bitbucket.CommentStrategy
+	}
+	if commentStrategy == "disable_all" {
+		v.Logger.Warn("bitbucket-datacenter: comments related to PipelineRuns status have been disabled for Bitbucket Data Center pull requests")
+		return nil
+	}
+
 	onPr := ""
 	if statusOpts.OriginalPipelineRunName != "" {
 		onPr = "/" + statusOpts.OriginalPipelineRunName
@@ -278,7 +319,7 @@ func removeLastSegment(urlStr string) string {
 	return u.String()
 }
 
-func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.Event, repo *v1alpha1.Repository, _ *events.EventEmitter) error {
+func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.Event, repo *v1alpha1.Repository, eventEmitter *events.EventEmitter) error {
 	if event.Provider.User == "" {
 		return fmt.Errorf("no spec.git_provider.user has been set in the repo crd")
 	}
@@ -319,6 +360,7 @@ func (v *Provider) SetClient(ctx context.Context, run *params.Run, event *info.E
 	}
 	v.run = run
 	v.repo = repo
+	v.eventEmitter = eventEmitter
 	v.triggerEvent = event.EventType
 	_, resp, err := v.Client().Users.FindLogin(ctx, event.Provider.User)
 	if resp != nil && resp.Status == http.StatusUnauthorized {
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
@@ -21,6 +22,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	bbtest "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/test"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
 
 	"github.com/jenkins-x/go-scm/scm"
 	"go.uber.org/zap"
@@ -114,6 +116,7 @@ func TestCreateStatus(t *testing.T) {
 		expectedCommentSubstr string
 		pacOpts               info.PacOpts
 		nilClient             bool
+		commentStrategy       string
 		wantErrSubstr         string
 	}{
 		{
@@ -150,6 +153,16 @@ func TestCreateStatus(t *testing.T) {
 			expectedCommentSubstr: "Happy as a bunny",
 			pacOpts:               pacopts,
 		},
+		{
+			name: "good/completed with comment disabled",
+			status: provider.StatusOpts{
+				Conclusion: "success",
+				Status:     "completed",
+				Text:       "validated",
+			},
+			commentStrategy: "disable_all",
+			pacOpts:         pacopts,
+		},
 		{
 			name: "good/failed",
 			status: provider.StatusOpts{
@@ -225,9 +238,28 @@ func TestCreateStatus(t *testing.T) {
 				projectKey:        event.Organization,
 				run:               &params.Run{},
 				pacInfo:           &tt.pacOpts,
+				Logger:            zap.NewNop().Sugar(),
+				repo: &v1alpha1.Repository{
+					Spec: v1alpha1.RepositorySpec{
+						Settings: &v1alpha1.Settings{
+							Bitbucket: &v1alpha1.BitbucketSettings{
+								CommentStrategy: tt.commentStrategy,
+							},
+						},
+					},
+				},
 			}
 			bbtest.MuxCreateAndTestCommitStatus(t, mux, event, tt.status.Text, tt.status)
-			bbtest.MuxCreateComment(t, mux, event, tt.expectedCommentSubstr, pullRequestNumber)
+			if tt.commentStrategy == "disable_all" {
+				event.TriggerTarget = triggertype.PullRequest
+				event.PullRequestNumber = pullRequestNumber
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments", event.Organization, event.Repository, pullRequestNumber),
+					func(_ http.ResponseWriter, _ *http.Request) {
+						t.Error("comment should not have been created when comments are disabled")
+					})
+			} else {
+				bbtest.MuxCreateComment(t, mux, event, tt.expectedCommentSubstr, pullRequestNumber)
+			}
 			err := v.CreateStatus(ctx, event, tt.status)
 			if tt.wantErrSubstr != "" {
 				assert.ErrorContains(t, err, tt.wantErrSubstr)
@@ -238,6 +270,101 @@ func TestCreateStatus(t *testing.T) {
 	}
 }
 
+func TestCreateComment(t *testing.T) {
+	pullRequestNumber := 10
+	// a full first page of activities with a single comment, the marker
+	// comment is on the second page
+	busyActivities := []*bbtest.Activity{{Action: "COMMENTED", Comment: types.ActivityComment{ID: 1, Text: "Hello there"}}}
+	for len(busyActivities) < apiResponseLimit {
+		busyActivities = append(busyActivities, &bbtest.Activity{Action: "APPROVED"})
+	}
+	busyActivities = append(busyActivities, &bbtest.Activity{Action: "COMMENTED", Comment: types.ActivityComment{ID: 42, Text: "Old Comment MARKER"}})
+	tests := []struct {
+		name          string
+		event         *info.Event
+		activities    []*bbtest.Activity
+		updateMarker  string
+		commentBody   string
+		nilClient     bool
+		wantUpdate    bool
+		wantErrSubstr string
+	}{
+		{
+			name:          "bad/nil client",
+			event:         bbtest.MakeEvent(&info.Event{PullRequestNumber: pullRequestNumber}),
+			nilClient:     true,
+			wantErrSubstr: "no bitbucket data center client has been initialized",
+		},
+		{
+			name:          "bad/not a pull request",
+			event:         bbtest.MakeEvent(nil),
+			wantErrSubstr: "create comment only works on pull requests",
+		},
+		{
+			name:        "good/create new comment",
+			event:       bbtest.MakeEvent(&info.Event{PullRequestNumber: pullRequestNumber}),
+			commentBody: "New Comment",
+		},
+		{
+			name:  "good/create new comment when marker does not match",
+			event: bbtest.MakeEvent(&info.Event{PullRequestNumber: pullRequestNumber}),
+			activities: []*bbtest.Activity{
+				{Action: "COMMENTED", Comment: types.ActivityComment{ID: 1, Text: "Hello there"}},
+			},
+			updateMarker: "MARKER",
+			commentBody:  "New Comment MARKER",
+		},
+		{
+			name:  "good/update existing comment",
+			event: bbtest.MakeEvent(&info.Event{PullRequestNumber: pullRequestNumber}),
+			activities: []*bbtest.Activity{
+				{Action: "COMMENTED", Comment: types.ActivityComment{ID: 1, Text: "Hello there"}},
+				{Action: "COMMENTED", Comment: types.ActivityComment{ID: 42, Text: "Old Comment MARKER"}},
+			},
+			updateMarker: "MARKER",
+			commentBody:  "Updated Comment MARKER",
+			wantUpdate:   true,
+		},
+		{
+			name:         "good/update existing comment on the next page of activities",
+			event:        bbtest.MakeEvent(&info.Event{PullRequestNumber: pullRequestNumber}),
+			activities:   busyActivities,
+			updateMarker: "MARKER",
+			commentBody:  "Updated Comment MARKER",
+			wantUpdate:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown, tURL := bbtest.SetupBBDataCenterClient()
+			defer tearDown()
+			if tt.nilClient {
+				client = nil
+			}
+			v := &Provider{baseURL: tURL, client: client}
+
+			bbtest.MuxPullRequestActivities(t, mux, tt.event, pullRequestNumber, tt.activities)
+			bbtest.MuxEditComment(t, mux, tt.event, tt.commentBody, pullRequestNumber, 42)
+			if tt.wantUpdate {
+				mux.HandleFunc(fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments", tt.event.Organization, tt.event.Repository, pullRequestNumber),
+					func(_ http.ResponseWriter, _ *http.Request) {
+						t.Error("comment should have been updated instead of created")
+					})
+			} else {
+				bbtest.MuxCreateComment(t, mux, tt.event, tt.commentBody, pullRequestNumber)
+			}
+
+			err := v.CreateComment(ctx, tt.event, tt.commentBody, tt.updateMarker)
+			if tt.wantErrSubstr != "" {
+				assert.ErrorContains(t, err, tt.wantErrSubstr)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
 func TestGetFileInsideRepo(t *testing.T) {
 	tests := []struct {
 		name          string
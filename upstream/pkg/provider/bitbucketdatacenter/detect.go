@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/types"
 	"go.uber.org/zap"
@@ -41,6 +42,14 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		if provider.Valid(event, []string{"pr:from_ref_updated", "pr:opened"}) {
 			return setLoggerAndProceed(true, "", nil)
 		}
+		// a modified pull request is only interesting when it has been
+		// marked as ready for review.
+		if event == "pr:modified" {
+			if e.PreviousDraft && !e.PullRequest.Draft {
+				return setLoggerAndProceed(true, "", nil)
+			}
+			return setLoggerAndProceed(false, "pull request has been modified without being marked as ready for review", nil)
+		}
 		if provider.Valid(event, []string{"pr:comment:added"}) {
 			if provider.IsTestRetestComment(e.Comment.Text) {
 				return setLoggerAndProceed(true, "", nil)
@@ -51,6 +60,9 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 			if provider.IsCancelComment(e.Comment.Text) {
 				return setLoggerAndProceed(true, "", nil)
 			}
+			if provider.IsHoldComment(e.Comment.Text) || provider.IsHelpComment(e.Comment.Text) {
+				return setLoggerAndProceed(true, "", nil)
+			}
 		}
 		return setLoggerAndProceed(false, fmt.Sprintf("not a recognized bitbucket event: \"%s\"", event), nil)
 
@@ -66,3 +78,31 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 		return setLoggerAndProceed(false, "", fmt.Errorf("bitbucket-datacenter: event \"%s\" is not supported", event))
 	}
 }
+
+// detectTriggerTypeFromEvent returns the trigger type of a parsed Bitbucket
+// Data Center event, this is used to know which policy rule applies to it.
+func detectTriggerTypeFromEvent(eventInt any) triggertype.Trigger {
+	switch e := eventInt.(type) {
+	case *types.PullRequestEvent:
+		// only the pr:comment:added events carry a comment
+		if e.Comment.Text == "" {
+			return triggertype.PullRequest
+		}
+		switch {
+		case provider.IsTestRetestComment(e.Comment.Text):
+			return triggertype.Retest
+		case provider.IsOkToTestComment(e.Comment.Text):
+			return triggertype.OkToTest
+		case provider.IsCancelComment(e.Comment.Text):
+			return triggertype.Cancel
+		case provider.IsHoldComment(e.Comment.Text):
+			return triggertype.Hold
+		case provider.IsHelpComment(e.Comment.Text):
+			return triggertype.Help
+		}
+		return triggertype.Comment
+	case *types.PushRequestEvent:
+		return triggertype.Push
+	}
+	return ""
+}
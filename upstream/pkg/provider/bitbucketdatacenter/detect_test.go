@@ -65,6 +65,25 @@ func TestProvider_Detect(t *testing.T) {
 			isBS:       true,
 			processReq: true,
 		},
+		{
+			name: "pull_request marked as ready for review",
+			event: types.PullRequestEvent{
+				PullRequest:   types.PullRequest{Draft: false},
+				PreviousDraft: true,
+			},
+			eventType:  "pr:modified",
+			isBS:       true,
+			processReq: true,
+		},
+		{
+			name: "pull_request modified",
+			event: types.PullRequestEvent{
+				PullRequest: types.PullRequest{Draft: true},
+			},
+			eventType:  "pr:modified",
+			isBS:       true,
+			processReq: false,
+		},
 		{
 			name: "retest comment",
 			event: types.PullRequestEvent{
@@ -110,6 +129,33 @@ func TestProvider_Detect(t *testing.T) {
 			isBS:       true,
 			processReq: true,
 		},
+		{
+			name: "hold comment",
+			event: types.PullRequestEvent{
+				Comment: types.ActivityComment{Text: "/hold"},
+			},
+			eventType:  "pr:comment:added",
+			isBS:       true,
+			processReq: true,
+		},
+		{
+			name: "unhold comment",
+			event: types.PullRequestEvent{
+				Comment: types.ActivityComment{Text: "/unhold"},
+			},
+			eventType:  "pr:comment:added",
+			isBS:       true,
+			processReq: true,
+		},
+		{
+			name: "help comment",
+			event: types.PullRequestEvent{
+				Comment: types.ActivityComment{Text: "/help"},
+			},
+			eventType:  "pr:comment:added",
+			isBS:       true,
+			processReq: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
@@ -107,11 +108,14 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 
 	switch e := eventPayload.(type) {
 	case *types.PullRequestEvent:
-		if provider.Valid(eventType, []string{"pr:from_ref_updated", "pr:opened"}) {
+		if provider.Valid(eventType, []string{"pr:from_ref_updated", "pr:opened", "pr:modified"}) {
 			processedEvent.TriggerTarget = triggertype.PullRequest
 			processedEvent.EventType = triggertype.PullRequest.String()
 		} else if provider.Valid(eventType, []string{"pr:comment:added", "pr:comment:edited"}) {
 			switch {
+			case provider.IsRetestFailedComment(e.Comment.Text):
+				processedEvent.TriggerTarget = triggertype.PullRequest
+				opscomments.SetEventTypeAndTargetPR(processedEvent, e.Comment.Text)
 			case provider.IsTestRetestComment(e.Comment.Text):
 				processedEvent.TriggerTarget = triggertype.PullRequest
 				if strings.Contains(e.Comment.Text, "/test") {
@@ -128,6 +132,9 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 				processedEvent.EventType = "cancel-comment"
 				processedEvent.CancelPipelineRuns = true
 				processedEvent.TargetCancelPipelineRun = provider.GetPipelineRunFromCancelComment(e.Comment.Text)
+			case provider.IsHoldComment(e.Comment.Text), provider.IsHelpComment(e.Comment.Text):
+				processedEvent.TriggerTarget = triggertype.PullRequest
+				opscomments.SetEventTypeAndTargetPR(processedEvent, e.Comment.Text)
 			}
 			processedEvent.TriggerComment = e.Comment.Text
 		}
@@ -141,6 +148,7 @@ func (v *Provider) ParsePayload(_ context.Context, _ *params.Run, request *http.
 		processedEvent.Repository = e.PullRequest.ToRef.Repository.Name
 		processedEvent.SHA = e.PullRequest.FromRef.LatestCommit
 		processedEvent.PullRequestNumber = e.PullRequest.ID
+		processedEvent.PullRequestDraft = e.PullRequest.Draft
 		processedEvent.URL = e.PullRequest.ToRef.Repository.Links.Self[0].Href
 		processedEvent.BaseBranch = e.PullRequest.ToRef.DisplayID
 		processedEvent.HeadBranch = e.PullRequest.FromRef.DisplayID
@@ -216,7 +224,7 @@ func parsePayloadType(event string) (any, error) {
 	var localEvent string
 	if strings.HasPrefix(event, "pr:") {
 		if !provider.Valid(event, []string{
-			"pr:from_ref_updated", "pr:opened", "pr:comment:added", "pr:comment:edited",
+			"pr:from_ref_updated", "pr:opened", "pr:modified", "pr:comment:added", "pr:comment:edited",
 		}) {
 			return nil, fmt.Errorf("event \"%s\" is not supported", event)
 		}
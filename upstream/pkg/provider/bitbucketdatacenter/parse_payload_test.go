@@ -574,6 +574,8 @@ func TestParsePayload(t *testing.T) {
 		rawStr                  string
 		targetPipelinerun       string
 		canceltargetPipelinerun string
+		expEventType            string
+		expCancel               bool
 	}{
 		{
 			name:          "bad/invalid event type",
@@ -660,6 +662,35 @@ func TestParsePayload(t *testing.T) {
 			payloadEvent: bbv1test.MakePREvent(ev1, "/cancel"),
 			expEvent:     ev1,
 		},
+		{
+			name:         "good/comment hold",
+			eventType:    "pr:comment:added",
+			payloadEvent: bbv1test.MakePREvent(ev1, "/hold"),
+			expEvent:     ev1,
+			expEventType: "hold-comment",
+		},
+		{
+			name:         "good/comment hold and cancel",
+			eventType:    "pr:comment:added",
+			payloadEvent: bbv1test.MakePREvent(ev1, "/hold cancel"),
+			expEvent:     ev1,
+			expEventType: "hold-comment",
+			expCancel:    true,
+		},
+		{
+			name:         "good/comment help",
+			eventType:    "pr:comment:added",
+			payloadEvent: bbv1test.MakePREvent(ev1, "/help"),
+			expEvent:     ev1,
+			expEventType: "help-comment",
+		},
+		{
+			name:         "good/comment unhold",
+			eventType:    "pr:comment:added",
+			payloadEvent: bbv1test.MakePREvent(ev1, "/unhold"),
+			expEvent:     ev1,
+			expEventType: "unhold-comment",
+		},
 		{
 			name:      "branch/deleted with zero hash",
 			eventType: "repo:refs_changed",
@@ -720,6 +751,10 @@ func TestParsePayload(t *testing.T) {
 			if tt.canceltargetPipelinerun != "" {
 				assert.Equal(t, got.TargetCancelPipelineRun, tt.canceltargetPipelinerun)
 			}
+			if tt.expEventType != "" {
+				assert.Equal(t, got.EventType, tt.expEventType)
+				assert.Equal(t, got.CancelPipelineRuns, tt.expCancel)
+			}
 		})
 	}
 }
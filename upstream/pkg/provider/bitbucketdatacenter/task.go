@@ -0,0 +1,70 @@
+package bitbucketdatacenter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// taskURLRegexp matches the browse and raw URLs of a file, the server may be
+// served under a context path and personal repositories are under /users/.
+var taskURLRegexp = regexp.MustCompile(`/(projects|users)/([^/]+)/repos/([^/]+)/(?:browse|raw)/(.+)$`)
+
+type bitbucketDataCenterInfo struct {
+	ProjectKey string
+	Repository string
+	Revision   string
+	FilePath   string
+}
+
+// extractBitbucketDataCenterInfo splits a browse URL
+// (https://bitbucket.example.com/projects/PROJ/repos/repo/browse/task.yaml?at=refs/heads/main)
+// or a raw URL (https://bitbucket.example.com/projects/PROJ/repos/repo/raw/task.yaml?at=main)
+// into its parts. When there is no `at` parameter the revision is empty and
+// the file is fetched from the default branch.
+func extractBitbucketDataCenterInfo(uri string) (*bitbucketDataCenterInfo, error) {
+	pURL, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("URL %s is not a valid provider URL: %w", uri, err)
+	}
+	matches := taskURLRegexp.FindStringSubmatch(pURL.Path)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("cannot recognize task as a Bitbucket Data Center URL to fetch: %s", uri)
+	}
+	projectKey := matches[2]
+	if matches[1] == "users" {
+		// personal repositories are in the ~USER project
+		projectKey = "~" + projectKey
+	}
+	return &bitbucketDataCenterInfo{
+		ProjectKey: projectKey,
+		Repository: matches[3],
+		Revision:   pURL.Query().Get("at"),
+		FilePath:   matches[4],
+	}, nil
+}
+
+// GetTaskURI if we are getting a URL from the same URL where the provider is,
+// it means we can try to get the file with the provider token.
+func (v *Provider) GetTaskURI(ctx context.Context, event *info.Event, uri string) (bool, string, error) {
+	if ret := provider.CompareHostOfURLS(uri, event.URL); !ret {
+		return false, "", nil
+	}
+	extracted, err := extractBitbucketDataCenterInfo(uri)
+	if err != nil {
+		return false, "", err
+	}
+
+	nEvent := info.NewEvent()
+	nEvent.Organization = extracted.ProjectKey
+	nEvent.Repository = extracted.Repository
+	ret, err := v.getRaw(ctx, nEvent, extracted.Revision, extracted.FilePath)
+	if err != nil {
+		return false, "", err
+	}
+	return true, ret, nil
+}
@@ -0,0 +1,122 @@
+package bitbucketdatacenter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	bbv1test "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/bitbucketdatacenter/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestExtractBitbucketDataCenterInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *bitbucketDataCenterInfo
+		wantErr  bool
+	}{
+		{
+			name: "browse url",
+			url:  "https://bitbucket.example.com/projects/PROJ/repos/repo/browse/tasks/task.yaml?at=refs%2Fheads%2Fmain",
+			expected: &bitbucketDataCenterInfo{
+				ProjectKey: "PROJ",
+				Repository: "repo",
+				Revision:   "refs/heads/main",
+				FilePath:   "tasks/task.yaml",
+			},
+		},
+		{
+			name: "raw url with a context path",
+			url:  "https://example.com/bitbucket/projects/PROJ/repos/repo/raw/task.yaml?at=abcdef",
+			expected: &bitbucketDataCenterInfo{
+				ProjectKey: "PROJ",
+				Repository: "repo",
+				Revision:   "abcdef",
+				FilePath:   "task.yaml",
+			},
+		},
+		{
+			name: "personal repository on the default branch",
+			url:  "https://bitbucket.example.com/users/jdoe/repos/repo/raw/task.yaml",
+			expected: &bitbucketDataCenterInfo{
+				ProjectKey: "~jdoe",
+				Repository: "repo",
+				FilePath:   "task.yaml",
+			},
+		},
+		{
+			name:    "not a file url",
+			url:     "https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := extractBitbucketDataCenterInfo(tt.url)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, info, tt.expected)
+		})
+	}
+}
+
+func TestGetTaskURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		uri         string
+		wantAllowed bool
+		wantErr     bool
+		wantContent string
+	}{
+		{
+			name:        "fetch from the same host",
+			uri:         "https://bitbucket.example.com/projects/PROJ/repos/tasks/browse/task.yaml?at=main",
+			wantAllowed: true,
+			wantContent: "hello world",
+		},
+		{
+			name: "not the same host",
+			uri:  "https://other.example.com/projects/PROJ/repos/tasks/browse/task.yaml?at=main",
+		},
+		{
+			name:    "unrecognized url",
+			uri:     "https://bitbucket.example.com/projects/PROJ/repos/tasks",
+			wantErr: true,
+		},
+		{
+			name:    "file not found",
+			uri:     "https://bitbucket.example.com/projects/PROJ/repos/tasks/raw/notfound.yaml?at=main",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown, _ := bbv1test.SetupBBDataCenterClient()
+			defer tearDown()
+			mux.HandleFunc("/projects/PROJ/repos/tasks/raw/task.yaml", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("at"), "main")
+				fmt.Fprint(rw, "hello world")
+			})
+
+			v := &Provider{client: client}
+			event := info.NewEvent()
+			event.URL = "https://bitbucket.example.com/projects/OWNER/repos/repo/browse"
+			allowed, content, err := v.GetTaskURI(ctx, event, tt.uri)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Equal(t, tt.wantContent, content)
+		})
+	}
+}
@@ -69,6 +69,25 @@ func MuxCreateComment(t *testing.T, mux *http.ServeMux, event *info.Event, expec
 	})
 }
 
+func MuxEditComment(t *testing.T, mux *http.ServeMux, event *info.Event, expectedCommentSubstr string, prID, commentID int) {
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments/%d", event.Organization, event.Repository, prID, commentID)
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(rw, `{"id": %d, "version": 1}`, commentID)
+			return
+		}
+		assert.Equal(t, r.Method, http.MethodPut)
+		cso := &Comment{}
+		bit, _ := io.ReadAll(r.Body)
+		err := json.Unmarshal(bit, cso)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(cso.Text, expectedCommentSubstr), "comment: %s doesn't have: %s",
+			cso.Text, expectedCommentSubstr)
+
+		fmt.Fprintf(rw, "{}")
+	})
+}
+
 // MakeEvent should we try to reflect? or json.Marshall? may be better ways, right?
 func MakeEvent(event *info.Event) *info.Event {
 	if event == nil {
@@ -265,11 +284,21 @@ func MuxRepoMemberShip(t *testing.T, mux *http.ServeMux, event *info.Event, user
 	})
 }
 
+// MuxPullRequestActivities serves the activities of a pull request, paginated
+// with the start and limit query parameters like the API does.
 func MuxPullRequestActivities(t *testing.T, mux *http.ServeMux, event *info.Event, prNumber int, activities []*Activity) {
 	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/activities", event.Organization, event.Repository, prNumber)
-	mux.HandleFunc(path, func(rw http.ResponseWriter, _ *http.Request) {
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit == 0 {
+			limit = len(activities)
+		}
+		start = min(start, len(activities))
+		end := min(start+limit, len(activities))
 		resp := map[string]any{
-			"values": activities,
+			"values":     activities[start:end],
+			"isLastPage": end == len(activities),
 		}
 		b, err := json.Marshal(resp)
 		assert.NilError(t, err)
@@ -369,3 +398,43 @@ func MakePushEvent(event *info.Event, changes []types.PushRequestEventChange, co
 		Commits: commits,
 	}
 }
+
+// MuxUsersWithPermission mocks the users endpoint filtered on a project or a
+// repository permission, users maps a permission (ie: PROJECT_WRITE) to the
+// name of the users having it.
+func MuxUsersWithPermission(t *testing.T, mux *http.ServeMux, event *info.Event, users map[string][]string) {
+	mux.HandleFunc("/users", func(rw http.ResponseWriter, r *http.Request) {
+		permission := r.URL.Query().Get("permission")
+		assert.Equal(t, r.URL.Query().Get("permission.projectKey"), event.Organization)
+		if strings.HasPrefix(permission, "REPO_") {
+			assert.Equal(t, r.URL.Query().Get("permission.repositorySlug"), event.Repository)
+		}
+		values := []types.User{}
+		for _, name := range users[permission] {
+			values = append(values, types.User{Name: name, Slug: name})
+		}
+		b, err := json.Marshal(map[string]any{"values": values, "isLastPage": true})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
+
+// MuxGroupMembers mocks the admin group members endpoint, groups maps a group
+// name to the name of its members, other groups are not found.
+func MuxGroupMembers(t *testing.T, mux *http.ServeMux, groups map[string][]string) {
+	mux.HandleFunc("/admin/groups/more-members", func(rw http.ResponseWriter, r *http.Request) {
+		members, ok := groups[r.URL.Query().Get("context")]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"errors": [{"message": "group not found"}]}`)
+			return
+		}
+		values := []types.User{}
+		for _, name := range members {
+			values = append(values, types.User{Name: name, Slug: name})
+		}
+		b, err := json.Marshal(map[string]any{"values": values, "isLastPage": true})
+		assert.NilError(t, err)
+		fmt.Fprint(rw, string(b))
+	})
+}
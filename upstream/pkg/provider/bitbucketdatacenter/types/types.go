@@ -33,6 +33,7 @@ type PullRequest struct {
 	FromRef      PullRequestRef     `json:"fromRef"`
 	ToRef        PullRequestRef     `json:"toRef"`
 	Locked       bool               `json:"locked"`
+	Draft        bool               `json:"draft"`
 	Author       *UserWithMetadata  `json:"author,omitempty"`
 	Reviewers    []UserWithMetadata `json:"reviewers"`
 	Participants []UserWithMetadata `json:"participants,omitempty"`
@@ -168,6 +169,9 @@ type PullRequestEvent struct {
 	// CommentParentID and PreviousComment should be used when event is `pr:comment:edited`.
 	CommentParentID string `json:"commentParentId"`
 	PreviousComment string `json:"previousComment"`
+
+	// PreviousDraft should be used when event is `pr:modified`.
+	PreviousDraft bool `json:"previousDraft"`
 }
 
 type PushRequestEventChange struct {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	giteaStructs "code.gitea.io/gitea/modules/structs"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
@@ -15,6 +16,10 @@ var (
 	pullRequestOpenSyncEvent = []string{"opened", "synchronize", "synchronized", "reopened"}
 	pullRequestLabelUpdated  = "label_updated"
 	pullRequestLabelClosed   = "closed"
+	pullRequestEdited        = "edited"
+	// wipTitlePrefixes are the default prefixes Gitea is using on a pull
+	// request title to mark it as a work in progress.
+	wipTitlePrefixes = []string{"WIP:", "[WIP]"}
 )
 
 // Detect processes event and detect if it is a gitea event, whether to process or reject it
@@ -60,6 +65,9 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 		if provider.Valid(string(event.Action), append(pullRequestOpenSyncEvent, pullRequestLabelUpdated, pullRequestLabelClosed)) {
 			return triggertype.PullRequest, ""
 		}
+		if string(event.Action) == pullRequestEdited && isMarkedAsReady(event) {
+			return triggertype.PullRequest, ""
+		}
 		return "", fmt.Sprintf("pull_request: unsupported action \"%s\"", event.Action)
 	case *giteaStructs.IssueCommentPayload:
 		if event.Action == "created" &&
@@ -74,6 +82,12 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 			if provider.IsCancelComment(event.Comment.Body) {
 				return triggertype.Cancel, ""
 			}
+			if provider.IsHoldComment(event.Comment.Body) {
+				return triggertype.Hold, ""
+			}
+			if provider.IsHelpComment(event.Comment.Body) {
+				return triggertype.Help, ""
+			}
 			// this ignores the comment if it is not a PAC gitops comment and not return an error
 			return triggertype.Comment, ""
 		}
@@ -81,3 +95,29 @@ func detectTriggerTypeFromPayload(ghEventType string, eventInt any) (triggertype
 	}
 	return "", fmt.Sprintf("gitea: event \"%v\" is not supported", ghEventType)
 }
+
+// isWIPTitle checks if the pull request title starts with one of the work in
+// progress prefixes.
+func isWIPTitle(title string) bool {
+	for _, prefix := range wipTitlePrefixes {
+		if strings.HasPrefix(strings.ToUpper(title), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDraft checks if the pull request is a draft, Gitea has no draft state
+// and is relying on the title prefixes for it.
+func isDraft(pr *giteaStructs.PullRequest) bool {
+	return pr.Draft || isWIPTitle(pr.Title)
+}
+
+// isMarkedAsReady checks if the work in progress prefix has just been removed
+// from the pull request title.
+func isMarkedAsReady(event *giteaStructs.PullRequestPayload) bool {
+	if event.Changes == nil || event.Changes.Title == nil || event.PullRequest == nil {
+		return false
+	}
+	return isWIPTitle(event.Changes.Title.From) && !isDraft(event.PullRequest)
+}
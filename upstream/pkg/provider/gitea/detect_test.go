@@ -99,6 +99,32 @@ func TestProviderDetect(t *testing.T) {
 			isGitea:      true,
 			processEvent: true,
 		},
+		{
+			name: "good/pull request marked as ready",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request"},
+					},
+				},
+				payload: `{"action": "edited", "changes": {"title": {"from": "WIP: my feature"}}, "pull_request": {"title": "my feature"}}`,
+			},
+			isGitea:      true,
+			processEvent: true,
+		},
+		{
+			name: "bad/pull request title edited",
+			args: args{
+				req: &http.Request{
+					Header: http.Header{
+						"X-Gitea-Event-Type": []string{"pull_request"},
+					},
+				},
+				payload: `{"action": "edited", "changes": {"title": {"from": "[WIP] my feature"}}, "pull_request": {"title": "[wip] my better feature"}}`,
+			},
+			wantReason: `pull_request: unsupported action "edited"`,
+			isGitea:    true,
+		},
 		{
 			name: "good/push",
 			args: args{
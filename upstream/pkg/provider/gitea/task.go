@@ -0,0 +1,71 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+type giteaInfo struct {
+	Organization string
+	Repository   string
+	Revision     string
+	FilePath     string
+}
+
+// extractGiteaInfo splits a browse URL
+// (https://gitea.example.com/org/repo/src/branch/main/task.yaml) or a raw URL
+// (https://gitea.example.com/org/repo/raw/branch/main/task.yaml) into its
+// parts, the revision can be a branch, a tag or a commit. The revision is a
+// single path element, branches containing a slash have to be referenced by
+// their commit.
+func extractGiteaInfo(uri string) (*giteaInfo, error) {
+	pURL, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("URL %s is not a valid provider URL: %w", uri, err)
+	}
+	split := strings.Split(strings.TrimPrefix(pURL.Path, "/"), "/")
+	if len(split) < 5 || (split[2] != "src" && split[2] != "raw" && split[2] != "media") {
+		return nil, fmt.Errorf("cannot recognize task as a Gitea URL to fetch: %s", uri)
+	}
+	ref, path := split[3], split[4:]
+	switch ref {
+	case "branch", "tag", "commit":
+		if len(split) < 6 {
+			return nil, fmt.Errorf("cannot recognize task as a Gitea URL to fetch: %s", uri)
+		}
+		ref, path = split[4], split[5:]
+	}
+	return &giteaInfo{
+		Organization: split[0],
+		Repository:   split[1],
+		Revision:     ref,
+		FilePath:     strings.Join(path, "/"),
+	}, nil
+}
+
+// GetTaskURI if we are getting a URL from the same URL where the provider is,
+// it means we can try to get the file with the provider token.
+func (v *Provider) GetTaskURI(ctx context.Context, event *info.Event, uri string) (bool, string, error) {
+	if ret := provider.CompareHostOfURLS(uri, event.URL); !ret {
+		return false, "", nil
+	}
+	extracted, err := extractGiteaInfo(uri)
+	if err != nil {
+		return false, "", err
+	}
+
+	nEvent := info.NewEvent()
+	nEvent.Organization = extracted.Organization
+	nEvent.Repository = extracted.Repository
+	nEvent.BaseBranch = extracted.Revision
+	ret, err := v.GetFileInsideRepo(ctx, nEvent, extracted.FilePath, extracted.Revision)
+	if err != nil {
+		return false, "", err
+	}
+	return true, ret, nil
+}
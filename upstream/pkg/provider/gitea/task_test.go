@@ -0,0 +1,144 @@
+package gitea
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	tgitea "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitea/test"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestExtractGiteaInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected *giteaInfo
+		wantErr  bool
+	}{
+		{
+			name: "raw branch",
+			url:  "https://gitea.example.com/org/repo/raw/branch/main/tasks/task.yaml",
+			expected: &giteaInfo{
+				Organization: "org",
+				Repository:   "repo",
+				Revision:     "main",
+				FilePath:     "tasks/task.yaml",
+			},
+		},
+		{
+			name: "browse tag",
+			url:  "https://gitea.example.com/org/repo/src/tag/v1.0/task.yaml",
+			expected: &giteaInfo{
+				Organization: "org",
+				Repository:   "repo",
+				Revision:     "v1.0",
+				FilePath:     "task.yaml",
+			},
+		},
+		{
+			name: "raw commit",
+			url:  "https://gitea.example.com/org/repo/raw/commit/abcdef/task.yaml",
+			expected: &giteaInfo{
+				Organization: "org",
+				Repository:   "repo",
+				Revision:     "abcdef",
+				FilePath:     "task.yaml",
+			},
+		},
+		{
+			name: "legacy raw without ref type",
+			url:  "https://gitea.example.com/org/repo/raw/main/task.yaml",
+			expected: &giteaInfo{
+				Organization: "org",
+				Repository:   "repo",
+				Revision:     "main",
+				FilePath:     "task.yaml",
+			},
+		},
+		{
+			name:    "missing file path",
+			url:     "https://gitea.example.com/org/repo/raw/branch/main",
+			wantErr: true,
+		},
+		{
+			name:    "not a file url",
+			url:     "https://gitea.example.com/org/repo/pulls/1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := extractGiteaInfo(tt.url)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, info, tt.expected)
+		})
+	}
+}
+
+func TestGetTaskURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventURL    string
+		uri         string
+		wantAllowed bool
+		wantErr     bool
+		wantContent string
+	}{
+		{
+			name:        "fetch from the same host",
+			eventURL:    "https://gitea.example.com/owner/repo",
+			uri:         "https://gitea.example.com/org/tasks/raw/branch/main/task.yaml",
+			wantAllowed: true,
+			wantContent: "hello world",
+		},
+		{
+			name:     "not the same host",
+			eventURL: "https://gitea.example.com/owner/repo",
+			uri:      "https://other.example.com/org/tasks/raw/branch/main/task.yaml",
+		},
+		{
+			name:     "unrecognized url",
+			eventURL: "https://gitea.example.com/owner/repo",
+			uri:      "https://gitea.example.com/org/tasks",
+			wantErr:  true,
+		},
+		{
+			name:     "file not found",
+			eventURL: "https://gitea.example.com/owner/repo",
+			uri:      "https://gitea.example.com/org/tasks/raw/branch/main/notfound.yaml",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			fakeclient, mux, teardown := tgitea.Setup(t)
+			defer teardown()
+			mux.HandleFunc("/repos/org/tasks/contents/task.yaml", func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("ref"), "main")
+				fmt.Fprintf(rw, `{"content": "%s"}`, base64.StdEncoding.EncodeToString([]byte("hello world")))
+			})
+
+			v := &Provider{giteaClient: fakeclient}
+			event := info.NewEvent()
+			event.URL = tt.eventURL
+			allowed, content, err := v.GetTaskURI(ctx, event, tt.uri)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Equal(t, tt.wantContent, content)
+		})
+	}
+}
@@ -202,6 +202,25 @@ func TestOkToTestComment(t *testing.T) {
 			wantErr:          false,
 			rememberOkToTest: true,
 		},
+		{
+			// the Approve (ok-to-test) button of the check run leaves no
+			// comment, the approval is not remembered on the next push
+			name:          "approved with the check run button",
+			commentsReply: `[{"body": "Foo Bar", "user": {"login": "owner"}}]`,
+			runevent: info.Event{
+				Organization: "owner",
+				Sender:       "nonowner",
+				EventType:    "pull_request",
+				Event: &github.PullRequestEvent{
+					PullRequest: &github.PullRequest{
+						HTMLURL: github.Ptr("http://url.com/owner/repo/1"),
+					},
+				},
+			},
+			allowed:          false,
+			wantErr:          false,
+			rememberOkToTest: true,
+		},
 		{
 			name:          "good issue comment event without remember",
 			commentsReply: `{"body": "/ok-to-test", "user": {"login": "owner"}}`,
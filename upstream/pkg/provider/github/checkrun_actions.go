@@ -0,0 +1,154 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/opscomments"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+)
+
+// The identifiers of the buttons we add on the check runs, GitHub sends them
+// back in the requested_action of the check_run event when a user clicks on
+// them. They are limited to 20 characters.
+const (
+	checkRunActionCancel       = "cancel"
+	checkRunActionRetestFailed = "retest-failed"
+	checkRunActionOkToTest     = "ok-to-test"
+)
+
+// checkRunActionCommand is the GitOps command a check run button is a
+// shortcut for.
+type checkRunActionCommand struct {
+	triggerType triggertype.Trigger
+	eventType   opscomments.EventType
+}
+
+var checkRunActionCommands = map[string]checkRunActionCommand{
+	checkRunActionCancel:       {triggerType: triggertype.Cancel, eventType: opscomments.CancelCommentSingleEventType},
+	checkRunActionRetestFailed: {triggerType: triggertype.Retest, eventType: opscomments.RetestFailedCommentEventType},
+	checkRunActionOkToTest:     {triggerType: triggertype.OkToTest, eventType: opscomments.OkToTestCommentEventType},
+}
+
+// checkRunUpdate is the payload used to update a check run, the actions are
+// always sent since an empty list is the only way to remove the buttons of a
+// previous status.
+type checkRunUpdate struct {
+	github.UpdateCheckRunOptions
+	Actions []*github.CheckRunAction `json:"actions"`
+}
+
+// checkRunActions returns the buttons to show on a check run for a status:
+// approving the commit when waiting for an /ok-to-test, the approval is not
+// remembered by remember-ok-to-test since no comment is left, cancelling the
+// PipelineRun while it's running and rerunning the failed PipelineRuns once
+// it has failed or has been cancelled.
+func checkRunActions(statusOpts provider.StatusOpts) []*github.CheckRunAction {
+	switch {
+	case statusOpts.Status == "queued" && statusOpts.Title == pendingApproval:
+		return []*github.CheckRunAction{{
+			Label:       "Approve (ok-to-test)",
+			Description: "Run the CI for this commit only",
+			Identifier:  checkRunActionOkToTest,
+		}}
+	case statusOpts.PipelineRunName == "":
+		return []*github.CheckRunAction{}
+	case statusOpts.Status == "queued" || statusOpts.Status == "in_progress":
+		return []*github.CheckRunAction{{
+			Label:       "Cancel",
+			Description: "Cancel this PipelineRun",
+			Identifier:  checkRunActionCancel,
+		}}
+	case statusOpts.Conclusion == "failure" || statusOpts.Conclusion == "cancelled" ||
+		isPipelineRunCancelledOrStopped(statusOpts.PipelineRun):
+		return []*github.CheckRunAction{{
+			Label:       "Re-run failed",
+			Description: "Run again the failed PipelineRuns",
+			Identifier:  checkRunActionRetestFailed,
+		}}
+	}
+	return []*github.CheckRunAction{}
+}
+
+// updateCheckRun updates a check run and replaces its buttons by actions.
+func (v *Provider) updateCheckRun(ctx context.Context, runevent *info.Event, checkRunID int64, opts github.UpdateCheckRunOptions, actions []*github.CheckRunAction) (*github.CheckRun, *github.Response, error) {
+	u := fmt.Sprintf("repos/%v/%v/check-runs/%v", runevent.Organization, runevent.Repository, checkRunID)
+	req, err := v.Client().NewRequest(http.MethodPatch, u, checkRunUpdate{UpdateCheckRunOptions: opts, Actions: actions})
+	if err != nil {
+		return nil, nil, err
+	}
+	checkRun := &github.CheckRun{}
+	resp, err := v.Client().Do(ctx, req, checkRun)
+	if err != nil {
+		return nil, resp, err
+	}
+	return checkRun, resp, nil
+}
+
+// handleRequestedActionEvent converts a click on one of the buttons of a
+// check run to the event of the GitOps command it is a shortcut for. The
+// sender is the user who clicked on the button, so the same ACL as when they
+// comment the command applies.
+func (v *Provider) handleRequestedActionEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
+	runevent := info.NewEvent()
+	if event.GetRepo() == nil {
+		return nil, errors.New("error parsing payload the repository should not be nil")
+	}
+	identifier := requestedActionIdentifier(event)
+	command, ok := checkRunActionCommands[identifier]
+	if !ok {
+		return nil, fmt.Errorf("check run requested action %q is not supported", identifier)
+	}
+	runevent.Organization = event.GetRepo().GetOwner().GetLogin()
+	runevent.Repository = event.GetRepo().GetName()
+	runevent.URL = event.GetRepo().GetHTMLURL()
+	runevent.DefaultBranch = event.GetRepo().GetDefaultBranch()
+	runevent.SHA = event.GetCheckRun().GetCheckSuite().GetHeadSHA()
+	runevent.HeadBranch = event.GetCheckRun().GetCheckSuite().GetHeadBranch()
+	runevent.HeadURL = event.GetCheckRun().GetCheckSuite().GetRepository().GetHTMLURL()
+	runevent.Sender = event.GetSender().GetLogin()
+	runevent.EventType = command.eventType.String()
+	v.userType = event.GetSender().GetType()
+	if identifier == checkRunActionCancel {
+		runevent.CancelPipelineRuns = true
+		runevent.TargetCancelPipelineRun = v.checkRunPipelineRunName(event.GetCheckRun().GetName())
+	}
+
+	if len(event.GetCheckRun().GetCheckSuite().PullRequests) == 0 {
+		if identifier == checkRunActionOkToTest {
+			return nil, fmt.Errorf("check run requested action %q is only supported on pull requests", identifier)
+		}
+		runevent.BaseBranch = runevent.HeadBranch
+		runevent.BaseURL = runevent.HeadURL
+		runevent.TriggerTarget = triggertype.Push
+		return runevent, nil
+	}
+	runevent.PullRequestNumber = event.GetCheckRun().GetCheckSuite().PullRequests[0].GetNumber()
+	runevent.TriggerTarget = triggertype.PullRequest
+	v.Logger.Infof("check_run: %s of PR %s/%s#%d has been requested by %s", identifier, runevent.Organization, runevent.Repository, runevent.PullRequestNumber, runevent.Sender)
+	return v.getPullRequest(ctx, runevent)
+}
+
+// requestedActionIdentifier returns the identifier of the button clicked by
+// the user in a requested_action check_run event.
+func requestedActionIdentifier(event *github.CheckRunEvent) string {
+	if event.GetRequestedAction() == nil {
+		return ""
+	}
+	return event.GetRequestedAction().Identifier
+}
+
+// checkRunPipelineRunName returns the name of the PipelineRun template from
+// the name of its check run, as set by provider.GetCheckName.
+func (v *Provider) checkRunPipelineRunName(checkRunName string) string {
+	if v.pacInfo == nil || v.pacInfo.ApplicationName == "" {
+		return checkRunName
+	}
+	return strings.TrimPrefix(checkRunName, v.pacInfo.ApplicationName+" / ")
+}
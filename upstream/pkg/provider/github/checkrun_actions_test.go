@@ -0,0 +1,105 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckRunActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusOpts provider.StatusOpts
+		want       string
+	}{
+		{
+			name: "pending approval",
+			statusOpts: provider.StatusOpts{
+				Status:     "queued",
+				Conclusion: "pending",
+				Title:      pendingApproval,
+			},
+			want: checkRunActionOkToTest,
+		},
+		{
+			name: "running",
+			statusOpts: provider.StatusOpts{
+				PipelineRunName: "pr-abcde",
+				Status:          "in_progress",
+			},
+			want: checkRunActionCancel,
+		},
+		{
+			name: "queued by concurrency",
+			statusOpts: provider.StatusOpts{
+				PipelineRunName: "pr-abcde",
+				Status:          "queued",
+				Conclusion:      "pending",
+				Title:           "Pending",
+			},
+			want: checkRunActionCancel,
+		},
+		{
+			name: "failed",
+			statusOpts: provider.StatusOpts{
+				PipelineRunName: "pr-abcde",
+				Status:          "completed",
+				Conclusion:      "failure",
+			},
+			want: checkRunActionRetestFailed,
+		},
+		{
+			name: "cancelled",
+			statusOpts: provider.StatusOpts{
+				PipelineRunName: "pr-abcde",
+				Status:          "completed",
+				Conclusion:      "neutral",
+				PipelineRun: &tektonv1.PipelineRun{
+					Spec: tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusCancelled},
+				},
+			},
+			want: checkRunActionRetestFailed,
+		},
+		{
+			name: "succeeded",
+			statusOpts: provider.StatusOpts{
+				PipelineRunName: "pr-abcde",
+				Status:          "completed",
+				Conclusion:      "success",
+			},
+		},
+		{
+			name: "failed without pipelinerun",
+			statusOpts: provider.StatusOpts{
+				Status:     "completed",
+				Conclusion: "failure",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := checkRunActions(tt.statusOpts)
+			assert.Assert(t, actions != nil)
+			if tt.want == "" {
+				assert.Equal(t, len(actions), 0)
+				return
+			}
+			assert.Equal(t, len(actions), 1)
+			assert.Equal(t, actions[0].Identifier, tt.want)
+			assert.Assert(t, len(actions[0].Label) <= 20)
+			assert.Assert(t, len(actions[0].Description) <= 40)
+		})
+	}
+}
+
+func TestCheckRunPipelineRunName(t *testing.T) {
+	v := &Provider{}
+	assert.Equal(t, v.checkRunPipelineRunName("my-pipeline"), "my-pipeline")
+
+	v.pacInfo = &info.PacOpts{Settings: settings.Settings{ApplicationName: "Pipelines as Code CI"}}
+	assert.Equal(t, v.checkRunPipelineRunName("Pipelines as Code CI / my-pipeline"), "my-pipeline")
+}
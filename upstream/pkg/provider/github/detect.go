@@ -85,6 +85,12 @@ func (v *Provider) detectTriggerTypeFromPayload(ghEventType string, eventInt any
 			if provider.IsCancelComment(event.GetComment().GetBody()) {
 				return triggertype.Cancel, ""
 			}
+			if provider.IsHoldComment(event.GetComment().GetBody()) {
+				return triggertype.Hold, ""
+			}
+			if provider.IsHelpComment(event.GetComment().GetBody()) {
+				return triggertype.Help, ""
+			}
 		}
 		return triggertype.Comment, ""
 	case *github.CheckSuiteEvent:
@@ -96,7 +102,18 @@ func (v *Provider) detectTriggerTypeFromPayload(ghEventType string, eventInt any
 		if event.GetAction() == "rerequested" && event.GetCheckRun() != nil {
 			return triggertype.CheckRunRerequested, ""
 		}
+		if event.GetAction() == "requested_action" && event.GetCheckRun() != nil {
+			if command, ok := checkRunActionCommands[requestedActionIdentifier(event)]; ok {
+				return command.triggerType, ""
+			}
+			return "", fmt.Sprintf("check_run: unsupported requested action \"%s\"", requestedActionIdentifier(event))
+		}
 		return "", fmt.Sprintf("check_run: unsupported action \"%s\"", event.GetAction())
+	case *github.MergeGroupEvent:
+		if event.GetAction() == "checks_requested" && event.GetMergeGroup() != nil {
+			return triggertype.MergeGroup, ""
+		}
+		return "", fmt.Sprintf("merge_group: unsupported action \"%s\"", event.GetAction())
 	case *github.CommitCommentEvent:
 		if event.GetAction() == "created" {
 			if provider.IsTestRetestComment(event.GetComment().GetBody()) {
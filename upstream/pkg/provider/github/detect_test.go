@@ -59,6 +59,33 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: true,
 		},
+		{
+			name: "valid check run requested action Event",
+			event: github.CheckRunEvent{
+				Action: github.Ptr("requested_action"),
+				CheckRun: &github.CheckRun{
+					ID: &idd,
+				},
+				RequestedAction: &github.RequestedAction{Identifier: "retest-failed"},
+			},
+			eventType:  "check_run",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "unsupported check run requested action Event",
+			event: github.CheckRunEvent{
+				Action: github.Ptr("requested_action"),
+				CheckRun: &github.CheckRun{
+					ID: &idd,
+				},
+				RequestedAction: &github.RequestedAction{Identifier: "deploy"},
+			},
+			eventType:  "check_run",
+			wantReason: "check_run: unsupported requested action \"deploy\"",
+			isGH:       true,
+			processReq: false,
+		},
 		{
 			name: "unsupported Event",
 			event: github.CommitCommentEvent{
@@ -87,6 +114,27 @@ func TestProvider_Detect(t *testing.T) {
 			isGH:       true,
 			processReq: false,
 		},
+		{
+			name: "valid merge group Event",
+			event: github.MergeGroupEvent{
+				Action: github.Ptr("checks_requested"),
+				MergeGroup: &github.MergeGroup{
+					HeadSHA: github.Ptr("sha"),
+				},
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: true,
+		},
+		{
+			name: "invalid merge group Event",
+			event: github.MergeGroupEvent{
+				Action: github.Ptr("destroyed"),
+			},
+			eventType:  "merge_group",
+			isGH:       true,
+			processReq: false,
+		},
 		{
 			name: "invalid issue comment Event",
 			event: github.IssueCommentEvent{
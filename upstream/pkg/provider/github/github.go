@@ -477,6 +477,7 @@ func (v *Provider) getPullRequest(ctx context.Context, runevent *info.Event) (*i
 	runevent.SHA = pr.GetHead().GetSHA()
 	runevent.SHAURL = fmt.Sprintf("%s/commit/%s", pr.GetHTMLURL(), pr.GetHead().GetSHA())
 	runevent.PullRequestTitle = pr.GetTitle()
+	runevent.PullRequestDraft = pr.GetDraft()
 
 	// TODO: check if we really need this
 	if runevent.Sender == "" {
@@ -560,6 +561,36 @@ func (v *Provider) GetFiles(ctx context.Context, runevent *info.Event) (changedf
 		}
 		return changedFiles, nil
 	}
+
+	if runevent.TriggerTarget == triggertype.MergeGroup {
+		// the changes of a merge group are the ones between the target branch and its head commit
+		mergeGroupEvent, ok := runevent.Event.(*github.MergeGroupEvent)
+		if !ok {
+			return changedfiles.ChangedFiles{}, fmt.Errorf("cannot get the merge group from the event")
+		}
+		changedFiles := changedfiles.ChangedFiles{}
+		comparison, _, err := wrapAPI(v, "compare_commits", func() (*github.CommitsComparison, *github.Response, error) {
+			return v.Client().Repositories.CompareCommits(ctx, runevent.Organization, runevent.Repository,
+				mergeGroupEvent.GetMergeGroup().GetBaseSHA(), mergeGroupEvent.GetMergeGroup().GetHeadSHA(), &github.ListOptions{})
+		})
+		if err != nil {
+			return changedfiles.ChangedFiles{}, err
+		}
+		for _, file := range comparison.Files {
+			changedFiles.All = append(changedFiles.All, file.GetFilename())
+			switch file.GetStatus() {
+			case "added":
+				changedFiles.Added = append(changedFiles.Added, file.GetFilename())
+			case "removed":
+				changedFiles.Deleted = append(changedFiles.Deleted, file.GetFilename())
+			case "modified":
+				changedFiles.Modified = append(changedFiles.Modified, file.GetFilename())
+			case "renamed":
+				changedFiles.Renamed = append(changedFiles.Renamed, file.GetFilename())
+			}
+		}
+		return changedFiles, nil
+	}
 	return changedfiles.ChangedFiles{}, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -24,6 +25,10 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// mergeGroupRefRegexp matches the head ref of a merge group, ie:
+// refs/heads/gh-readonly-queue/main/pr-123-<sha>.
+var mergeGroupRefRegexp = regexp.MustCompile(`/gh-readonly-queue/.+/pr-(\d+)-[0-9a-f]+$`)
+
 // GetAppIDAndPrivateKey retrieves the GitHub application ID and private key from a secret in the specified namespace.
 // It takes a context, namespace, and Kubernetes client as input parameters.
 // It returns the application ID (int64), private key ([]byte), and an error if any.
@@ -98,9 +103,12 @@ func (v *Provider) parseEventType(request *http.Request, event *info.Event) erro
 
 	event.Provider.URL = request.Header.Get("X-GitHub-Enterprise-Host")
 
-	if event.EventType == "push" {
+	switch event.EventType {
+	case "push":
 		event.TriggerTarget = triggertype.Push
-	} else {
+	case triggertype.MergeGroup.String():
+		event.TriggerTarget = triggertype.MergeGroup
+	default:
 		event.TriggerTarget = triggertype.PullRequest
 	}
 
@@ -282,10 +290,13 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 			return nil, fmt.Errorf("check run rerequest is only supported with github apps integration")
 		}
 
-		if *gitEvent.Action != "rerequested" {
-			return nil, fmt.Errorf("only issue recheck is supported in checkrunevent")
+		switch gitEvent.GetAction() {
+		case "rerequested":
+			return v.handleReRequestEvent(ctx, gitEvent)
+		case "requested_action":
+			return v.handleRequestedActionEvent(ctx, gitEvent)
 		}
-		return v.handleReRequestEvent(ctx, gitEvent)
+		return nil, fmt.Errorf("only issue recheck and requested actions are supported in checkrunevent")
 	case *github.CheckSuiteEvent:
 		if v.ghClient == nil {
 			return nil, fmt.Errorf("check suite rerequest is only supported with github apps integration")
@@ -378,6 +389,32 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 		processedEvent.BaseURL = gitEvent.GetRepo().GetHTMLURL()
 		processedEvent.HeadURL = processedEvent.BaseURL // in push events Head URL is the same as BaseURL
 		v.userType = gitEvent.GetSender().GetType()
+	case *github.MergeGroupEvent:
+		if gitEvent.GetRepo() == nil {
+			return nil, errors.New("error parsing payload the repository should not be nil")
+		}
+		if gitEvent.GetAction() != "checks_requested" {
+			return nil, fmt.Errorf("only checks_requested is supported in merge_group event, received: %s", gitEvent.GetAction())
+		}
+		mergeGroup := gitEvent.GetMergeGroup()
+		processedEvent.Organization = gitEvent.GetRepo().GetOwner().GetLogin()
+		processedEvent.Repository = gitEvent.GetRepo().GetName()
+		processedEvent.DefaultBranch = gitEvent.GetRepo().GetDefaultBranch()
+		processedEvent.URL = gitEvent.GetRepo().GetHTMLURL()
+		v.RepositoryIDs = []int64{gitEvent.GetRepo().GetID()}
+		// the checks have to be reported on the temporary commit GitHub
+		// created for the merge group and not on the pull request head.
+		processedEvent.SHA = mergeGroup.GetHeadSHA()
+		processedEvent.SHATitle = mergeGroup.GetHeadCommit().GetMessage()
+		processedEvent.SHAURL = fmt.Sprintf("%s/commit/%s", gitEvent.GetRepo().GetHTMLURL(), mergeGroup.GetHeadSHA())
+		processedEvent.Sender = gitEvent.GetSender().GetLogin()
+		processedEvent.BaseBranch = strings.TrimPrefix(mergeGroup.GetBaseRef(), "refs/heads/")
+		processedEvent.HeadBranch = strings.TrimPrefix(mergeGroup.GetHeadRef(), "refs/heads/")
+		processedEvent.BaseURL = gitEvent.GetRepo().GetHTMLURL()
+		processedEvent.HeadURL = processedEvent.BaseURL
+		processedEvent.PullRequestNumber = mergeGroupPullRequestNumber(mergeGroup.GetHeadRef())
+		processedEvent.EventType = event.TriggerTarget.String()
+		v.userType = gitEvent.GetSender().GetType()
 	case *github.PullRequestEvent:
 		processedEvent.Repository = gitEvent.GetRepo().GetName()
 		if gitEvent.GetRepo() == nil {
@@ -405,6 +442,7 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 
 		processedEvent.PullRequestNumber = gitEvent.GetPullRequest().GetNumber()
 		processedEvent.PullRequestTitle = gitEvent.GetPullRequest().GetTitle()
+		processedEvent.PullRequestDraft = gitEvent.GetPullRequest().GetDraft()
 		// getting the repository ids of the base and head of the pull request
 		// to scope the token to
 		v.RepositoryIDs = []int64{
@@ -426,6 +464,19 @@ func (v *Provider) processEvent(ctx context.Context, event *info.Event, eventInt
 	return processedEvent, nil
 }
 
+// mergeGroupPullRequestNumber returns the number of the pull request a merge
+// group has been created for, from its head ref in the form of
+// refs/heads/gh-readonly-queue/main/pr-123-<sha>. When multiple pull requests
+// are grouped the ref only references the last one.
+func mergeGroupPullRequestNumber(headRef string) int {
+	matches := mergeGroupRefRegexp.FindStringSubmatch(headRef)
+	if len(matches) != 2 {
+		return 0
+	}
+	number, _ := strconv.Atoi(matches[1])
+	return number
+}
+
 func (v *Provider) handleReRequestEvent(ctx context.Context, event *github.CheckRunEvent) (*info.Event, error) {
 	runevent := info.NewEvent()
 	if event.GetRepo() == nil {
@@ -393,8 +393,8 @@ func TestParsePayLoad(t *testing.T) {
 			payloadEventStruct: github.PullRequestReviewCommentEvent{Action: github.Ptr("created")},
 		},
 		{
-			name:               "bad/check run only issue recheck supported",
-			wantErrString:      "only issue recheck is supported",
+			name:               "bad/check run only issue recheck and requested actions supported",
+			wantErrString:      "only issue recheck and requested actions are supported",
 			eventType:          "check_run",
 			triggerTarget:      "nonopetitrobot",
 			payloadEventStruct: github.CheckRunEvent{Action: github.Ptr("created")},
@@ -531,6 +531,73 @@ func TestParsePayLoad(t *testing.T) {
 			},
 			shaRet: "headSHACheckSuite",
 		},
+		{
+			name:          "good/requested action cancel on pull request",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: string(triggertype.PullRequest),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.Ptr("requested_action"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					Name: github.Ptr("pr-cancelled"),
+					CheckSuite: &github.CheckSuite{
+						PullRequests: []*github.PullRequest{&samplePR},
+					},
+				},
+				RequestedAction: &github.RequestedAction{Identifier: "cancel"},
+			},
+			muxReplies:              map[string]any{"/repos/owner/reponame/pulls/54321": samplePR},
+			shaRet:                  "samplePRsha",
+			targetCancelPipelinerun: "pr-cancelled",
+		},
+		{
+			name:          "good/requested action retest failed on push",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: string(triggertype.Push),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.Ptr("requested_action"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					CheckSuite: &github.CheckSuite{
+						HeadSHA: github.Ptr("headSHACheckSuite"),
+					},
+				},
+				RequestedAction: &github.RequestedAction{Identifier: "retest-failed"},
+			},
+			shaRet: "headSHACheckSuite",
+		},
+		{
+			name:          "bad/requested action ok-to-test on push",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: string(triggertype.Push),
+			payloadEventStruct: github.CheckRunEvent{
+				Action: github.Ptr("requested_action"),
+				Repo:   sampleRepo,
+				CheckRun: &github.CheckRun{
+					CheckSuite: &github.CheckSuite{
+						HeadSHA: github.Ptr("headSHACheckSuite"),
+					},
+				},
+				RequestedAction: &github.RequestedAction{Identifier: "ok-to-test"},
+			},
+			wantErrString: "check run requested action \"ok-to-test\" is only supported on pull requests",
+		},
+		{
+			name:          "bad/requested action not supported",
+			eventType:     "check_run",
+			githubClient:  true,
+			triggerTarget: string(triggertype.PullRequest),
+			payloadEventStruct: github.CheckRunEvent{
+				Action:          github.Ptr("requested_action"),
+				Repo:            sampleRepo,
+				CheckRun:        &github.CheckRun{},
+				RequestedAction: &github.RequestedAction{Identifier: "deploy"},
+			},
+			wantErrString: "check run requested action \"deploy\" is not supported",
+		},
 		{
 			name:               "bad/issue_comment_not_from_created",
 			wantErrString:      "only newly created comment is supported, received: deleted",
@@ -583,6 +650,32 @@ func TestParsePayLoad(t *testing.T) {
 			},
 			shaRet: "SHAPush",
 		},
+		{
+			name:          "good/merge group",
+			eventType:     "merge_group",
+			triggerTarget: triggertype.MergeGroup.String(),
+			payloadEventStruct: github.MergeGroupEvent{
+				Action: github.Ptr("checks_requested"),
+				Repo:   sampleRepo,
+				MergeGroup: &github.MergeGroup{
+					HeadSHA: github.Ptr("SHAMergeGroup"),
+					HeadRef: github.Ptr("refs/heads/gh-readonly-queue/main/pr-123-abcdef"),
+					BaseRef: github.Ptr("refs/heads/main"),
+				},
+			},
+			shaRet: "SHAMergeGroup",
+		},
+		{
+			name:          "bad/merge group unsupported action",
+			eventType:     "merge_group",
+			triggerTarget: triggertype.MergeGroup.String(),
+			payloadEventStruct: github.MergeGroupEvent{
+				Action:     github.Ptr("destroyed"),
+				Repo:       sampleRepo,
+				MergeGroup: &github.MergeGroup{},
+			},
+			wantErrString: "only checks_requested is supported in merge_group event",
+		},
 		{
 			name:          "good/issue comment for retest",
 			eventType:     "issue_comment",
@@ -997,6 +1090,11 @@ func TestParsePayLoad(t *testing.T) {
 				assert.Equal(t, tt.wantedBranchName, ret.BaseBranch)
 				assert.Equal(t, tt.isCancelPipelineRunEnabled, ret.CancelPipelineRuns)
 			}
+			if tt.eventType == triggertype.MergeGroup.String() {
+				assert.Equal(t, "main", ret.BaseBranch)
+				assert.Equal(t, "gh-readonly-queue/main/pr-123-abcdef", ret.HeadBranch)
+				assert.Equal(t, 123, ret.PullRequestNumber)
+			}
 			if tt.targetPipelinerun != "" {
 				assert.Equal(t, tt.targetPipelinerun, ret.TargetTestPipelineRun)
 			}
@@ -275,7 +275,7 @@ func (v *Provider) getOrUpdateCheckRunStatus(ctx context.Context, runevent *info
 	}
 
 	_, _, err = wrapAPI(v, "update_check_run", func() (*github.CheckRun, *github.Response, error) {
-		return v.Client().Checks.UpdateCheckRun(ctx, runevent.Organization, runevent.Repository, *checkRunID, opts)
+		return v.updateCheckRun(ctx, runevent, *checkRunID, opts, checkRunActions(statusOpts))
 	})
 	return err
 }
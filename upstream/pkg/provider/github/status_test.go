@@ -250,6 +250,7 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 		githubApps         bool
 		accessDenied       bool
 		isBot              bool
+		actionIdentifier   string
 	}
 	tests := []struct {
 		name                 string
@@ -318,6 +319,7 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 				detailsURL:         "https://cireport.com",
 				nilCompletedAtDate: true,
 				githubApps:         true,
+				actionIdentifier:   "cancel",
 			},
 			want:    &github.CheckRun{ID: &resultid},
 			wantErr: false,
@@ -325,13 +327,14 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 		{
 			name: "failure",
 			args: args{
-				runevent:    runEvent,
-				status:      "completed",
-				conclusion:  "failure",
-				text:        "Nay",
-				detailsURL:  "https://cireport.com",
-				titleSubstr: "Failed",
-				githubApps:  true,
+				runevent:         runEvent,
+				status:           "completed",
+				conclusion:       "failure",
+				text:             "Nay",
+				detailsURL:       "https://cireport.com",
+				titleSubstr:      "Failed",
+				githubApps:       true,
+				actionIdentifier: "retest-failed",
 			},
 			want:    &github.CheckRun{ID: &resultid},
 			wantErr: false,
@@ -354,14 +357,15 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 		{
 			name: "success from bot",
 			args: args{
-				runevent:    runEvent,
-				status:      "completed",
-				conclusion:  "failure",
-				text:        "Nay",
-				detailsURL:  "https://cireport.com",
-				titleSubstr: "Failed",
-				githubApps:  true,
-				isBot:       true,
+				runevent:         runEvent,
+				status:           "completed",
+				conclusion:       "failure",
+				text:             "Nay",
+				detailsURL:       "https://cireport.com",
+				titleSubstr:      "Failed",
+				githubApps:       true,
+				isBot:            true,
+				actionIdentifier: "retest-failed",
 			},
 			wantErr: false,
 			want:    &github.CheckRun{ID: &resultid},
@@ -369,13 +373,14 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 		{
 			name: "skipped",
 			args: args{
-				runevent:    runEvent,
-				status:      "queued",
-				conclusion:  "pending",
-				text:        "Skipit",
-				detailsURL:  "https://cireport.com",
-				titleSubstr: "Pending",
-				githubApps:  true,
+				runevent:         runEvent,
+				status:           "queued",
+				conclusion:       "pending",
+				text:             "Skipit",
+				detailsURL:       "https://cireport.com",
+				titleSubstr:      "Pending",
+				githubApps:       true,
+				actionIdentifier: "cancel",
 			},
 			want:    &github.CheckRun{ID: &resultid},
 			wantErr: false,
@@ -435,6 +440,14 @@ func TestGithubProviderCreateStatus(t *testing.T) {
 				assert.Equal(t, checkRun.Output.GetText(), tt.args.text)
 				assert.Equal(t, checkRun.GetDetailsURL(), tt.args.detailsURL)
 				assert.Assert(t, strings.Contains(checkRun.Output.GetTitle(), tt.args.titleSubstr))
+				update := &github.UpdateCheckRunOptions{}
+				assert.NilError(t, json.Unmarshal(bit, update))
+				if tt.args.actionIdentifier == "" {
+					assert.Equal(t, len(update.Actions), 0)
+				} else {
+					assert.Equal(t, len(update.Actions), 1)
+					assert.Equal(t, update.Actions[0].Identifier, tt.args.actionIdentifier)
+				}
 				_, err = fmt.Fprintf(rw, `{"id": %d}`, resultid)
 				assert.NilError(t, err)
 			})
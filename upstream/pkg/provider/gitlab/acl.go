@@ -4,12 +4,83 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/acl"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/policy"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 )
 
+// accessLevels maps the access level names allowed as suffix of a policy group
+// to their GitLab value.
+var accessLevels = map[string]gitlab.AccessLevelValue{
+	"guest":      gitlab.GuestPermissions,
+	"planner":    gitlab.PlannerPermissions,
+	"reporter":   gitlab.ReporterPermissions,
+	"developer":  gitlab.DeveloperPermissions,
+	"maintainer": gitlab.MaintainerPermissions,
+	"owner":      gitlab.OwnerPermissions,
+}
+
+// parsePolicyGroup splits a policy entry into the full path of the group and
+// the minimum access level required, entries are in the form of
+// `group/subgroup` or `group/subgroup:maintainer`. When no access level is
+// specified any member of the group (guest and above) is allowed.
+func parsePolicyGroup(entry string) (string, gitlab.AccessLevelValue, error) {
+	groupPath, level, found := strings.Cut(entry, ":")
+	groupPath = strings.Trim(strings.TrimSpace(groupPath), "/")
+	if groupPath == "" {
+		return "", gitlab.NoPermissions, fmt.Errorf("invalid policy group: %q", entry)
+	}
+	if !found {
+		return groupPath, gitlab.GuestPermissions, nil
+	}
+	accessLevel, ok := accessLevels[strings.ToLower(strings.TrimSpace(level))]
+	if !ok {
+		return "", gitlab.NoPermissions, fmt.Errorf("invalid access level %q for policy group: %s", level, groupPath)
+	}
+	return groupPath, accessLevel, nil
+}
+
+// CheckPolicyAllowing check that policy is allowing the event to be processed
+// we check the membership of the user in the allowed GitLab groups, membership
+// inherited from a parent group counts so a policy can target a nested subgroup.
+// if the group is not found we explicitly disallow the policy, user have to correct the setting.
+func (v *Provider) CheckPolicyAllowing(_ context.Context, event *info.Event, allowedGroups []string) (bool, string) {
+	if v.userID == 0 {
+		return false, fmt.Sprintf("cannot find the user id of the sender: %s", event.Sender)
+	}
+	for _, allowedGroup := range allowedGroups {
+		groupPath, minAccessLevel, err := parsePolicyGroup(allowedGroup)
+		if err != nil {
+			return false, err.Error()
+		}
+		// TODO: caching
+		member, resp, err := v.Client().GroupMembers.GetInheritedGroupMember(groupPath, v.userID)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			// a not found may either mean the user is not a member or that
+			// the group doesn't exist, we explicitly disallow the policy on
+			// the latter, as we do for GitHub teams.
+			if _, gresp, gerr := v.Client().Groups.GetGroup(groupPath, &gitlab.GetGroupOptions{}); gresp != nil && gresp.StatusCode == http.StatusNotFound {
+				return false, fmt.Sprintf("group: %s is not found", groupPath)
+			} else if gerr != nil {
+				return false, fmt.Sprintf("error while getting group: %s, error: %s", groupPath, gerr.Error())
+			}
+			continue
+		}
+		if err != nil {
+			// probably a 500 or another api error, no need to try again and again with other groups
+			return false, fmt.Sprintf("error while getting group membership for user: %s in group: %s, error: %s", event.Sender, groupPath, err.Error())
+		}
+		if member.ID == v.userID && member.State != "blocked" && member.AccessLevel >= minAccessLevel {
+			return true, fmt.Sprintf("allowing user: %s as a member of the group: %s", event.Sender, groupPath)
+		}
+	}
+
+	return false, fmt.Sprintf("user: %s is not a member of any of the allowed groups: %v", event.Sender, allowedGroups)
+}
+
 // IsAllowedOwnersFile get the owner files (OWNERS, OWNERS_ALIASES) from main branch
 // and check if we have explicitly allowed the user in there.
 func (v *Provider) IsAllowedOwnersFile(_ context.Context, event *info.Event) (bool, error) {
@@ -97,9 +168,37 @@ func (v *Provider) IsAllowed(ctx context.Context, event *info.Event) (bool, erro
 		return false, fmt.Errorf("no github client has been initialized, " +
 			"exiting... (hint: did you forget setting a secret on your repo?)")
 	}
+
+	aclPolicy := policy.Policy{
+		Repository:   v.repo,
+		EventEmitter: v.eventEmitter,
+		Event:        event,
+		VCX:          v,
+		Logger:       v.Logger,
+	}
+
+	// Try to detect a policy rule allowing this
+	policyAllowed, policyReason := aclPolicy.IsAllowed(ctx, detectTriggerTypeFromEvent(event.Event))
+	switch policyAllowed {
+	case policy.ResultAllowed:
+		return true, nil
+	case policy.ResultDisallowed:
+		return false, nil
+	case policy.ResultNotSet: // this is to make golangci-lint happy
+	}
+
 	if v.checkMembership(ctx, event, v.userID) {
 		return true, nil
 	}
 
-	return v.checkOkToTestCommentFromApprovedMember(ctx, event, 1)
+	allowed, err := v.checkOkToTestCommentFromApprovedMember(ctx, event, 1)
+	if err != nil || allowed {
+		return allowed, err
+	}
+
+	// error with the policy reason if it was set
+	if policyReason != "" {
+		return false, fmt.Errorf("%s", policyReason)
+	}
+	return false, nil
 }
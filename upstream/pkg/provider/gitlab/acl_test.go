@@ -5,8 +5,15 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
 	thelp "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/gitlab/test"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 )
 
@@ -261,3 +268,146 @@ func TestMembershipAPIFailureDoesNotCacheApiError(t *testing.T) {
 		t.Fatalf("expected membership API to be called again after retry, got %d total calls (initial %d)", calls, initialCallCount)
 	}
 }
+
+func TestCheckPolicyAllowing(t *testing.T) {
+	groups := map[string]map[int]int{
+		"group":                    {123: 50},
+		"group/subgroup":           {123: 50, 456: 30},
+		"group/subgroup/nested":    {123: 50, 456: 30, 789: 10},
+		"othergroup":               {},
+		"group/subgroup/reporters": {789: 20},
+	}
+	tests := []struct {
+		name          string
+		userID        int
+		allowedGroups []string
+		wantAllowed   bool
+		wantReason    string
+	}{
+		{
+			name:          "allowed as member of the group",
+			userID:        123,
+			allowedGroups: []string{"group"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender as a member of the group: group",
+		},
+		{
+			name:          "allowed as member of a nested subgroup",
+			userID:        789,
+			allowedGroups: []string{"othergroup", "group/subgroup/nested"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender as a member of the group: group/subgroup/nested",
+		},
+		{
+			name:          "allowed with minimum access level",
+			userID:        456,
+			allowedGroups: []string{"group/subgroup:developer"},
+			wantAllowed:   true,
+			wantReason:    "allowing user: sender as a member of the group: group/subgroup",
+		},
+		{
+			name:          "disallowed below minimum access level",
+			userID:        789,
+			allowedGroups: []string{"group/subgroup/reporters:Maintainer"},
+			wantReason:    "user: sender is not a member of any of the allowed groups: [group/subgroup/reporters:Maintainer]",
+		},
+		{
+			name:          "disallowed not a member",
+			userID:        456,
+			allowedGroups: []string{"group", "othergroup"},
+			wantReason:    "user: sender is not a member of any of the allowed groups: [group othergroup]",
+		},
+		{
+			name:          "disallowed group not found",
+			userID:        123,
+			allowedGroups: []string{"notfound"},
+			wantReason:    "group: notfound is not found",
+		},
+		{
+			name:          "disallowed invalid access level",
+			userID:        123,
+			allowedGroups: []string{"group:superadmin"},
+			wantReason:    `invalid access level "superadmin" for policy group: group`,
+		},
+		{
+			name:          "disallowed without a user id",
+			allowedGroups: []string{"group"},
+			wantReason:    "cannot find the user id of the sender: sender",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(t)
+			defer tearDown()
+			thelp.MuxGroupMembers(mux, groups)
+
+			v := &Provider{gitlabClient: client, userID: tt.userID}
+			gotAllowed, gotReason := v.CheckPolicyAllowing(ctx, &info.Event{Sender: "sender"}, tt.allowedGroups)
+			assert.Equal(t, tt.wantAllowed, gotAllowed)
+			assert.Equal(t, tt.wantReason, gotReason)
+		})
+	}
+}
+
+func TestIsAllowedWithPolicy(t *testing.T) {
+	okToTestEvent := &gitlab.MergeCommentEvent{}
+	okToTestEvent.ObjectAttributes.Note = "/ok-to-test"
+
+	tests := []struct {
+		name        string
+		userID      int
+		event       any
+		policy      *v1alpha1.Policy
+		wantAllowed bool
+	}{
+		{
+			name:        "allowed on pull request by group policy",
+			userID:      123,
+			event:       &gitlab.MergeEvent{},
+			policy:      &v1alpha1.Policy{PullRequest: []string{"group/subgroup"}},
+			wantAllowed: true,
+		},
+		{
+			name:   "disallowed on pull request by group policy",
+			userID: 456,
+			event:  &gitlab.MergeEvent{},
+			policy: &v1alpha1.Policy{PullRequest: []string{"group/subgroup"}},
+		},
+		{
+			name:   "ok-to-test comment disallowed by group policy",
+			userID: 456,
+			event:  okToTestEvent,
+			policy: &v1alpha1.Policy{OkToTest: []string{"group"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			client, mux, tearDown := thelp.Setup(t)
+			defer tearDown()
+			thelp.MuxGroupMembers(mux, map[string]map[int]int{
+				"group":          {123: 50},
+				"group/subgroup": {123: 50},
+			})
+			thelp.MuxDisallowUserID(mux, 2525, tt.userID)
+			thelp.MuxDiscussionsNoteEmpty(mux, 2525, 1)
+
+			v := &Provider{
+				gitlabClient:    client,
+				userID:          tt.userID,
+				targetProjectID: 2525,
+				repo: &v1alpha1.Repository{
+					ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+					Spec: v1alpha1.RepositorySpec{
+						Settings: &v1alpha1.Settings{Policy: tt.policy},
+					},
+				},
+				eventEmitter: events.NewEventEmitter(fake.NewSimpleClientset(), zap.NewNop().Sugar()),
+			}
+			got, err := v.IsAllowed(ctx, &info.Event{Sender: "sender", PullRequestNumber: 1, Event: tt.event})
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantAllowed, got)
+		})
+	}
+}
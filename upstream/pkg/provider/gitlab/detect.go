@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/triggertype"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 	"go.uber.org/zap"
@@ -39,9 +40,10 @@ func (v *Provider) Detect(req *http.Request, payload string, logger *zap.Sugared
 	switch gitEvent := eventInt.(type) {
 	case *gitlab.MergeEvent:
 		// on a MR update, react only if OldRev is empty (no new commits pushed).
-		// If OldRev is empty, it's a metadata-only update (e.g., label changes).
+		// If OldRev is empty, it's a metadata-only update (e.g., label changes
+		// or the MR being marked as ready).
 		if gitEvent.ObjectAttributes.Action == "update" && gitEvent.ObjectAttributes.OldRev == "" {
-			if !hasOnlyLabelsChanged(gitEvent) {
+			if !hasOnlyLabelsChanged(gitEvent) && !isMarkedAsReady(gitEvent) {
 				return setLoggerAndProceed(false, "this 'Merge Request' update event changes are not supported; cannot proceed", nil)
 			}
 		}
@@ -108,3 +110,42 @@ func hasOnlyLabelsChanged(gitEvent *gitlab.MergeEvent) bool {
 
 	return onlyUpdatedAtOrLabels
 }
+
+// isMarkedAsReady checks if the merge request has just been switched from a
+// draft to ready.
+func isMarkedAsReady(gitEvent *gitlab.MergeEvent) bool {
+	return gitEvent.Changes.Draft.Previous && !gitEvent.Changes.Draft.Current
+}
+
+// detectTriggerTypeFromEvent returns the trigger type of a parsed GitLab
+// event, this is used to know which policy rule applies to it.
+func detectTriggerTypeFromEvent(eventInt any) triggertype.Trigger {
+	switch gitEvent := eventInt.(type) {
+	case *gitlab.MergeEvent:
+		if gitEvent.ObjectAttributes.Action == "close" {
+			return triggertype.PullRequestClosed
+		}
+		if gitEvent.Changes.Labels.Current != nil {
+			return triggertype.PullRequestLabeled
+		}
+		return triggertype.PullRequest
+	case *gitlab.MergeCommentEvent:
+		comment := gitEvent.ObjectAttributes.Note
+		switch {
+		case provider.IsTestRetestComment(comment):
+			return triggertype.Retest
+		case provider.IsOkToTestComment(comment):
+			return triggertype.OkToTest
+		case provider.IsCancelComment(comment):
+			return triggertype.Cancel
+		case provider.IsHoldComment(comment):
+			return triggertype.Hold
+		case provider.IsHelpComment(comment):
+			return triggertype.Help
+		}
+		return triggertype.Comment
+	case *gitlab.PushEvent, *gitlab.TagEvent, *gitlab.CommitCommentEvent:
+		return triggertype.Push
+	}
+	return ""
+}
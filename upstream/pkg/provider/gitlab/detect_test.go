@@ -86,6 +86,20 @@ func TestProvider_Detect(t *testing.T) {
 			isGL:       true,
 			processReq: false,
 		},
+		{
+			name:       "good/mergeRequest update Event marked as ready",
+			event:      strings.Replace(sample.MREventAsJSON("update", `"draft": false`), "{", `{"changes": {"draft": {"previous": true, "current": false}},`, 1),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: true,
+		},
+		{
+			name:       "bad/mergeRequest update Event marked as draft",
+			event:      strings.Replace(sample.MREventAsJSON("update", `"draft": true`), "{", `{"changes": {"draft": {"previous": false, "current": true}},`, 1),
+			eventType:  gitlab.EventTypeMergeRequest,
+			isGL:       true,
+			processReq: false,
+		},
 		{
 			name:       "good/note event",
 			event:      sample.NoteEventAsJSON("abc"),
@@ -126,11 +126,6 @@ func (v *Provider) CreateComment(_ context.Context, event *info.Event, commit, u
 	return err
 }
 
-// CheckPolicyAllowing TODO: Implement ME.
-func (v *Provider) CheckPolicyAllowing(_ context.Context, _ *info.Event, _ []string) (bool, string) {
-	return false, ""
-}
-
 func (v *Provider) SetLogger(logger *zap.SugaredLogger) {
 	v.Logger = logger
 }
@@ -91,6 +91,20 @@ func TestParsePayload(t *testing.T) {
 				SHATitle:      "commit it",
 			},
 		},
+		{
+			name: "merge event draft",
+			args: args{
+				event:   gitlab.EventTypeMergeRequest,
+				payload: sample.MREventAsJSON("open", `"draft": true`),
+			},
+			want: &info.Event{
+				EventType:        "Merge Request",
+				TriggerTarget:    "pull_request",
+				Organization:     "hello/this/is/me/ze",
+				Repository:       "project",
+				PullRequestDraft: true,
+			},
+		},
 		{
 			name: "merge event closed",
 			args: args{
@@ -497,6 +511,7 @@ func TestParsePayload(t *testing.T) {
 				assert.Equal(t, tt.want.EventType, got.EventType)
 				assert.Equal(t, tt.want.Organization, got.Organization)
 				assert.Equal(t, tt.want.Repository, got.Repository)
+				assert.Equal(t, tt.want.PullRequestDraft, got.PullRequestDraft)
 				if tt.want.TargetTestPipelineRun != "" {
 					assert.Equal(t, tt.want.TargetTestPipelineRun, got.TargetTestPipelineRun)
 				}
@@ -5,7 +5,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -78,6 +80,35 @@ func MuxAllowUserIDCounting(mux *http.ServeMux, projectID, userID int, counter *
 	})
 }
 
+// MuxGroupMembers serves the group and inherited group members API. groups
+// maps the full path of a group to the access level of its members by user
+// ID, groups that are not in the map are reported as not found.
+func MuxGroupMembers(mux *http.ServeMux, groups map[string]map[int]int) {
+	mux.HandleFunc("/groups/", func(rw http.ResponseWriter, r *http.Request) {
+		escaped := strings.TrimPrefix(r.URL.EscapedPath(), "/groups/")
+		groupPath, memberPath, _ := strings.Cut(escaped, "/")
+		groupPath, _ = url.PathUnescape(groupPath)
+		members, ok := groups[groupPath]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"message": "404 Group Not Found"}`)
+			return
+		}
+		if memberPath == "" {
+			fmt.Fprintf(rw, `{"id": 1, "full_path": %q}`, groupPath)
+			return
+		}
+		userID, _ := strconv.Atoi(strings.TrimPrefix(memberPath, "members/all/"))
+		accessLevel, ok := members[userID]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, `{"message": "404 Not found"}`)
+			return
+		}
+		fmt.Fprintf(rw, `{"id": %d, "state": "active", "access_level": %d}`, userID, accessLevel)
+	})
+}
+
 func MuxListTektonDir(_ *testing.T, mux *http.ServeMux, pid int, ref, prs string, wantTreeAPIErr, wantFilesAPIErr bool) {
 	mux.HandleFunc(fmt.Sprintf("/projects/%d/repository/tree", pid), func(rw http.ResponseWriter, r *http.Request) {
 		if wantTreeAPIErr {
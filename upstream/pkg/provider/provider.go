@@ -20,15 +20,19 @@ const ValidationErrorTemplate = `> [!CAUTION]
 var (
 	testRetestAllRegex    = regexp.MustCompile(`(?m)^(/retest|/test)\s*$`)
 	testRetestSingleRegex = regexp.MustCompile(`(?m)^(/test|/retest)[ \t]+\S+`)
+	retestFailedRegex     = regexp.MustCompile(`(?m)^/retest-failed([ \t]+(branch|tag):\S+)?\s*$`)
 	oktotestRegex         = regexp.MustCompile(`(?m)^/ok-to-test\s*$`)
 	cancelAllRegex        = regexp.MustCompile(`(?m)^(/cancel)\s*$`)
 	cancelSingleRegex     = regexp.MustCompile(`(?m)^(/cancel)[ \t]+\S+`)
+	holdUnholdRegex       = regexp.MustCompile(`(?m)^(/hold([ \t]+cancel)?|/unhold)\s*$`)
+	helpRegex             = regexp.MustCompile(`(?m)^/help\s*$`)
 )
 
 const (
-	testComment   = "/test"
-	retestComment = "/retest"
-	cancelComment = "/cancel"
+	testComment         = "/test"
+	retestComment       = "/retest"
+	retestFailedComment = "/retest-failed"
+	cancelComment       = "/cancel"
 )
 
 const (
@@ -77,7 +81,13 @@ func Valid(value string, validValues []string) bool {
 }
 
 func IsTestRetestComment(comment string) bool {
-	return testRetestSingleRegex.MatchString(comment) || testRetestAllRegex.MatchString(comment)
+	return testRetestSingleRegex.MatchString(comment) || testRetestAllRegex.MatchString(comment) || IsRetestFailedComment(comment)
+}
+
+// IsRetestFailedComment returns true when the comment is a `/retest-failed`
+// command, optionally targeting a branch or a tag on a pushed commit.
+func IsRetestFailedComment(comment string) bool {
+	return retestFailedRegex.MatchString(comment)
 }
 
 func IsOkToTestComment(comment string) bool {
@@ -88,6 +98,14 @@ func IsCancelComment(comment string) bool {
 	return cancelAllRegex.MatchString(comment) || cancelSingleRegex.MatchString(comment)
 }
 
+func IsHoldComment(comment string) bool {
+	return holdUnholdRegex.MatchString(comment)
+}
+
+func IsHelpComment(comment string) bool {
+	return helpRegex.MatchString(comment)
+}
+
 func GetPipelineRunFromTestComment(comment string) string {
 	if strings.Contains(comment, testComment) {
 		return getNameFromComment(testComment, comment)
@@ -108,6 +126,10 @@ func getNameFromComment(typeOfComment, comment string) string {
 }
 
 func GetPipelineRunAndBranchOrTagNameFromTestComment(comment string) (string, string, string, error) {
+	// `/retest-failed` doesn't target a PipelineRun, only a branch or a tag
+	if IsRetestFailedComment(comment) {
+		return getPipelineRunAndBranchOrTagNameFromComment(retestFailedComment, comment)
+	}
 	if strings.Contains(comment, testComment) {
 		return getPipelineRunAndBranchOrTagNameFromComment(testComment, comment)
 	}
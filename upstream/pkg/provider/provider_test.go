@@ -105,6 +105,78 @@ func TestCancelComment(t *testing.T) {
 	}
 }
 
+func TestIsHoldComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    bool
+	}{
+		{
+			name:    "hold",
+			comment: "/hold",
+			want:    true,
+		},
+		{
+			name:    "hold and cancel",
+			comment: "/hold cancel",
+			want:    true,
+		},
+		{
+			name:    "unhold with some string before",
+			comment: "all good now\n/unhold",
+			want:    true,
+		},
+		{
+			name:    "invalid hold argument",
+			comment: "/hold abc",
+			want:    false,
+		},
+		{
+			name:    "invalid",
+			comment: "/holding",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsHoldComment(tt.comment)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsHelpComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    bool
+	}{
+		{
+			name:    "help",
+			comment: "/help",
+			want:    true,
+		},
+		{
+			name:    "help with some string before",
+			comment: "what can I do?\n/help",
+			want:    true,
+		},
+		{
+			name:    "invalid",
+			comment: "/helpme",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsHelpComment(tt.comment)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestIsTestRetestComment(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -156,6 +228,16 @@ func TestIsTestRetestComment(t *testing.T) {
 			comment: "/retest abc",
 			want:    true,
 		},
+		{
+			name:    "retest failed",
+			comment: "/lgtm \n/retest-failed",
+			want:    true,
+		},
+		{
+			name:    "retest failed on a tag",
+			comment: "/retest-failed tag:v1.0",
+			want:    true,
+		},
 		{
 			name:    "test trigger single pr",
 			comment: "/test abc",
@@ -373,6 +455,17 @@ func TestGetPipelineRunAndBranchNameFromTestComment(t *testing.T) {
 			prName:    "abc-01-pr",
 			wantError: false,
 		},
+		{
+			name:      "retest failed",
+			comment:   "/retest-failed",
+			wantError: false,
+		},
+		{
+			name:       "retest failed on a branch",
+			comment:    "/retest-failed branch:nightly",
+			branchName: "nightly",
+			wantError:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/archive"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/generated/injection/informers/pipelinesascode/v1alpha1/repository"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
@@ -39,6 +40,8 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 		if err != nil {
 			log.Fatal("failed to init kinit client : ", err)
 		}
+		// archive the PipelineRuns before the cleanups delete them
+		kinteract.Archiver = archive.NewArchiver(run, kinteract)
 
 		// Start pac config syncer
 		go params.StartConfigSync(ctx, run)
@@ -54,16 +57,28 @@ func NewController() func(context.Context, configmap.Watcher) *controller.Impl {
 			kinteract:         kinteract,
 			pipelineRunLister: pipelineRunInformer.Lister(),
 			repoLister:        repository.Get(ctx).Lister(),
-			qm:                sync.NewQueueManager(run.Clients.Log),
 			metrics:           metrics,
 			eventEmitter:      events.NewEventEmitter(run.Clients.Kube, run.Clients.Log),
 		}
+		r.qm = sync.NewPersistentQueueManager(run.Clients.Log,
+			sync.NewConfigMapQueueStore(run.Clients.Kube, system.Namespace()), r.pipelineRunPriority, r.concurrencyQuotas)
+		// the quotas have to be known before the queues are rebuilt, the
+		// config syncer may not have loaded them yet
+		_ = run.UpdatePacConfig(ctx)
 		impl := tektonPipelineRunReconcilerv1.NewImpl(ctx, r, ctrlOpts())
+		r.enqueueAfter = impl.EnqueueKeyAfter
 
 		if err := r.qm.InitQueues(ctx, run.Clients.Tekton, run.Clients.PipelineAsCode); err != nil {
 			log.Fatal("failed to init queues", err)
 		}
 
+		// delete the completed PipelineRuns past their retention, only on the
+		// replica elected for the retention bucket
+		if la, ok := impl.Reconciler.(leaderChecker); ok {
+			r.isLeaderFor = la.IsLeaderFor
+		}
+		go r.startRetention(ctx, log)
+
 		if _, err := pipelineRunInformer.Informer().AddEventHandler(controller.HandleAll(checkStateAndEnqueue(impl))); err != nil {
 			logging.FromContext(ctx).Panicf("Couldn't register PipelineRun informer event handler: %w", err)
 		}
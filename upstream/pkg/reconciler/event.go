@@ -79,7 +79,11 @@ func buildEventFromPipelineRun(pr *tektonv1.PipelineRun) *info.Event {
 	prNumber := prAnno[keys.PullRequest]
 	if prNumber != "" {
 		event.PullRequestNumber, _ = strconv.Atoi(prNumber)
-		event.TriggerTarget = triggertype.PullRequest
+		// a merge group references the pull request it has been queued for
+		// but its status is reported on the merge group commit.
+		if event.TriggerTarget != triggertype.MergeGroup {
+			event.TriggerTarget = triggertype.PullRequest
+		}
 	}
 
 	// GitHub
@@ -7,6 +7,7 @@ import (
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,7 +46,10 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *tektonv1.PipelineRun)
 			repo.Spec.Merge(r.globalRepo.Spec)
 		}
 		logger = logger.With("namespace", repo.Namespace)
+		r.queuePositions.forget(sync.PrKey(pr))
 		next := r.qm.RemoveAndTakeItemFromQueue(repo, pr)
+		// the PipelineRuns still waiting in the queue moved up
+		defer r.reportQueuePositions(ctx, logger, repo, sync.QueueKey(repo, sync.PipelineRunGroup(pr)))
 		if next != "" {
 			key := strings.Split(next, "/")
 			pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(key[0]).Get(ctx, key[1], metav1.GetOptions{})
@@ -53,7 +57,7 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, pr *tektonv1.PipelineRun)
 				return err
 			}
 			if err := r.
-				updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
+				updatePipelineRunToInProgress(ctx, logger, r.pipelineRunRepository(logger, repo, pr), pr); err != nil {
 				logger.Errorf("failed to update status: %w", err)
 				return err
 			}
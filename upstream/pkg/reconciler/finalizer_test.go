@@ -110,6 +110,7 @@ func TestReconciler_FinalizeKind(t *testing.T) {
 				run: &params.Run{
 					Clients: clients.Clients{
 						PipelineAsCode: stdata.PipelineAsCode,
+						Tekton:         stdata.Pipeline,
 					},
 					Info: info.Info{
 						Kube:       &info.KubeOpts{Namespace: "pac"},
@@ -120,7 +121,7 @@ func TestReconciler_FinalizeKind(t *testing.T) {
 
 			if len(tt.addToQueue) != 0 {
 				for _, pr := range tt.addToQueue {
-					_, err := r.qm.AddListToRunningQueue(finalizeTestRepo, []string{pr.GetNamespace() + "/" + pr.GetName()})
+					_, err := r.qm.AddListToRunningQueue(finalizeTestRepo, sync.Group{}, []string{pr.GetNamespace() + "/" + pr.GetName()})
 					assert.NilError(t, err)
 				}
 			}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacAPIv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"go.uber.org/zap"
@@ -52,9 +55,15 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		repo.Spec.Merge(r.globalRepo.Spec)
 	}
 
+	// the PipelineRuns of a concurrency group are queued with their group
+	// whatever the concurrency limit of the repository
+	group := sync.PipelineRunGroup(pr)
+
 	// if concurrency was set and later removed or changed to zero
-	// then remove pipelineRun from Queue and update pending state to running
-	if repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 {
+	// then remove pipelineRun from Queue and update pending state to running,
+	// unless the quotas of the namespace still throttle it
+	if group.Name == "" && repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit == 0 &&
+		!r.concurrencyQuotas().Enabled(repo.GetNamespace()) {
 		_ = r.qm.RemoveAndTakeItemFromQueue(repo, pr)
 		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
 			return fmt.Errorf("failed to update PipelineRun to in_progress: %w", err)
@@ -62,13 +71,18 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		return nil
 	}
 
+	// tkn pac queue promote asks for the PipelineRun to be started next
+	if _, ok := pr.GetAnnotations()[keys.QueuePromote]; ok {
+		r.promotePipelineRun(ctx, logger, repo, group, pr)
+	}
+
 	var processed bool
 	var itered int
 	maxIterations := 5
 
 	orderedList := sync.FilterPipelineRunByState(ctx, r.run.Clients.Tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
 	for {
-		acquired, err := r.qm.AddListToRunningQueue(repo, orderedList)
+		acquired, err := r.qm.AddListToRunningQueue(repo, group, orderedList)
 		if err != nil {
 			return fmt.Errorf("failed to add to queue: %s: %w", pr.GetName(), err)
 		}
@@ -79,15 +93,15 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 
 		for _, prKeys := range acquired {
 			nsName := strings.Split(prKeys, "/")
-			repoKey := sync.RepoKey(repo)
+			queueKey := sync.QueueKey(repo, group)
 			pr, err = r.run.Clients.Tekton.TektonV1().PipelineRuns(nsName[0]).Get(ctx, nsName[1], metav1.GetOptions{})
 			if err != nil {
 				logger.Info("failed to get pr with namespace and name: ", nsName[0], nsName[1])
-				_ = r.qm.RemoveFromQueue(repoKey, prKeys)
+				_ = r.qm.RemoveFromQueue(queueKey, prKeys)
 			} else {
 				if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
 					logger.Errorf("failed to update pipelineRun to in_progress: %w", err)
-					_ = r.qm.RemoveFromQueue(repoKey, prKeys)
+					_ = r.qm.RemoveFromQueue(queueKey, prKeys)
 				} else {
 					processed = true
 				}
@@ -101,5 +115,79 @@ func (r *Reconciler) queuePipelineRun(ctx context.Context, logger *zap.SugaredLo
 		}
 		itered++
 	}
+	r.reportQueuePositions(ctx, logger, repo, sync.QueueKey(repo, group))
 	return nil
 }
+
+// promotePipelineRun moves a queued PipelineRun to the front of its queue and
+// removes the queue-promote annotation so it is only promoted once.
+func (r *Reconciler) promotePipelineRun(ctx context.Context, logger *zap.SugaredLogger, repo *pacAPIv1alpha1.Repository, group sync.Group, pr *tektonv1.PipelineRun) {
+	if !r.qm.PromoteInQueue(sync.QueueKey(repo, group), sync.PrKey(pr)) {
+		logger.Infof("pipelineRun %s/%s is not pending in the queue, not promoting it", pr.GetNamespace(), pr.GetName())
+	}
+	mergePatch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]any{keys.QueuePromote: nil},
+		},
+	}
+	if _, err := action.PatchPipelineRun(ctx, logger, "queue-promote annotation", r.run.Clients.Tekton, pr, mergePatch); err != nil {
+		logger.Errorf("cannot remove the %s annotation: %v", keys.QueuePromote, err)
+	}
+}
+
+// concurrencyQuotas returns the cluster-wide and per-namespace concurrency
+// quotas from the Pipelines-as-Code configuration.
+func (r *Reconciler) concurrencyQuotas() settings.ConcurrencyQuotas {
+	if r.run.Info.Pac == nil {
+		return settings.ConcurrencyQuotas{}
+	}
+	pacOpts := r.run.Info.GetPacOpts()
+	return pacOpts.ConcurrencyQuotas()
+}
+
+// pipelineRunRepository returns the Repository of a PipelineRun started from
+// the queue, with the concurrency quotas it can be another one than the
+// Repository of the PipelineRun which released its place.
+func (r *Reconciler) pipelineRunRepository(logger *zap.SugaredLogger, repo *pacAPIv1alpha1.Repository, pr *tektonv1.PipelineRun) *pacAPIv1alpha1.Repository {
+	repoName := pr.GetAnnotations()[keys.Repository]
+	if pr.GetNamespace() == repo.GetNamespace() && repoName == repo.GetName() {
+		return repo
+	}
+	prRepo, err := r.repoLister.Repositories(pr.GetNamespace()).Get(repoName)
+	if err != nil {
+		logger.Errorf("cannot get repository %s/%s of pipelineRun %s: %v", pr.GetNamespace(), repoName, pr.GetName(), err)
+		return repo
+	}
+	prRepo = prRepo.DeepCopy()
+	if r.globalRepo != nil {
+		prRepo.Spec.Merge(r.globalRepo.Spec)
+	}
+	return prRepo
+}
+
+// pipelineRunPriority returns the priority of a queued PipelineRun and how long
+// it waits to gain one level of priority. The PipelineRun is fetched from the
+// API when it is not in the informer cache yet, like when the queues get
+// initialized at startup, the queue manager calls it before taking its lock.
+func (r *Reconciler) pipelineRunPriority(prKey string) (int, time.Duration) {
+	aging := settings.ConcurrencyPriorityAgingDefaultValue
+	if r.run.Info.Pac != nil {
+		pacOpts := r.run.Info.GetPacOpts()
+		aging = pacOpts.PriorityAging()
+	}
+
+	ns, name, found := strings.Cut(prKey, "/")
+	if !found {
+		return 0, aging
+	}
+	if r.pipelineRunLister != nil {
+		if pr, err := r.pipelineRunLister.PipelineRuns(ns).Get(name); err == nil {
+			return sync.PipelineRunPriority(pr), aging
+		}
+	}
+	pr, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return 0, aging
+	}
+	return sync.PipelineRunPriority(pr), aging
+}
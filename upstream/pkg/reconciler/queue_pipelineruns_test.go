@@ -3,12 +3,14 @@ package reconciler
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
 	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
 	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
 	testconcurrency "github.com/openshift-pipelines/pipelines-as-code/pkg/test/concurrency"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
@@ -208,3 +210,43 @@ func TestQueuePipelineRun(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelineRunPriority(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		PipelineRuns: []*tektonv1.PipelineRun{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "release",
+					Namespace:   "ns",
+					Annotations: map[string]string{keys.Priority: "10"},
+				},
+			},
+		},
+	})
+	r := &Reconciler{
+		run: &params.Run{
+			Info: info.Info{
+				Pac: &info.PacOpts{
+					Settings: settings.Settings{ConcurrencyPriorityAging: "1h"},
+				},
+			},
+			Clients: clients.Clients{
+				Tekton: stdata.Pipeline,
+			},
+		},
+	}
+
+	// without the informer cache the pipelinerun is fetched from the API
+	priority, aging := r.pipelineRunPriority("ns/release")
+	assert.Equal(t, priority, 10)
+	assert.Equal(t, aging, time.Hour)
+
+	r.pipelineRunLister = informers.PipelineRun.Lister()
+	priority, _ = r.pipelineRunPriority("ns/release")
+	assert.Equal(t, priority, 10)
+
+	priority, aging = r.pipelineRunPriority("ns/missing")
+	assert.Equal(t, priority, 0)
+	assert.Equal(t, aging, time.Hour)
+}
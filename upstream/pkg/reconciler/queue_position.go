@@ -0,0 +1,173 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// queuePositionReportInterval is the minimum time between two updates of the
+// queue position of a PipelineRun on the Git provider.
+const queuePositionReportInterval = 30 * time.Second
+
+type queuePositionReport struct {
+	position int
+	total    int
+	reported time.Time
+	// deferred is set when a change has been throttled and the PipelineRun
+	// is requeued to report it at the end of the interval.
+	deferred bool
+}
+
+// queuePositionReports remembers the last queue position reported for every
+// queued PipelineRun, so the Git provider is only updated when it changes and
+// not more often than queuePositionReportInterval.
+type queuePositionReports struct {
+	lock    sync.Mutex
+	reports map[string]queuePositionReport
+}
+
+// shouldReport returns true when the position of the PipelineRun has to be
+// reported now. When the change is throttled, it returns how long to wait
+// before reporting it, this is only returned once per interval so the
+// PipelineRun is not requeued for every change.
+func (q *queuePositionReports) shouldReport(prKey string, position, total int, now time.Time) (bool, time.Duration) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.reports == nil {
+		q.reports = map[string]queuePositionReport{}
+	}
+	if last, ok := q.reports[prKey]; ok {
+		if last.position == position && last.total == total {
+			return false, 0
+		}
+		if wait := queuePositionReportInterval - now.Sub(last.reported); wait > 0 {
+			if last.deferred {
+				return false, 0
+			}
+			last.deferred = true
+			q.reports[prKey] = last
+			return false, wait
+		}
+	}
+	q.reports[prKey] = queuePositionReport{position: position, total: total, reported: now}
+	return true, 0
+}
+
+// forget drops the last reported position of a PipelineRun which has left the
+// queue.
+func (q *queuePositionReports) forget(prKey string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.reports, prKey)
+}
+
+// queueEstimate estimates how long a PipelineRun waits before it starts from
+// its position in the queue, the number of PipelineRuns running from the
+// queue and the average duration of the last PipelineRuns of its Repository.
+// It is zero when no PipelineRun of the Repository has completed yet.
+func (r *Reconciler) queueEstimate(pr *tektonv1.PipelineRun, position, running int) time.Duration {
+	average, ok := r.metrics.AveragePRDuration(pr.GetNamespace(), pr.GetAnnotations()[keys.Repository])
+	if !ok {
+		return 0
+	}
+	waves := (position-1)/max(1, running) + 1
+	return time.Duration(waves) * average
+}
+
+// reportQueuePositions updates the status of the queued PipelineRuns of a
+// queue on the Git provider with their position and how long they are
+// expected to wait.
+func (r *Reconciler) reportQueuePositions(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, queueKey string) {
+	running, pending := r.qm.QueueStatus(queueKey)
+	now := time.Now()
+	for i, prKey := range pending {
+		position := i + 1
+		report, wait := r.queuePositions.shouldReport(prKey, position, len(pending), now)
+		if !report {
+			// reconcile the PipelineRun again at the end of the interval to
+			// report its latest position
+			if wait > 0 && r.enqueueAfter != nil {
+				ns, name, _ := strings.Cut(prKey, "/")
+				r.enqueueAfter(types.NamespacedName{Namespace: ns, Name: name}, wait)
+			}
+			continue
+		}
+		pr, err := r.queuedPipelineRun(ctx, prKey)
+		if err != nil {
+			logger.Errorf("cannot get queued pipelineRun %s: %v", prKey, err)
+			continue
+		}
+		estimate := r.queueEstimate(pr, position, running)
+		if err := r.reportQueuePosition(ctx, logger, repo, pr, formatting.QueuePosition(position, len(pending), estimate)); err != nil {
+			logger.Errorf("failed to report the queue position of pipelineRun %s: %v", prKey, err)
+		}
+	}
+}
+
+// queuedPipelineRun returns a queued PipelineRun from the informer cache, or
+// from the API when it is not there yet.
+func (r *Reconciler) queuedPipelineRun(ctx context.Context, prKey string) (*tektonv1.PipelineRun, error) {
+	ns, name, _ := strings.Cut(prKey, "/")
+	if r.pipelineRunLister != nil {
+		if pr, err := r.pipelineRunLister.PipelineRuns(ns).Get(name); err == nil {
+			return pr, nil
+		}
+	}
+	return r.run.Clients.Tekton.TektonV1().PipelineRuns(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (r *Reconciler) reportQueuePosition(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun, queuePosition string) error {
+	pacInfo := r.run.Info.GetPacOpts()
+	detectedProvider, event, err := r.detectProvider(ctx, logger, pr)
+	if err != nil {
+		return err
+	}
+	detectedProvider.SetPacInfo(&pacInfo)
+	if err := r.setProviderClient(ctx, logger, &pacInfo, repo, detectedProvider, event); err != nil {
+		return err
+	}
+
+	consoleURL := r.run.Clients.ConsoleUI().DetailURL(pr)
+	mt := formatting.MessageTemplate{
+		PipelineRunName: pr.GetName(),
+		Namespace:       repo.GetNamespace(),
+		ConsoleName:     r.run.Clients.ConsoleUI().GetName(),
+		ConsoleURL:      consoleURL,
+		TknBinary:       settings.TknBinaryName,
+		TknBinaryURL:    settings.TknBinaryURL,
+		QueuePosition:   queuePosition,
+	}
+	msg, err := mt.MakeTemplate(detectedProvider.GetTemplate(provider.QueueingPipelineType))
+	if err != nil {
+		return fmt.Errorf("cannot create message template: %w", err)
+	}
+	status := provider.StatusOpts{
+		Status:                  "queued",
+		Conclusion:              "pending",
+		Text:                    msg,
+		DetailsURL:              consoleURL,
+		PipelineRunName:         pr.GetName(),
+		PipelineRun:             pr,
+		OriginalPipelineRunName: pr.GetAnnotations()[keys.OriginalPRName],
+	}
+	if err := detectedProvider.CreateStatus(ctx, event, status); err != nil {
+		return fmt.Errorf("cannot update the queued status: %w", err)
+	}
+	logger.Infof("reported the queue position of pipelineRun %s/%s: %s", pr.GetNamespace(), pr.GetName(), queuePosition)
+	return nil
+}
@@ -0,0 +1,99 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	testconcurrency "github.com/openshift-pipelines/pipelines-as-code/pkg/test/concurrency"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestQueuePositionReportsThrottle(t *testing.T) {
+	reports := &queuePositionReports{}
+	now := time.Now()
+
+	report, wait := reports.shouldReport("ns/pr", 3, 7, now)
+	assert.Assert(t, report)
+	assert.Equal(t, wait, time.Duration(0))
+	// same position, nothing to update
+	report, wait = reports.shouldReport("ns/pr", 3, 7, now.Add(time.Hour))
+	assert.Assert(t, !report)
+	assert.Equal(t, wait, time.Duration(0))
+	// the position moved but the last update is too recent, it is deferred
+	// to the end of the interval
+	report, wait = reports.shouldReport("ns/pr", 2, 6, now.Add(time.Second))
+	assert.Assert(t, !report)
+	assert.Equal(t, wait, queuePositionReportInterval-time.Second)
+	// already deferred, no need to requeue again
+	report, wait = reports.shouldReport("ns/pr", 1, 5, now.Add(2*time.Second))
+	assert.Assert(t, !report)
+	assert.Equal(t, wait, time.Duration(0))
+	// the latest position is reported at the end of the interval
+	report, _ = reports.shouldReport("ns/pr", 1, 5, now.Add(queuePositionReportInterval))
+	assert.Assert(t, report)
+	// another pipelinerun is throttled on its own
+	report, _ = reports.shouldReport("ns/other", 1, 6, now.Add(time.Second))
+	assert.Assert(t, report)
+
+	reports.forget("ns/pr")
+	report, _ = reports.shouldReport("ns/pr", 2, 6, now.Add(queuePositionReportInterval+time.Second))
+	assert.Assert(t, report)
+}
+
+func TestQueueEstimate(t *testing.T) {
+	m, err := metrics.NewRecorder()
+	assert.NilError(t, err)
+	r := &Reconciler{metrics: m}
+
+	pr := &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pr",
+		Namespace:   "queue-estimate",
+		Annotations: map[string]string{keys.Repository: "repo"},
+	}}
+	// nothing has completed yet for this repository
+	assert.Equal(t, r.queueEstimate(pr, 3, 2), time.Duration(0))
+
+	assert.NilError(t, m.CountPRDuration("queue-estimate", "repo", "success", "Succeeded", 4*time.Minute))
+	assert.NilError(t, m.CountPRDuration("queue-estimate", "repo", "failed", "Failed", 2*time.Minute))
+	// cancelled pipelineruns don't tell how long a pipelinerun takes
+	assert.NilError(t, m.CountPRDuration("queue-estimate", "repo", "cancelled", "Cancelled", time.Second))
+
+	// the first two start once the running ones are done
+	assert.Equal(t, r.queueEstimate(pr, 2, 2), 3*time.Minute)
+	assert.Equal(t, r.queueEstimate(pr, 3, 2), 6*time.Minute)
+	assert.Equal(t, r.queueEstimate(pr, 3, 0), 9*time.Minute)
+	assert.Equal(t, (&Reconciler{}).queueEstimate(pr, 1, 1), time.Duration(0))
+}
+
+func TestReportQueuePositionsDeferred(t *testing.T) {
+	requeued := map[types.NamespacedName]time.Duration{}
+	r := &Reconciler{
+		qm: testconcurrency.TestQMI{QueuedPrs: []string{"ns/first", "ns/second"}, RunningQueue: []string{"ns/running"}},
+		enqueueAfter: func(key types.NamespacedName, wait time.Duration) {
+			requeued[key] = wait
+		},
+	}
+	// both have been reported a moment ago at another position
+	now := time.Now()
+	r.queuePositions.shouldReport("ns/first", 3, 3, now)
+	r.queuePositions.shouldReport("ns/second", 1, 2, now)
+
+	r.reportQueuePositions(context.Background(), zap.NewNop().Sugar(), &v1alpha1.Repository{}, "ns/repo")
+	assert.Equal(t, len(requeued), 2)
+	for key, wait := range requeued {
+		assert.Assert(t, wait > 0 && wait <= queuePositionReportInterval, "%s is requeued after %s", key, wait)
+	}
+
+	// the requeue is only asked once per interval
+	clear(requeued)
+	r.reportQueuePositions(context.Background(), zap.NewNop().Sugar(), &v1alpha1.Repository{}, "ns/repo")
+	assert.Equal(t, len(requeued), 0)
+}
@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	pipelinerunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1/pipelinerun"
 	tektonv1lister "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
@@ -43,6 +45,13 @@ type Reconciler struct {
 	eventEmitter      *events.EventEmitter
 	globalRepo        *v1alpha1.Repository
 	secretNS          string
+	queuePositions    queuePositionReports
+	// isLeaderFor tells if this replica is the leader for a key, it is nil
+	// when the reconciler is not leader aware.
+	isLeaderFor func(types.NamespacedName) bool
+	// enqueueAfter reconciles a PipelineRun again after a delay, it is nil
+	// when the reconciler has no queue.
+	enqueueAfter func(types.NamespacedName, time.Duration)
 }
 
 var (
@@ -230,10 +239,19 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	}
 
 	finalState := kubeinteraction.StateCompleted
-	newPr, err := r.postFinalStatus(ctx, logger, pacInfo, provider, event, pr)
+	// a failed PipelineRun which gets retried reports its retry instead of
+	// its failure
+	newPr := pr
+	retryPR, err := r.retryPipelineRun(ctx, logger, pacInfo, repo, provider, event, pr)
 	if err != nil {
-		logger.Errorf("failed to post final status, moving on: %v", err)
-		finalState = kubeinteraction.StateFailed
+		logger.Errorf("failed to retry pipelineRun %s: %v", pr.GetName(), err)
+	}
+	if retryPR == nil {
+		newPr, err = r.postFinalStatus(ctx, logger, pacInfo, provider, event, pr)
+		if err != nil {
+			logger.Errorf("failed to post final status, moving on: %v", err)
+			finalState = kubeinteraction.StateFailed
+		}
 	}
 
 	if err := r.updateRepoRunStatus(ctx, logger, newPr, repo, event); err != nil {
@@ -249,6 +267,8 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 	}
 
 	// remove pipelineRun from Queue and start the next one
+	r.queuePositions.forget(sync.PrKey(pr))
+	queueKey := sync.QueueKey(repo, sync.PipelineRunGroup(pr))
 	for {
 		next := r.qm.RemoveAndTakeItemFromQueue(repo, pr)
 		if next == "" {
@@ -261,13 +281,19 @@ func (r *Reconciler) reportFinalStatus(ctx context.Context, logger *zap.SugaredL
 			continue
 		}
 
-		if err := r.updatePipelineRunToInProgress(ctx, logger, repo, pr); err != nil {
+		nextRepo := r.pipelineRunRepository(logger, repo, pr)
+		if err := r.updatePipelineRunToInProgress(ctx, logger, nextRepo, pr); err != nil {
 			logger.Errorf("failed to update status: %w", err)
-			_ = r.qm.RemoveFromQueue(sync.RepoKey(repo), sync.PrKey(pr))
+			_ = r.qm.RemoveFromQueue(sync.QueueKey(nextRepo, sync.PipelineRunGroup(pr)), sync.PrKey(pr))
 			continue
 		}
+		if nextQueueKey := sync.QueueKey(nextRepo, sync.PipelineRunGroup(pr)); nextQueueKey != queueKey {
+			r.reportQueuePositions(ctx, logger, nextRepo, nextQueueKey)
+		}
 		break
 	}
+	// the PipelineRuns still waiting in the queue moved up
+	r.reportQueuePositions(ctx, logger, repo, queueKey)
 
 	if err := r.cleanupPipelineRuns(ctx, logger, pacInfo, repo, pr); err != nil {
 		return repo, fmt.Errorf("error cleaning pipelineruns: %w", err)
@@ -281,6 +307,7 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 	if err != nil {
 		return fmt.Errorf("cannot update state: %w", err)
 	}
+	r.queuePositions.forget(sync.PrKey(pr))
 	pacInfo := r.run.Info.GetPacOpts()
 	detectedProvider, event, err := r.detectProvider(ctx, logger, pr)
 	if err != nil {
@@ -288,33 +315,8 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 		return nil
 	}
 	detectedProvider.SetPacInfo(&pacInfo)
-
-	if event.InstallationID > 0 {
-		event.Provider.WebhookSecret, _ = pac.GetCurrentNSWebhookSecret(ctx, r.kinteract, r.run)
-	} else {
-		// secretNS is needed when git provider is other than Github.
-		secretNS := repo.GetNamespace()
-		if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret == nil && r.globalRepo != nil && r.globalRepo.Spec.GitProvider != nil && r.globalRepo.Spec.GitProvider.Secret != nil {
-			secretNS = r.globalRepo.GetNamespace()
-		}
-
-		secretFromRepo := pac.SecretFromRepository{
-			K8int:       r.kinteract,
-			Config:      detectedProvider.GetConfig(),
-			Event:       event,
-			Repo:        repo,
-			WebhookType: pacInfo.WebhookType,
-			Logger:      logger,
-			Namespace:   secretNS,
-		}
-		if err := secretFromRepo.Get(ctx); err != nil {
-			return fmt.Errorf("cannot get secret from repository: %w", err)
-		}
-	}
-
-	err = detectedProvider.SetClient(ctx, r.run, event, repo, r.eventEmitter)
-	if err != nil {
-		return fmt.Errorf("cannot set client: %w", err)
+	if err := r.setProviderClient(ctx, logger, &pacInfo, repo, detectedProvider, event); err != nil {
+		return err
 	}
 
 	consoleURL := r.run.Clients.ConsoleUI().DetailURL(pr)
@@ -352,6 +354,39 @@ func (r *Reconciler) updatePipelineRunToInProgress(ctx context.Context, logger *
 	return nil
 }
 
+// setProviderClient sets the client of the provider detected for a PipelineRun
+// with the secret of its Repository, or of the global Repository when the
+// Repository doesn't have one.
+func (r *Reconciler) setProviderClient(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, repo *v1alpha1.Repository, detectedProvider provider.Interface, event *info.Event) error {
+	if event.InstallationID > 0 {
+		event.Provider.WebhookSecret, _ = pac.GetCurrentNSWebhookSecret(ctx, r.kinteract, r.run)
+	} else {
+		// secretNS is needed when git provider is other than Github.
+		secretNS := repo.GetNamespace()
+		if repo.Spec.GitProvider != nil && repo.Spec.GitProvider.Secret == nil && r.globalRepo != nil && r.globalRepo.Spec.GitProvider != nil && r.globalRepo.Spec.GitProvider.Secret != nil {
+			secretNS = r.globalRepo.GetNamespace()
+		}
+
+		secretFromRepo := pac.SecretFromRepository{
+			K8int:       r.kinteract,
+			Config:      detectedProvider.GetConfig(),
+			Event:       event,
+			Repo:        repo,
+			WebhookType: pacInfo.WebhookType,
+			Logger:      logger,
+			Namespace:   secretNS,
+		}
+		if err := secretFromRepo.Get(ctx); err != nil {
+			return fmt.Errorf("cannot get secret from repository: %w", err)
+		}
+	}
+
+	if err := detectedProvider.SetClient(ctx, r.run, event, repo, r.eventEmitter); err != nil {
+		return fmt.Errorf("cannot set client: %w", err)
+	}
+	return nil
+}
+
 func (r *Reconciler) updatePipelineRunState(ctx context.Context, logger *zap.SugaredLogger, pr *tektonv1.PipelineRun, state string) (*tektonv1.PipelineRun, error) {
 	currentState := pr.GetAnnotations()[keys.State]
 	logger.Infof("updating pipelineRun %v/%v state from %s to %s", pr.GetNamespace(), pr.GetName(), currentState, state)
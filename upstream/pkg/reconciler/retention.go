@@ -0,0 +1,124 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/system"
+)
+
+// retentionLeaderKey is the key of the reconciler bucket whose leader applies
+// the retention, so only one replica of the watcher deletes and archives the
+// PipelineRuns.
+const retentionLeaderKey = "pipelines-as-code-retention"
+
+// leaderChecker is implemented by the generated reconciler to know if this
+// replica is the leader of the bucket of a key.
+type leaderChecker interface {
+	IsLeaderFor(key types.NamespacedName) bool
+}
+
+// retentionPolicy returns the retention policy of a Repository. The retention
+// of the Repository, or of the global Repository when it has none, takes
+// precedence over the one of the ConfigMap.
+func retentionPolicy(pacSettings *settings.Settings, repo, globalRepo *v1alpha1.Repository) (kubeinteraction.RetentionPolicy, error) {
+	successful, failed, keepLast := pacSettings.RetentionSuccessful, pacSettings.RetentionFailed, pacSettings.RetentionKeepLast
+	var retention *v1alpha1.Retention
+	if repo.Spec.Settings != nil && repo.Spec.Settings.Retention != nil {
+		retention = repo.Spec.Settings.Retention
+	} else if globalRepo != nil && globalRepo.Spec.Settings != nil {
+		retention = globalRepo.Spec.Settings.Retention
+	}
+	if retention != nil {
+		if retention.Successful != "" {
+			successful = retention.Successful
+		}
+		if retention.Failed != "" {
+			failed = retention.Failed
+		}
+		if retention.KeepLast != nil {
+			keepLast = *retention.KeepLast
+		}
+	}
+
+	policy := kubeinteraction.RetentionPolicy{KeepLast: max(keepLast, 0)}
+	var err error
+	if policy.Successful, err = settings.ParseRetention(successful); err != nil {
+		return policy, fmt.Errorf("successful: %w", err)
+	}
+	if policy.Failed, err = settings.ParseRetention(failed); err != nil {
+		return policy, fmt.Errorf("failed: %w", err)
+	}
+	return policy, nil
+}
+
+// startRetention deletes the completed PipelineRuns past their retention
+// every retention-check-interval until the context is done.
+func (r *Reconciler) startRetention(ctx context.Context, logger *zap.SugaredLogger) {
+	for {
+		interval := settings.RetentionCheckIntervalDefaultValue
+		if r.run.Info.Pac != nil {
+			pacOpts := r.run.Info.GetPacOpts()
+			interval = pacOpts.RetentionInterval()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		r.applyRetention(ctx, logger)
+	}
+}
+
+// applyRetention deletes the completed PipelineRuns of every Repository
+// which are past their retention.
+func (r *Reconciler) applyRetention(ctx context.Context, logger *zap.SugaredLogger) {
+	if r.run.Info.Pac == nil || !r.isRetentionLeader() {
+		return
+	}
+	pacOpts := r.run.Info.GetPacOpts()
+	repos, err := r.repoLister.List(labels.Everything())
+	if err != nil {
+		logger.Errorf("cannot list repositories to apply the retention: %v", err)
+		return
+	}
+	var globalRepo *v1alpha1.Repository
+	if r.run.Info.Kube != nil && r.run.Info.Controller != nil {
+		globalRepo, _ = r.repoLister.Repositories(r.run.Info.Kube.Namespace).Get(r.run.Info.Controller.GlobalRepository)
+	}
+
+	for _, repo := range repos {
+		policy, err := retentionPolicy(&pacOpts.Settings, repo, globalRepo)
+		if err != nil {
+			logger.Errorf("invalid retention for repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+			continue
+		}
+		if !policy.Enabled() {
+			continue
+		}
+		deleted, err := r.kinteract.ApplyRetention(ctx, logger, repo, policy, time.Now())
+		if err != nil {
+			logger.Errorf("cannot apply the retention of repository %s/%s: %v", repo.GetNamespace(), repo.GetName(), err)
+			continue
+		}
+		if deleted > 0 {
+			logger.Infof("deleted %d pipelineruns of repository %s/%s past their retention", deleted, repo.GetNamespace(), repo.GetName())
+		}
+	}
+}
+
+// isRetentionLeader returns true when this replica is the leader of the
+// bucket of retentionLeaderKey, or when leader election is not known.
+func (r *Reconciler) isRetentionLeader() bool {
+	if r.isLeaderFor == nil {
+		return true
+	}
+	return r.isLeaderFor(types.NamespacedName{Namespace: system.Namespace(), Name: retentionLeaderKey})
+}
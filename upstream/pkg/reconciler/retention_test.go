@@ -0,0 +1,133 @@
+package reconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/test/kubernetestint"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+)
+
+func retentionTestRepo(name, namespace string, retention *v1alpha1.Retention) *v1alpha1.Repository {
+	return &v1alpha1.Repository{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: v1alpha1.RepositorySpec{
+			URL:      "https://anywhere.com/owner/" + name,
+			Settings: &v1alpha1.Settings{Retention: retention},
+		},
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	five := 5
+	day := 24 * time.Hour
+	pacSettings := &settings.Settings{RetentionSuccessful: "2d", RetentionFailed: "14d", RetentionKeepLast: 3}
+
+	tests := []struct {
+		name       string
+		retention  *v1alpha1.Retention
+		globalRepo *v1alpha1.Repository
+		want       kubeinteraction.RetentionPolicy
+		wantErr    string
+	}{
+		{
+			name: "configmap",
+			want: kubeinteraction.RetentionPolicy{Successful: 2 * day, Failed: 14 * day, KeepLast: 3},
+		},
+		{
+			name:      "repository overrides some fields",
+			retention: &v1alpha1.Retention{Successful: "12h", KeepLast: &five},
+			want:      kubeinteraction.RetentionPolicy{Successful: 12 * time.Hour, Failed: 14 * day, KeepLast: 5},
+		},
+		{
+			name:       "global repository",
+			globalRepo: retentionTestRepo("pac", "pac", &v1alpha1.Retention{Failed: "30d"}),
+			want:       kubeinteraction.RetentionPolicy{Successful: 2 * day, Failed: 30 * day, KeepLast: 3},
+		},
+		{
+			name:       "repository takes precedence over the global repository",
+			retention:  &v1alpha1.Retention{Successful: "1d"},
+			globalRepo: retentionTestRepo("pac", "pac", &v1alpha1.Retention{Failed: "30d"}),
+			want:       kubeinteraction.RetentionPolicy{Successful: day, Failed: 14 * day, KeepLast: 3},
+		},
+		{
+			name:      "invalid",
+			retention: &v1alpha1.Retention{Failed: "a while"},
+			wantErr:   "failed: invalid retention \"a while\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retentionPolicy(pacSettings, retentionTestRepo("repo", "ns", tt.retention), tt.globalRepo)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+
+	_, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{
+			retentionTestRepo("default", "ns", nil),
+			retentionTestRepo("custom", "ns", &v1alpha1.Retention{Successful: "1h"}),
+			retentionTestRepo("invalid", "ns", &v1alpha1.Retention{Successful: "soon"}),
+		},
+	})
+	kint := &kubernetestint.KinterfaceTest{}
+	r := &Reconciler{
+		repoLister: informers.Repository.Lister(),
+		kinteract:  kint,
+		run: &params.Run{
+			Info: info.Info{
+				Kube:       &info.KubeOpts{Namespace: "pac"},
+				Controller: &info.ControllerInfo{GlobalRepository: "pac"},
+				Pac:        &info.PacOpts{Settings: settings.Settings{RetentionFailed: "7d"}},
+			},
+		},
+	}
+
+	r.applyRetention(ctx, logger)
+	assert.DeepEqual(t, kint.RetentionPolicies, map[string]kubeinteraction.RetentionPolicy{
+		"ns/default": {Failed: 7 * 24 * time.Hour},
+		"ns/custom":  {Successful: time.Hour, Failed: 7 * 24 * time.Hour},
+	})
+
+	// nothing to delete without retention
+	kint.RetentionPolicies = nil
+	r.run.Info.Pac = &info.PacOpts{Settings: settings.Settings{}}
+	r.applyRetention(ctx, logger)
+	assert.DeepEqual(t, kint.RetentionPolicies, map[string]kubeinteraction.RetentionPolicy{
+		"ns/custom": {Successful: time.Hour},
+	})
+
+	// only the leader of the retention bucket applies the retention
+	kint.RetentionPolicies = nil
+	leaderKeys := []types.NamespacedName{}
+	r.isLeaderFor = func(key types.NamespacedName) bool {
+		leaderKeys = append(leaderKeys, key)
+		return false
+	}
+	r.applyRetention(ctx, logger)
+	assert.Assert(t, kint.RetentionPolicies == nil)
+	assert.DeepEqual(t, leaderKeys, []types.NamespacedName{{Namespace: system.Namespace(), Name: retentionLeaderKey}})
+}
@@ -0,0 +1,345 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/action"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/cel"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	kstatus "github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction/status"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/provider"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/secrets"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+)
+
+// retryDroppedAnnotationPrefixes are the prefixes of the annotations set by
+// other controllers on a PipelineRun, which don't apply to its retry.
+var retryDroppedAnnotationPrefixes = []string{"results.tekton.dev/", "chains.tekton.dev/"}
+
+// retryAttempt returns the attempt of a PipelineRun, the PipelineRuns which
+// aren't a retry are the first attempt.
+func retryAttempt(pr *tektonv1.PipelineRun) int {
+	attempt, err := strconv.Atoi(pr.GetAnnotations()[keys.RetryAttempt])
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return attempt
+}
+
+// maxRetries returns the max-retries annotation of a PipelineRun, zero when
+// the annotation is not set or invalid.
+func maxRetries(logger *zap.SugaredLogger, pr *tektonv1.PipelineRun) int {
+	value, ok := pr.GetAnnotations()[keys.MaxRetries]
+	if !ok {
+		return 0
+	}
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 {
+		logger.Warnf("invalid %s annotation %q on pipelineRun %s, it is not retried", keys.MaxRetries, value, pr.GetName())
+		return 0
+	}
+	return retries
+}
+
+// failedTasks returns the sorted names of the pipeline tasks which failed.
+func (r *Reconciler) failedTasks(ctx context.Context, pr *tektonv1.PipelineRun) []string {
+	names := []string{}
+	for _, task := range kstatus.GetStatusFromTaskStatusOrFromAsking(ctx, pr, r.run) {
+		if task.Status == nil {
+			continue
+		}
+		if cond := task.Status.GetCondition(apis.ConditionSucceeded); cond != nil && cond.IsFalse() {
+			names = append(names, task.PipelineTaskName)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// shouldRetry checks if a PipelineRun has failed, without being cancelled,
+// and has some retries left. When the PipelineRun has a
+// retry-on-cel-expression annotation the failure has to match it.
+func (r *Reconciler) shouldRetry(ctx context.Context, logger *zap.SugaredLogger, repo *v1alpha1.Repository, pr *tektonv1.PipelineRun) bool {
+	retries := maxRetries(logger, pr)
+	if retries == 0 {
+		return false
+	}
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	if cond == nil || !cond.IsFalse() || pr.IsCancelled() {
+		return false
+	}
+	switch tektonv1.PipelineRunReason(cond.Reason) {
+	case tektonv1.PipelineRunReasonCancelled, tektonv1.PipelineRunReasonCancelledRunningFinally, tektonv1.PipelineRunReasonStoppedRunningFinally:
+		return false
+	}
+	attempt := retryAttempt(pr)
+	if attempt > retries {
+		logger.Infof("pipelineRun %s has failed after %d attempts, it is not retried anymore", pr.GetName(), attempt)
+		return false
+	}
+
+	expr, ok := pr.GetAnnotations()[keys.RetryCELExpression]
+	if !ok {
+		return true
+	}
+	retry, err := cel.Retry(expr, cond.Reason, cond.Message, r.failedTasks(ctx, pr), attempt)
+	if err != nil {
+		r.eventEmitter.EmitMessage(repo, zap.ErrorLevel, "PipelineRunRetryError",
+			fmt.Sprintf("cannot evaluate the %s annotation of pipelineRun %s: %v", keys.RetryCELExpression, pr.GetName(), err))
+		return false
+	}
+	return retry
+}
+
+// retryName returns the name of the next attempt of a PipelineRun, the
+// attempt number replaces the one of the PipelineRun when it is already a
+// retry. The name is the same on every reconcile of the failed PipelineRun so
+// it is only retried once.
+func retryName(pr *tektonv1.PipelineRun) string {
+	attempt := retryAttempt(pr)
+	base := strings.TrimSuffix(pr.GetName(), fmt.Sprintf("-retry-%d", attempt))
+	return kmeta.ChildName(base, fmt.Sprintf("-retry-%d", attempt+1))
+}
+
+// newRetryPipelineRun returns a copy of a failed PipelineRun to run it again
+// as its next attempt. The copy keeps the check run id of the PipelineRun so
+// the same check run is updated on GitHub.
+func newRetryPipelineRun(pr *tektonv1.PipelineRun) *tektonv1.PipelineRun {
+	annotations := map[string]string{}
+	for key, value := range pr.GetAnnotations() {
+		switch key {
+		case keys.State, keys.SCMReportingPLRStarted, keys.LogURL, keys.ExecutionOrder, keys.QueuePromote:
+			continue
+		}
+		if slices.ContainsFunc(retryDroppedAnnotationPrefixes, func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+			continue
+		}
+		annotations[key] = value
+	}
+	annotations[keys.RetryAttempt] = strconv.Itoa(retryAttempt(pr) + 1)
+
+	labels := map[string]string{}
+	for key, value := range pr.GetLabels() {
+		if key != keys.State {
+			labels[key] = value
+		}
+	}
+
+	spec := pr.Spec.DeepCopy()
+	spec.Status = ""
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        retryName(pr),
+			Namespace:   pr.GetNamespace(),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *spec,
+	}
+}
+
+// renameGitAuthSecret makes the retry of a PipelineRun use a new git auth
+// secret, the secret of the failed PipelineRun gets deleted with it. Only the
+// references to the secret are renamed: the workspaces bound to it and the
+// environment variables and volumes of the embedded tasks using it, the
+// params and the scripts are left as they are.
+func renameGitAuthSecret(pr *tektonv1.PipelineRun, secretName string) {
+	oldName := pr.GetAnnotations()[keys.GitAuthSecret]
+	rename := func(name *string) {
+		if *name == oldName {
+			*name = secretName
+		}
+	}
+	for _, ws := range pr.Spec.Workspaces {
+		if ws.Secret != nil {
+			rename(&ws.Secret.SecretName)
+		}
+	}
+	if ps := pr.Spec.PipelineSpec; ps != nil {
+		for _, tasks := range [][]tektonv1.PipelineTask{ps.Tasks, ps.Finally} {
+			for _, task := range tasks {
+				if task.TaskSpec != nil {
+					renameTaskSecret(&task.TaskSpec.TaskSpec, rename)
+				}
+			}
+		}
+	}
+	pr.Annotations[keys.GitAuthSecret] = secretName
+}
+
+// renameTaskSecret renames the secret references of the steps, the sidecars
+// and the volumes of a task.
+func renameTaskSecret(ts *tektonv1.TaskSpec, rename func(*string)) {
+	renameEnv := func(env []corev1.EnvVar, envFrom []corev1.EnvFromSource) {
+		for _, e := range env {
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+				rename(&e.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+		for _, e := range envFrom {
+			if e.SecretRef != nil {
+				rename(&e.SecretRef.Name)
+			}
+		}
+	}
+	for _, step := range ts.Steps {
+		renameEnv(step.Env, step.EnvFrom)
+	}
+	for _, sidecar := range ts.Sidecars {
+		renameEnv(sidecar.Env, sidecar.EnvFrom)
+	}
+	if ts.StepTemplate != nil {
+		renameEnv(ts.StepTemplate.Env, ts.StepTemplate.EnvFrom)
+	}
+	for _, volume := range ts.Volumes {
+		if volume.Secret != nil {
+			rename(&volume.Secret.SecretName)
+		}
+	}
+}
+
+// createRetryPipelineRun creates the retry of a PipelineRun with its own git
+// auth secret. When the retry already exists, because an earlier reconcile of
+// the failed PipelineRun failed after creating it, the existing one is
+// returned.
+func (r *Reconciler) createRetryPipelineRun(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, repo *v1alpha1.Repository, event *info.Event, retryPR *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+	var authSecret *corev1.Secret
+	if _, ok := retryPR.GetAnnotations()[keys.GitAuthSecret]; ok && pacInfo.SecretAutoCreation {
+		renameGitAuthSecret(retryPR, secrets.GenerateBasicAuthSecretName())
+		var err error
+		if authSecret, err = secrets.MakeBasicAuthSecret(event, retryPR.GetAnnotations()[keys.GitAuthSecret]); err != nil {
+			return nil, fmt.Errorf("making basic auth secret has failed: %w", err)
+		}
+		if err := r.kinteract.CreateSecret(ctx, repo.GetNamespace(), authSecret); err != nil {
+			return nil, fmt.Errorf("creating basic auth secret %s has failed: %w", authSecret.GetName(), err)
+		}
+	}
+
+	created, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(retryPR.GetNamespace()).Create(ctx, retryPR, metav1.CreateOptions{})
+	if err != nil {
+		if authSecret != nil {
+			if errDelSec := r.kinteract.DeleteSecret(ctx, logger, repo.GetNamespace(), authSecret.GetName()); errDelSec != nil {
+				logger.Errorf("removing auto created secret: %s in namespace %s has failed: %v", authSecret.GetName(), repo.GetNamespace(), errDelSec)
+			}
+		}
+		if errors.IsAlreadyExists(err) {
+			return r.run.Clients.Tekton.TektonV1().PipelineRuns(retryPR.GetNamespace()).Get(ctx, retryPR.GetName(), metav1.GetOptions{})
+		}
+		return nil, err
+	}
+	if authSecret != nil {
+		if err := r.kinteract.UpdateSecretWithOwnerRef(ctx, logger, created.GetNamespace(), authSecret.GetName(), created); err != nil {
+			logger.Errorf("cannot update the ownerRef of secret %s: %v", authSecret.GetName(), err)
+		}
+	}
+	return created, nil
+}
+
+// retryPipelineRun creates the next attempt of a failed PipelineRun which has
+// to be retried, and reports it on the Git provider in place of the failure.
+// The retry is queued like the other PipelineRuns of the Repository when a
+// concurrency limit applies. It returns nil when the PipelineRun is not
+// retried. The retry has a name derived from the failed PipelineRun, when it
+// already exists it is not created again and it is only reported if that
+// hasn't been done yet.
+func (r *Reconciler) retryPipelineRun(ctx context.Context, logger *zap.SugaredLogger, pacInfo *info.PacOpts, repo *v1alpha1.Repository, vcx provider.Interface, event *info.Event, pr *tektonv1.PipelineRun) (*tektonv1.PipelineRun, error) {
+	if !r.shouldRetry(ctx, logger, repo, pr) {
+		return nil, nil
+	}
+
+	retryPR := newRetryPipelineRun(pr)
+	queued := (repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0) || retryPR.GetAnnotations()[keys.ConcurrencyGroup] != "" ||
+		r.concurrencyQuotas().Enabled(repo.GetNamespace())
+	if queued {
+		retryPR.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+	}
+
+	created, err := r.run.Clients.Tekton.TektonV1().PipelineRuns(pr.GetNamespace()).Get(ctx, retryPR.GetName(), metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if created.GetLabels()[keys.State] != "" {
+			logger.Infof("pipelineRun %s has already been retried by %s", pr.GetName(), created.GetName())
+			return created, nil
+		}
+	case errors.IsNotFound(err):
+		if created, err = r.createRetryPipelineRun(ctx, logger, pacInfo, repo, event, retryPR); err != nil {
+			return nil, fmt.Errorf("cannot create the retry of pipelinerun %s: %w", pr.GetName(), err)
+		}
+	default:
+		return nil, fmt.Errorf("cannot get the retry of pipelinerun %s: %w", pr.GetName(), err)
+	}
+
+	attempt, attempts := retryAttempt(created), maxRetries(logger, pr)+1
+	reason := pr.Status.GetCondition(apis.ConditionSucceeded).Reason
+	msg := fmt.Sprintf("PipelineRun %s has failed with the reason %s and is retried by %s, attempt %d of %d",
+		pr.GetName(), reason, created.GetName(), attempt, attempts)
+	logger.Info(msg)
+	r.eventEmitter.EmitMessage(repo, zap.InfoLevel, "PipelineRunRetried", msg)
+
+	consoleURL := r.run.Clients.ConsoleUI().DetailURL(created)
+	mt := formatting.MessageTemplate{
+		PipelineRunName: created.GetName(),
+		Namespace:       created.GetNamespace(),
+		ConsoleName:     r.run.Clients.ConsoleUI().GetName(),
+		ConsoleURL:      consoleURL,
+		TknBinary:       settings.TknBinaryName,
+		TknBinaryURL:    settings.TknBinaryURL,
+	}
+	status := provider.StatusOpts{
+		Status:                  "in_progress",
+		Conclusion:              "pending",
+		DetailsURL:              consoleURL,
+		PipelineRunName:         created.GetName(),
+		PipelineRun:             created,
+		OriginalPipelineRunName: created.GetAnnotations()[keys.OriginalPRName],
+	}
+	templateType := provider.StartingPipelineType
+	state := kubeinteraction.StateStarted
+	patchAnnotations := map[string]string{keys.LogURL: consoleURL}
+	if queued {
+		status.Status = "queued"
+		templateType = provider.QueueingPipelineType
+		state = kubeinteraction.StateQueued
+		patchAnnotations[keys.ExecutionOrder] = created.GetNamespace() + "/" + created.GetName()
+	} else {
+		patchAnnotations[keys.SCMReportingPLRStarted] = "true"
+	}
+	patchAnnotations[keys.State] = state
+	tmpl, err := mt.MakeTemplate(vcx.GetTemplate(templateType))
+	if err != nil {
+		return created, fmt.Errorf("cannot create message template: %w", err)
+	}
+	status.Text = fmt.Sprintf("PipelineRun <b>%s</b> has failed with the reason <b>%s</b>, this is the attempt %d of %d.\n\n%s",
+		pr.GetName(), reason, attempt, attempts, tmpl)
+	if err := createStatusWithRetry(ctx, logger, vcx, event, status); err != nil {
+		// the retry is reported again when it starts or completes
+		logger.Errorf("failed to report the retry of pipelineRun %s: %v", pr.GetName(), err)
+	}
+
+	mergePatch := map[string]any{
+		"metadata": map[string]any{
+			"labels":      map[string]string{keys.State: state},
+			"annotations": patchAnnotations,
+		},
+	}
+	patched, err := action.PatchPipelineRun(ctx, logger, "retry state", r.run.Clients.Tekton, created, mergePatch)
+	if err != nil {
+		return created, fmt.Errorf("cannot patch the retry of pipelinerun %s: %w", pr.GetName(), err)
+	}
+	return patched, nil
+}
@@ -0,0 +1,364 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/consoleui"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/events"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/metrics"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/clients"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/info"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	ghprovider "github.com/openshift-pipelines/pipelines-as-code/pkg/provider/github"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	ghtesthelper "github.com/openshift-pipelines/pipelines-as-code/pkg/test/github"
+	tektontest "github.com/openshift-pipelines/pipelines-as-code/pkg/test/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	knativeapi "knative.dev/pkg/apis"
+	knativeduckv1 "knative.dev/pkg/apis/duck/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func newRetryTestPR(status string, annotations map[string]string) *tektonv1.PipelineRun {
+	pr := tektontest.MakePRCompletion(clockwork.NewFakeClock(), "pr-abcde", "ns", status, map[string]string{
+		keys.Repository:     "repo",
+		keys.OriginalPRName: "pr",
+		keys.State:          kubeinteraction.StateStarted,
+	}, map[string]string{keys.Repository: "repo", keys.State: kubeinteraction.StateStarted}, 10)
+	for key, value := range annotations {
+		pr.Annotations[key] = value
+	}
+	pr.GenerateName = "pr-"
+	pr.Status.ChildReferences = []tektonv1.ChildStatusReference{
+		{TypeMeta: runtime.TypeMeta{Kind: "TaskRun"}, Name: "pr-abcde-build", PipelineTaskName: "build"},
+		{TypeMeta: runtime.TypeMeta{Kind: "TaskRun"}, Name: "pr-abcde-integration", PipelineTaskName: "integration"},
+	}
+	return pr
+}
+
+func newRetryTestTaskRuns() []*tektonv1.TaskRun {
+	clock := clockwork.NewFakeClock()
+	condition := func(status corev1.ConditionStatus, reason string) knativeduckv1.Conditions {
+		return knativeduckv1.Conditions{{Type: knativeapi.ConditionSucceeded, Status: status, Reason: reason}}
+	}
+	return []*tektonv1.TaskRun{
+		tektontest.MakeTaskRunCompletion(clock, "pr-abcde-build", "ns", "pr-abcde", map[string]string{},
+			tektonv1.TaskRunStatusFields{}, condition(corev1.ConditionTrue, "Succeeded"), 10),
+		tektontest.MakeTaskRunCompletion(clock, "pr-abcde-integration", "ns", "pr-abcde", map[string]string{},
+			tektonv1.TaskRunStatusFields{}, condition(corev1.ConditionFalse, "Failed"), 10),
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		annotations map[string]string
+		cancelled   bool
+		retry       bool
+	}{
+		{
+			name:   "no max-retries",
+			status: string(tektonv1.PipelineRunReasonFailed),
+		},
+		{
+			name:        "succeeded",
+			annotations: map[string]string{keys.MaxRetries: "2"},
+		},
+		{
+			name:        "failed",
+			status:      string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{keys.MaxRetries: "2"},
+			retry:       true,
+		},
+		{
+			name:        "invalid max-retries",
+			status:      string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{keys.MaxRetries: "twice"},
+		},
+		{
+			name:        "cancelled",
+			status:      string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{keys.MaxRetries: "2"},
+			cancelled:   true,
+		},
+		{
+			name:        "last retry",
+			status:      string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{keys.MaxRetries: "2", keys.RetryAttempt: "2"},
+			retry:       true,
+		},
+		{
+			name:        "no retry left",
+			status:      string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{keys.MaxRetries: "2", keys.RetryAttempt: "3"},
+		},
+		{
+			name:   "matching failed task",
+			status: string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{
+				keys.MaxRetries:         "2",
+				keys.RetryCELExpression: `reason == "Failed" && "integration" in failed_tasks`,
+			},
+			retry: true,
+		},
+		{
+			name:   "not matching failed task",
+			status: string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{
+				keys.MaxRetries:         "2",
+				keys.RetryCELExpression: `failed_tasks == ["build"]`,
+			},
+		},
+		{
+			name:   "invalid expression",
+			status: string(tektonv1.PipelineRunReasonFailed),
+			annotations: map[string]string{
+				keys.MaxRetries:         "2",
+				keys.RetryCELExpression: `failed_tasks`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			pr := newRetryTestPR(tt.status, tt.annotations)
+			if tt.cancelled {
+				pr.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+			}
+			stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{TaskRuns: newRetryTestTaskRuns()})
+			r := &Reconciler{
+				run: &params.Run{
+					Clients: clients.Clients{Tekton: stdata.Pipeline, Log: logger},
+				},
+				eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+			}
+			repo := &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"}}
+			assert.Equal(t, r.shouldRetry(ctx, logger, repo, pr), tt.retry)
+		})
+	}
+}
+
+func TestNewRetryPipelineRun(t *testing.T) {
+	pr := newRetryTestPR(string(tektonv1.PipelineRunReasonFailed), map[string]string{
+		keys.MaxRetries:             "2",
+		keys.CheckRunID:             "123",
+		keys.LogURL:                 "https://console/pr-abcde",
+		keys.SCMReportingPLRStarted: "true",
+		keys.ExecutionOrder:         "ns/pr-abcde",
+		"results.tekton.dev/record": "ns/results/uid/records/uid",
+	})
+	pr.Spec.Status = tektonv1.PipelineRunSpecStatusPending
+
+	retryPR := newRetryPipelineRun(pr)
+	assert.Equal(t, retryPR.GetName(), "pr-abcde-retry-2")
+	assert.Equal(t, retryPR.Spec.Status, tektonv1.PipelineRunSpecStatus(""))
+	assert.DeepEqual(t, retryPR.GetAnnotations(), map[string]string{
+		keys.Repository:     "repo",
+		keys.OriginalPRName: "pr",
+		keys.MaxRetries:     "2",
+		keys.CheckRunID:     "123",
+		keys.RetryAttempt:   "2",
+	})
+	assert.DeepEqual(t, retryPR.GetLabels(), map[string]string{keys.Repository: "repo"})
+	assert.Equal(t, newRetryPipelineRun(retryPR).GetAnnotations()[keys.RetryAttempt], "3")
+	// the attempt number of a retry is replaced
+	assert.Equal(t, newRetryPipelineRun(retryPR).GetName(), "pr-abcde-retry-3")
+}
+
+func TestRenameGitAuthSecret(t *testing.T) {
+	pr := newRetryTestPR(string(tektonv1.PipelineRunReasonFailed), map[string]string{keys.GitAuthSecret: "pac-gitauth-old"})
+	pr.Spec.Workspaces = []tektonv1.WorkspaceBinding{
+		{Name: "basic-auth", Secret: &corev1.SecretVolumeSource{SecretName: "pac-gitauth-old"}},
+		{Name: "source", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+	pr.Spec.Params = tektonv1.Params{
+		{Name: "secret", Value: *tektonv1.NewStructuredValues("pac-gitauth-old-key")},
+		{Name: "name", Value: *tektonv1.NewStructuredValues("pac-gitauth-old")},
+	}
+	secretEnv := corev1.EnvVar{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "pac-gitauth-old"}, Key: "git-provider-token"},
+	}}
+	pr.Spec.PipelineSpec = &tektonv1.PipelineSpec{
+		Tasks: []tektonv1.PipelineTask{{Name: "clone", TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{
+			Steps: []tektonv1.Step{{Name: "clone", Env: []corev1.EnvVar{secretEnv}, Script: "echo pac-gitauth-old"}},
+			Volumes: []corev1.Volume{{Name: "auth", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: "pac-gitauth-old"},
+			}}},
+		}}}},
+		Finally: []tektonv1.PipelineTask{{Name: "notify", TaskSpec: &tektonv1.EmbeddedTask{TaskSpec: tektonv1.TaskSpec{
+			Steps: []tektonv1.Step{{Name: "notify", EnvFrom: []corev1.EnvFromSource{
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "pac-gitauth-old"}}},
+			}}},
+		}}}},
+	}
+
+	renameGitAuthSecret(pr, "pac-gitauth-new")
+	assert.Equal(t, pr.GetAnnotations()[keys.GitAuthSecret], "pac-gitauth-new")
+	assert.Equal(t, pr.Spec.Workspaces[0].Secret.SecretName, "pac-gitauth-new")
+	assert.Assert(t, pr.Spec.Workspaces[1].EmptyDir != nil)
+	clone := pr.Spec.PipelineSpec.Tasks[0].TaskSpec
+	assert.Equal(t, clone.Steps[0].Env[0].ValueFrom.SecretKeyRef.Name, "pac-gitauth-new")
+	assert.Equal(t, clone.Volumes[0].Secret.SecretName, "pac-gitauth-new")
+	assert.Equal(t, pr.Spec.PipelineSpec.Finally[0].TaskSpec.Steps[0].EnvFrom[0].SecretRef.Name, "pac-gitauth-new")
+	// the values which are not a reference to the secret are left as they are
+	assert.Equal(t, pr.Spec.Params[0].Value.StringVal, "pac-gitauth-old-key")
+	assert.Equal(t, pr.Spec.Params[1].Value.StringVal, "pac-gitauth-old")
+	assert.Equal(t, clone.Steps[0].Script, "echo pac-gitauth-old")
+}
+
+func TestReportFinalStatusRetry(t *testing.T) {
+	tests := []struct {
+		name             string
+		concurrencyLimit *int
+		// existingRetry is the state label of a retry created by an earlier
+		// reconcile, nil when there is none
+		existingRetry *string
+		retryStatus   string
+		retryState    string
+	}{
+		{
+			name:        "started retry",
+			retryStatus: "in_progress",
+			retryState:  kubeinteraction.StateStarted,
+		},
+		{
+			name:             "queued retry",
+			concurrencyLimit: github.Ptr(1),
+			retryStatus:      "queued",
+			retryState:       kubeinteraction.StateQueued,
+		},
+		{
+			name:          "retry created but not reported",
+			existingRetry: github.Ptr(""),
+			retryStatus:   "in_progress",
+			retryState:    kubeinteraction.StateStarted,
+		},
+		{
+			name:          "retry already reported",
+			existingRetry: github.Ptr(kubeinteraction.StateStarted),
+			retryState:    kubeinteraction.StateStarted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := rtesting.SetupFakeContext(t)
+			ctx = info.StoreCurrentControllerName(ctx, "default")
+			observer, _ := zapobserver.New(zap.InfoLevel)
+			logger := zap.New(observer).Sugar()
+			fakeclient, mux, _, teardown := ghtesthelper.SetupGH()
+			defer teardown()
+			vcx := &ghprovider.Provider{Token: github.Ptr("None")}
+			vcx.SetGithubClient(fakeclient)
+
+			pr := newRetryTestPR(string(tektonv1.PipelineRunReasonFailed), map[string]string{
+				keys.MaxRetries:     "2",
+				keys.InstallationID: "1234",
+				keys.CheckRunID:     "6566930543",
+				keys.RepoURL:        randomURL,
+				keys.URLOrg:         "random",
+				keys.URLRepository:  "app",
+			})
+			repo := &v1alpha1.Repository{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo", Namespace: "ns"},
+				Spec:       v1alpha1.RepositorySpec{URL: randomURL, ConcurrencyLimit: tt.concurrencyLimit},
+			}
+
+			checkRunStatuses := []string{}
+			mux.HandleFunc("/repos/random/app/check-runs/6566930543", func(_ http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				checkRun := github.UpdateCheckRunOptions{}
+				assert.NilError(t, json.Unmarshal(body, &checkRun))
+				checkRunStatuses = append(checkRunStatuses, checkRun.GetStatus())
+			})
+
+			prs := []*tektonv1.PipelineRun{pr}
+			if tt.existingRetry != nil {
+				existing := newRetryPipelineRun(pr)
+				if *tt.existingRetry != "" {
+					existing.Labels[keys.State] = *tt.existingRetry
+					existing.Annotations[keys.State] = *tt.existingRetry
+				}
+				prs = append(prs, existing)
+			}
+			stdata, informers := testclient.SeedTestData(t, ctx, testclient.Data{
+				Repositories: []*v1alpha1.Repository{repo},
+				PipelineRuns: prs,
+				TaskRuns:     newRetryTestTaskRuns(),
+			})
+			metrics, err := metrics.NewRecorder()
+			assert.NilError(t, err)
+			r := Reconciler{
+				repoLister: informers.Repository.Lister(),
+				qm:         sync.NewQueueManager(logger),
+				run: &params.Run{
+					Clients: clients.Clients{
+						PipelineAsCode: stdata.PipelineAsCode,
+						Tekton:         stdata.Pipeline,
+						Kube:           stdata.Kube,
+					},
+					Info: info.Info{
+						Kube:       &info.KubeOpts{},
+						Controller: &info.ControllerInfo{},
+					},
+				},
+				kinteract:    &kubeinteraction.Interaction{Run: &params.Run{Clients: clients.Clients{Kube: stdata.Kube, Tekton: stdata.Pipeline}}},
+				eventEmitter: events.NewEventEmitter(stdata.Kube, logger),
+				metrics:      metrics,
+			}
+			r.run.Clients.SetConsoleUI(consoleui.FallBackConsole{})
+			pacInfo := &info.PacOpts{Settings: settings.Settings{}}
+			vcx.SetPacInfo(pacInfo)
+
+			_, err = r.reportFinalStatus(ctx, logger, pacInfo, buildEventFromPipelineRun(pr), pr, vcx)
+			assert.NilError(t, err)
+
+			// the check run is kept open for the retry instead of reporting the failure
+			// an already reported retry is not reported again
+			wantStatuses := []string{}
+			if tt.retryStatus != "" {
+				wantStatuses = append(wantStatuses, tt.retryStatus)
+			}
+			assert.DeepEqual(t, checkRunStatuses, wantStatuses)
+
+			// the retry is only created once
+			list, err := stdata.Pipeline.TektonV1().PipelineRuns("ns").List(ctx, metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(list.Items), 2)
+			for _, got := range list.Items {
+				if got.GetName() == pr.GetName() {
+					assert.Equal(t, got.GetAnnotations()[keys.State], kubeinteraction.StateCompleted)
+					continue
+				}
+				assert.Equal(t, got.GetAnnotations()[keys.RetryAttempt], "2")
+				assert.Equal(t, got.GetAnnotations()[keys.CheckRunID], "6566930543")
+				assert.Equal(t, got.GetAnnotations()[keys.State], tt.retryState)
+				assert.Equal(t, got.GetLabels()[keys.State], tt.retryState)
+				assert.Equal(t, got.Spec.Status == tektonv1.PipelineRunSpecStatusPending, tt.concurrencyLimit != nil)
+			}
+
+			gotRepo, err := stdata.PipelineAsCode.PipelinesascodeV1alpha1().Repositories("ns").Get(ctx, "repo", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(gotRepo.Status), 1)
+			assert.Equal(t, gotRepo.Status[0].Attempt, 1)
+		})
+	}
+}
@@ -47,6 +47,9 @@ func (r *Reconciler) updateRepoRunStatus(ctx context.Context, logger *zap.Sugare
 		EventType:       &event.EventType,
 		TargetBranch:    &refsanitized,
 	}
+	if _, ok := pr.GetAnnotations()[apipac.MaxRetries]; ok {
+		repoStatus.Attempt = retryAttempt(pr)
+	}
 
 	// Get repository again in case it was updated while we were running the CI
 	// we try multiple time until we get right in case of conflicts.
@@ -12,9 +12,13 @@ type Semaphore interface {
 	resize(int) bool
 	addToQueue(string, time.Time) bool
 	addToPendingQueue(string, time.Time) bool
+	enqueue(string, time.Time, int64) bool
 	removeFromQueue(string)
 	getName() string
 	getLimit() int
 	getCurrentRunning() []string
 	getCurrentPending() []string
+	getState() *QueueState
+	restore(running, pending []QueueItem)
+	promote(string) bool
 }
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// DefaultGroupLimit is the number of PipelineRuns of a concurrency group
+// allowed to run at the same time when the group doesn't set a limit.
+const DefaultGroupLimit = 1
+
+// Group is a named concurrency group of a Repository. The PipelineRuns sharing
+// the same group have their own queue limited by the group limit instead of
+// the concurrency_limit of the Repository, the zero value is the queue of the
+// Repository itself.
+type Group struct {
+	Name  string
+	Limit int
+}
+
+// PipelineRunGroup returns the concurrency group of a PipelineRun as set by
+// the concurrency-group annotation, a missing or invalid limit is the default
+// one.
+func PipelineRunGroup(pr *tektonv1.PipelineRun) Group {
+	name := strings.TrimSpace(pr.GetAnnotations()[keys.ConcurrencyGroup])
+	if name == "" {
+		return Group{}
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(pr.GetAnnotations()[keys.ConcurrencyGroupLimit]))
+	if err != nil || limit < 1 {
+		limit = DefaultGroupLimit
+	}
+	return Group{Name: name, Limit: limit}
+}
+
+// QueueKey returns the key of the queue of a concurrency group of a
+// Repository, the group name comes after the repository key since a
+// Repository name can't have a slash.
+func QueueKey(repo *v1alpha1.Repository, group Group) string {
+	if group.Name == "" {
+		return RepoKey(repo)
+	}
+	return RepoKey(repo) + "/" + group.Name
+}
+
+// splitQueueKey returns the repository key and the group name of a queue key.
+func splitQueueKey(queueKey string) (string, string) {
+	parts := strings.SplitN(queueKey, "/", 3)
+	if len(parts) < 3 {
+		return queueKey, ""
+	}
+	return parts[0] + "/" + parts[1], parts[2]
+}
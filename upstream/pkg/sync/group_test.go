@@ -0,0 +1,156 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestPipelineRunGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        Group
+	}{
+		{
+			name: "no group",
+			want: Group{},
+		},
+		{
+			name:        "limit without group",
+			annotations: map[string]string{keys.ConcurrencyGroupLimit: "2"},
+			want:        Group{},
+		},
+		{
+			name:        "default limit",
+			annotations: map[string]string{keys.ConcurrencyGroup: " deploy-main "},
+			want:        Group{Name: "deploy-main", Limit: DefaultGroupLimit},
+		},
+		{
+			name:        "limit",
+			annotations: map[string]string{keys.ConcurrencyGroup: "deploy-main", keys.ConcurrencyGroupLimit: "3"},
+			want:        Group{Name: "deploy-main", Limit: 3},
+		},
+		{
+			name:        "invalid limit",
+			annotations: map[string]string{keys.ConcurrencyGroup: "deploy-main", keys.ConcurrencyGroupLimit: "0"},
+			want:        Group{Name: "deploy-main", Limit: DefaultGroupLimit},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newTestPR("first", time.Now(), nil, tt.annotations, tektonv1.PipelineRunSpec{})
+			assert.Equal(t, PipelineRunGroup(pr), tt.want)
+		})
+	}
+}
+
+func TestQueueKey(t *testing.T) {
+	repo := newTestRepo(1)
+	assert.Equal(t, QueueKey(repo, Group{}), "test-ns/test")
+	assert.Equal(t, QueueKey(repo, Group{Name: "deploy/release-1.0", Limit: 1}), "test-ns/test/deploy/release-1.0")
+
+	repoKey, group := splitQueueKey("test-ns/test/deploy/release-1.0")
+	assert.Equal(t, repoKey, "test-ns/test")
+	assert.Equal(t, group, "deploy/release-1.0")
+	repoKey, group = splitQueueKey("test-ns/test")
+	assert.Equal(t, repoKey, "test-ns/test")
+	assert.Equal(t, group, "")
+}
+
+func TestQueueManager_ConcurrencyGroups(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	store := NewConfigMapQueueStore(stdata.Kube, "pac")
+
+	// the groups are serialised even without a concurrency limit on the repository
+	repo := newTestRepo(1)
+	repo.Spec.ConcurrencyLimit = nil
+	qm := NewPersistentQueueManager(logger, store, nil, nil)
+
+	mainGroup := Group{Name: "deploy-main", Limit: 1}
+	releaseGroup := Group{Name: "deploy-release", Limit: 1}
+	groupPR := func(name string, group Group, d time.Duration) *tektonv1.PipelineRun {
+		return newTestPR(name, cw.Now().Add(d), nil, map[string]string{keys.ConcurrencyGroup: group.Name}, tektonv1.PipelineRunSpec{})
+	}
+	mainFirst := groupPR("main-first", mainGroup, 0)
+	mainSecond := groupPR("main-second", mainGroup, time.Second)
+	releaseFirst := groupPR("release-first", releaseGroup, 2*time.Second)
+
+	started, err := qm.AddListToRunningQueue(repo, mainGroup, []string{PrKey(mainFirst), PrKey(mainSecond)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(mainFirst)})
+	// another group runs freely
+	started, err = qm.AddListToRunningQueue(repo, releaseGroup, []string{PrKey(releaseFirst)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(releaseFirst)})
+
+	// every group has its own persisted queue
+	state, err := store.Load(ctx, QueueKey(repo, mainGroup))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, state.Pending[0].Key, PrKey(mainSecond))
+	state, err = store.Load(ctx, QueueKey(repo, releaseGroup))
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 1)
+
+	// releasing a group only starts the next one of the same group
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, releaseFirst), "")
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, mainFirst), PrKey(mainSecond))
+
+	// a bigger limit of the group resizes its queue
+	mainThird := groupPR("main-third", mainGroup, 3*time.Second)
+	started, err = qm.AddListToRunningQueue(repo, Group{Name: mainGroup.Name, Limit: 2}, []string{PrKey(mainThird)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(mainThird)})
+
+	qm.RemoveRepository(repo)
+	assert.Equal(t, len(qm.queueMap), 0)
+	state, err = store.Load(ctx, QueueKey(repo, mainGroup))
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+}
+
+func TestQueueManager_InitQueuesWithGroups(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+
+	pendingSpec := tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending}
+	repo := newTestRepo(1)
+	repo.Spec.ConcurrencyLimit = nil
+
+	groupAnnotations := func(order string) map[string]string {
+		return map[string]string{keys.ConcurrencyGroup: "deploy-main", keys.ExecutionOrder: order}
+	}
+	order := "test-ns/first,test-ns/second"
+	firstPR := newTestPR("first", cw.Now(), map[string]string{keys.State: kubeinteraction.StateStarted}, groupAnnotations(order), tektonv1.PipelineRunSpec{})
+	firstPR.Annotations[keys.State] = kubeinteraction.StateStarted
+	secondPR := newTestPR("second", cw.Now().Add(time.Second), map[string]string{keys.State: kubeinteraction.StateQueued}, groupAnnotations(order), pendingSpec)
+	secondPR.Annotations[keys.State] = kubeinteraction.StateQueued
+
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*tektonv1.PipelineRun{firstPR, secondPR},
+	})
+	qm := NewQueueManager(logger)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+
+	// the repository has no limit, only the queue of the group is rebuilt
+	_, found := qm.queueMap[RepoKey(repo)]
+	assert.Assert(t, !found)
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, firstPR), PrKey(secondPR))
+}
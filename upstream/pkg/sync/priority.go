@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+)
+
+// MaxPriority bounds the priority of a PipelineRun so the boost it gives in
+// the queue stays in range.
+const MaxPriority = 1000
+
+// PriorityFunc returns the priority of a queued PipelineRun from its key and
+// how long it has to wait in the queue to gain one level of priority.
+// It is never called with the lock of the queue manager held, so it can call
+// the API.
+type PriorityFunc func(prKey string) (int, time.Duration)
+
+// PipelineRunPriority returns the priority set on a PipelineRun with the
+// priority annotation, a missing or invalid one is the default priority 0.
+func PipelineRunPriority(pr *tektonv1.PipelineRun) int {
+	value, ok := pr.GetAnnotations()[keys.Priority]
+	if !ok {
+		return 0
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return max(-MaxPriority, min(priority, MaxPriority))
+}
+
+// queuePriority returns the position of a PipelineRun in the queue, the lowest
+// one is started first. Gaining one level of priority for every aging period
+// spent in the queue is the same as having been queued one aging period
+// earlier per level, so the order never has to be recomputed.
+func queuePriority(enqueued time.Time, priority int, aging time.Duration) int64 {
+	return enqueued.UnixNano() - int64(priority)*int64(aging)
+}
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"container/heap"
+	"time"
 )
 
 type (
@@ -11,6 +12,7 @@ type (
 type item struct {
 	key      string
 	priority int64
+	enqueued time.Time
 	index    int
 }
 
@@ -27,10 +29,16 @@ func (pq *priorityQueue) isPending(key key) bool {
 }
 
 func (pq *priorityQueue) add(key key, priority int64) {
-	if _, ok := pq.itemByKey[key]; ok {
+	pq.addItem(&item{key: key, priority: priority})
+}
+
+// addItem pushes an item keeping its priority and enqueue time, it is a no-op
+// if the key is already pending.
+func (pq *priorityQueue) addItem(it *item) {
+	if _, ok := pq.itemByKey[it.key]; ok {
 		return
 	}
-	heap.Push(pq, &item{key: key, priority: priority})
+	heap.Push(pq, it)
 }
 
 func (pq *priorityQueue) remove(key key) {
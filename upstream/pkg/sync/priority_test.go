@@ -0,0 +1,113 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+)
+
+func TestPipelineRunPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority string
+		want     int
+	}{
+		{name: "no annotation", want: 0},
+		{name: "positive", priority: "10", want: 10},
+		{name: "negative with spaces", priority: " -3 ", want: -3},
+		{name: "invalid", priority: "high", want: 0},
+		{name: "too high", priority: "100000", want: MaxPriority},
+		{name: "too low", priority: "-100000", want: -MaxPriority},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.priority != "" {
+				annotations[keys.Priority] = tt.priority
+			}
+			pr := newTestPR("first", time.Now(), nil, annotations, tektonv1.PipelineRunSpec{})
+			assert.Equal(t, PipelineRunPriority(pr), tt.want)
+		})
+	}
+}
+
+func TestQueuePriorityAging(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	aging := 5 * time.Minute
+	sema := newSemaphore("test", 1)
+	assert.Assert(t, sema.acquire("running"))
+
+	// a pull request queued 20 minutes ago with the default priority
+	old := cw.Now().Add(-20 * time.Minute)
+	assert.Assert(t, sema.enqueue("pull-request", old, queuePriority(old, 0, aging)))
+	// a push queued now with a priority of 3 is 15 minutes ahead, not enough
+	// to go before the pull request
+	assert.Assert(t, sema.enqueue("push", cw.Now(), queuePriority(cw.Now(), 3, aging)))
+	// a release with a priority of 10 goes first
+	assert.Assert(t, sema.enqueue("release", cw.Now(), queuePriority(cw.Now(), 10, aging)))
+
+	assert.Assert(t, sema.release("running"))
+	assert.Equal(t, sema.acquireLatest(), "release")
+	assert.Assert(t, sema.release("release"))
+	assert.Equal(t, sema.acquireLatest(), "pull-request")
+	assert.Assert(t, sema.release("pull-request"))
+	assert.Equal(t, sema.acquireLatest(), "push")
+}
+
+func TestQueueManagerPriority(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	priorities := map[string]int{"test-ns/release": 10}
+	var qm *QueueManager
+	qm = NewPersistentQueueManager(logger, nil, func(prKey string) (int, time.Duration) {
+		// the priority function may call the API, it must not block the queues
+		if !qm.lock.TryLock() {
+			t.Errorf("the priority of %s is computed with the queue manager lock held", prKey)
+		} else {
+			qm.lock.Unlock()
+		}
+		return priorities[prKey], time.Hour
+	}, nil)
+	repo := newTestRepo(1)
+
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{"test-ns/first", "test-ns/second"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"test-ns/first"})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{"test-ns/release"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(started), 0)
+	running, pending := qm.QueueStatus(RepoKey(repo))
+	assert.Equal(t, running, 1)
+	assert.DeepEqual(t, pending, []string{"test-ns/release", "test-ns/second"})
+
+	// the release jumps ahead of the second pipelinerun queued before it
+	first := newTestPR("first", time.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, first), "test-ns/release")
+}
+
+func TestQueueManagerPromote(t *testing.T) {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	qm := NewPersistentQueueManager(logger, nil, nil, nil)
+	repo := newTestRepo(1)
+
+	_, err := qm.AddListToRunningQueue(repo, Group{}, []string{"test-ns/first", "test-ns/second", "test-ns/third"})
+	assert.NilError(t, err)
+
+	assert.Assert(t, qm.PromoteInQueue(RepoKey(repo), "test-ns/third"))
+	_, pending := qm.QueueStatus(RepoKey(repo))
+	assert.DeepEqual(t, pending, []string{"test-ns/third", "test-ns/second"})
+	// the running one and the unknown ones cannot be promoted
+	assert.Assert(t, !qm.PromoteInQueue(RepoKey(repo), "test-ns/first"))
+	assert.Assert(t, !qm.PromoteInQueue(RepoKey(repo), "test-ns/unknown"))
+	assert.Assert(t, !qm.PromoteInQueue("test-ns/unknown", "test-ns/second"))
+
+	first := newTestPR("first", time.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, first), "test-ns/third")
+}
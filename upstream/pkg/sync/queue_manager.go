@@ -27,42 +27,184 @@ type QueueManager struct {
 	queueMap map[string]Semaphore
 	lock     *sync.Mutex
 	logger   *zap.SugaredLogger
+	store    QueueStore
+	priority PriorityFunc
+	quotas   QuotaFunc
+
+	// the states of the queues waiting to be written to the store, a nil
+	// state deletes it, and the queues being written. They are protected by
+	// writeLock, not by lock, so the store is called without holding lock.
+	writeLock *sync.Mutex
+	writes    map[string]*QueueState
+	writing   map[string]bool
 }
 
 func NewQueueManager(logger *zap.SugaredLogger) *QueueManager {
 	return &QueueManager{
-		queueMap: make(map[string]Semaphore),
-		lock:     &sync.Mutex{},
-		logger:   logger,
+		queueMap:  make(map[string]Semaphore),
+		lock:      &sync.Mutex{},
+		logger:    logger,
+		writeLock: &sync.Mutex{},
+		writes:    make(map[string]*QueueState),
+		writing:   make(map[string]bool),
 	}
 }
 
-// getSemaphore returns existing semaphore created for repository or create
-// a new one with limit provided in repository
+// NewPersistentQueueManager returns a QueueManager saving the state of the
+// queues in the store after every change, InitQueues restores it from there so
+// the order and the priorities of the PipelineRuns survive a restart. The
+// queued PipelineRuns are ordered with the priority function, or by the time
+// they have been queued when it is nil. The quota function returns the
+// cluster-wide and per-namespace concurrency quotas enforced on top of the
+// limits of the queues, nil means there is none.
+func NewPersistentQueueManager(logger *zap.SugaredLogger, store QueueStore, priority PriorityFunc, quotas QuotaFunc) *QueueManager {
+	qm := NewQueueManager(logger)
+	qm.store = store
+	qm.priority = priority
+	qm.quotas = quotas
+	return qm
+}
+
+// pipelineRunPriority is the priority of a PipelineRun and how long it waits
+// to gain one level of priority.
+type pipelineRunPriority struct {
+	priority int
+	aging    time.Duration
+}
+
+// priorities returns the priorities of PipelineRuns with the priority
+// function. It must be called before taking the queue manager lock since the
+// priority function may have to call the API.
+func (qm *QueueManager) priorities(prKeys []string) map[string]pipelineRunPriority {
+	ret := map[string]pipelineRunPriority{}
+	if qm.priority == nil {
+		return ret
+	}
+	for _, prKey := range prKeys {
+		priority, aging := qm.priority(prKey)
+		ret[prKey] = pipelineRunPriority{priority: priority, aging: aging}
+	}
+	return ret
+}
+
+// queuePriority returns the position in the queue of a PipelineRun queued at
+// the given time from the priorities computed before taking the lock.
+func (qm *QueueManager) queuePriority(priorities map[string]pipelineRunPriority, prKey string, enqueued time.Time) int64 {
+	if qm.priority == nil {
+		return enqueued.UnixNano()
+	}
+	p := priorities[prKey]
+	return queuePriority(enqueued, p.priority, p.aging)
+}
+
+// persist records a copy of the state of a queue to be saved by flush, the
+// queue manager lock must be held. The state of a queue without any
+// PipelineRun is deleted from the store.
+func (qm *QueueManager) persist(queueKey string) {
+	if qm.store == nil {
+		return
+	}
+	var state *QueueState
+	if sema, found := qm.queueMap[queueKey]; found {
+		state = sema.getState()
+		if len(state.Running) == 0 && len(state.Pending) == 0 {
+			state = nil
+		}
+	}
+	qm.writeLock.Lock()
+	defer qm.writeLock.Unlock()
+	qm.writes[queueKey] = state
+}
+
+// flush writes the states recorded by persist to the store, it must be called
+// after releasing the queue manager lock so the reconcilers don't wait on the
+// API. A queue is written by one goroutine at a time which keeps writing its
+// latest recorded state until there is none left, the states recorded in
+// between are skipped. A failure is only logged, the in-memory queue stays
+// the source of truth until the next restart.
+func (qm *QueueManager) flush() {
+	if qm.store == nil {
+		return
+	}
+	ctx := context.Background()
+	qm.writeLock.Lock()
+	defer qm.writeLock.Unlock()
+	for {
+		queueKey, state, found := qm.nextWrite()
+		if !found {
+			return
+		}
+		qm.writing[queueKey] = true
+		qm.writeLock.Unlock()
+		qm.write(ctx, queueKey, state)
+		qm.writeLock.Lock()
+		delete(qm.writing, queueKey)
+	}
+}
+
+// nextWrite takes a recorded state of a queue nobody is writing, the write
+// lock must be held.
+func (qm *QueueManager) nextWrite() (string, *QueueState, bool) {
+	for queueKey, state := range qm.writes {
+		if qm.writing[queueKey] {
+			continue
+		}
+		delete(qm.writes, queueKey)
+		return queueKey, state, true
+	}
+	return "", nil, false
+}
+
+func (qm *QueueManager) write(ctx context.Context, queueKey string, state *QueueState) {
+	if state == nil {
+		if err := qm.store.Delete(ctx, queueKey); err != nil {
+			qm.logger.Errorf("failed to delete the state of queue (%s): %v", queueKey, err)
+		}
+		return
+	}
+	if err := qm.store.Save(ctx, queueKey, state); err != nil {
+		qm.logger.Errorf("failed to persist the state of queue (%s): %v", queueKey, err)
+	}
+}
+
+// queueLimit returns how many PipelineRuns of the queue can run at a time,
+// zero means they are not throttled.
+func queueLimit(repo *v1alpha1.Repository, group Group) int {
+	if group.Name != "" {
+		return group.Limit
+	}
+	if repo.Spec.ConcurrencyLimit != nil {
+		return *repo.Spec.ConcurrencyLimit
+	}
+	return 0
+}
+
+// getSemaphore returns existing semaphore created for the repository or the
+// concurrency group or create a new one with the limit of the queue.
 // Semaphore: nothing but a waiting and a running queue for a repository
 // with limit deciding how many should be running at a time.
-func (qm *QueueManager) getSemaphore(repo *v1alpha1.Repository) (Semaphore, error) {
-	repoKey := RepoKey(repo)
+func (qm *QueueManager) getSemaphore(repo *v1alpha1.Repository, group Group) (Semaphore, error) {
+	queueKey := QueueKey(repo, group)
+	limit := queueLimit(repo, group)
+	if limit == 0 && qm.concurrencyQuotas().Enabled(repo.GetNamespace()) {
+		// the queue isn't throttled but its namespace is, the quotas decide
+		// when its PipelineRuns can run
+		limit = unlimitedQueueLimit
+	}
 
-	if sema, found := qm.queueMap[repoKey]; found {
-		if err := qm.checkAndUpdateSemaphoreSize(repo, sema); err != nil {
+	if sema, found := qm.queueMap[queueKey]; found {
+		if err := qm.checkAndUpdateSemaphoreSize(limit, sema); err != nil {
 			return nil, err
 		}
 		return sema, nil
 	}
 
-	// create a new semaphore; can't assume callers have checked that ConcurrencyLimit is set
-	limit := 0
-	if repo.Spec.ConcurrencyLimit != nil {
-		limit = *repo.Spec.ConcurrencyLimit
-	}
-	qm.queueMap[repoKey] = newSemaphore(repoKey, limit)
+	qm.queueMap[queueKey] = newSemaphore(queueKey, limit)
 
-	return qm.queueMap[repoKey], nil
+	return qm.queueMap[queueKey], nil
 }
 
-func (qm *QueueManager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, semaphore Semaphore) error {
-	limit := *repo.Spec.ConcurrencyLimit
+func (qm *QueueManager) checkAndUpdateSemaphoreSize(limit int, semaphore Semaphore) error {
 	if limit != semaphore.getLimit() {
 		if semaphore.resize(limit) {
 			return nil
@@ -72,89 +214,144 @@ func (qm *QueueManager) checkAndUpdateSemaphoreSize(repo *v1alpha1.Repository, s
 	return nil
 }
 
-// AddListToRunningQueue adds the pipelineRun to the waiting queue of the repository
-// and if it is at the top and ready to run which means currently running pipelineRun < limit
-// then move it to running queue
+// AddListToRunningQueue adds the pipelineRun to the waiting queue of the repository,
+// or of the concurrency group when it has a name, and if it is at the top and ready
+// to run which means currently running pipelineRun < limit then move it to running queue
 // This adds the pipelineRuns in the same order as in the list.
-func (qm *QueueManager) AddListToRunningQueue(repo *v1alpha1.Repository, list []string) ([]string, error) {
+func (qm *QueueManager) AddListToRunningQueue(repo *v1alpha1.Repository, group Group, list []string) ([]string, error) {
+	priorities := qm.priorities(list)
+	defer qm.flush()
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
-	sema, err := qm.getSemaphore(repo)
+	acquiredList, err := qm.addListToRunningQueue(repo, group, list, priorities)
+	if err != nil {
+		return acquiredList, err
+	}
+	qm.persist(QueueKey(repo, group))
+	return acquiredList, nil
+}
+
+func (qm *QueueManager) addListToRunningQueue(repo *v1alpha1.Repository, group Group, list []string, priorities map[string]pipelineRunPriority) ([]string, error) {
+	sema, err := qm.getSemaphore(repo, group)
 	if err != nil {
 		return []string{}, err
 	}
 
+	queueKey := QueueKey(repo, group)
 	for _, pr := range list {
-		if sema.addToQueue(pr, time.Now()) {
-			qm.logger.Infof("added pipelineRun (%s) to running queue for repository (%s)", pr, RepoKey(repo))
+		now := time.Now()
+		if sema.enqueue(pr, now, qm.queuePriority(priorities, pr, now)) {
+			qm.logger.Infof("added pipelineRun (%s) to running queue for repository (%s)", pr, queueKey)
 		}
 	}
 
 	// it is possible something besides PAC set the PipelineRun to Pending; if concurrency limit has not
 	// been set, return all the pending PipelineRuns; also, if the limit is zero, that also means do not throttle,
 	// so we return all the PipelinesRuns, the for loop below skips that case as well
-	if repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0 {
+	quotas := qm.concurrencyQuotas()
+	limit := queueLimit(repo, group)
+	if limit == 0 && !quotas.Enabled(repo.GetNamespace()) {
 		return sema.getCurrentPending(), nil
 	}
 
 	acquiredList := []string{}
-	for i := 0; i < *repo.Spec.ConcurrencyLimit; i++ {
+	for qm.quotaAvailable(quotas, repo.GetNamespace()) {
 		acquired := sema.acquireLatest()
-		if acquired != "" {
-			qm.logger.Infof("moved (%s) to running for repository (%s)", acquired, RepoKey(repo))
-			acquiredList = append(acquiredList, acquired)
+		if acquired == "" {
+			break
 		}
+		qm.logger.Infof("moved (%s) to running for repository (%s)", acquired, queueKey)
+		acquiredList = append(acquiredList, acquired)
 	}
 
 	return acquiredList, nil
 }
 
-func (qm *QueueManager) AddToPendingQueue(repo *v1alpha1.Repository, list []string) error {
+func (qm *QueueManager) AddToPendingQueue(repo *v1alpha1.Repository, group Group, list []string) error {
+	priorities := qm.priorities(list)
+	defer qm.flush()
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
-	sema, err := qm.getSemaphore(repo)
+	if err := qm.addToPendingQueue(repo, group, list, priorities); err != nil {
+		return err
+	}
+	qm.persist(QueueKey(repo, group))
+	return nil
+}
+
+func (qm *QueueManager) addToPendingQueue(repo *v1alpha1.Repository, group Group, list []string, priorities map[string]pipelineRunPriority) error {
+	sema, err := qm.getSemaphore(repo, group)
 	if err != nil {
 		return err
 	}
 
 	for _, pr := range list {
-		if sema.addToPendingQueue(pr, time.Now()) {
-			qm.logger.Infof("added pipelineRun (%s) to pending queue for repository (%s)", pr, RepoKey(repo))
+		now := time.Now()
+		if sema.enqueue(pr, now, qm.queuePriority(priorities, pr, now)) {
+			qm.logger.Infof("added pipelineRun (%s) to pending queue for repository (%s)", pr, QueueKey(repo, group))
 		}
 	}
 	return nil
 }
 
-func (qm *QueueManager) RemoveFromQueue(repoKey, prKey string) bool {
+// RemoveFromQueue removes the PipelineRun from the queue with the given
+// QueueKey.
+func (qm *QueueManager) RemoveFromQueue(queueKey, prKey string) bool {
+	defer qm.flush()
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
-	sema, found := qm.queueMap[repoKey]
+	if !qm.removeFromQueue(queueKey, prKey) {
+		return false
+	}
+	qm.persist(queueKey)
+	return true
+}
+
+func (qm *QueueManager) removeFromQueue(queueKey, prKey string) bool {
+	sema, found := qm.queueMap[queueKey]
 	if !found {
 		return false
 	}
 
 	sema.release(prKey)
 	sema.removeFromQueue(prKey)
-	qm.logger.Infof("removed (%s) for repository (%s)", prKey, repoKey)
+	qm.logger.Infof("removed (%s) for repository (%s)", prKey, queueKey)
 	return true
 }
 
+// RemoveAndTakeItemFromQueue removes the PipelineRun from the queue of its
+// concurrency group, or of the repository, and returns the next one to start.
+// When quotas are set the next one can come from the queue of another
+// repository, see takeFairShare.
 func (qm *QueueManager) RemoveAndTakeItemFromQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun) string {
-	repoKey := RepoKey(repo)
+	defer qm.flush()
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	queueKey := QueueKey(repo, PipelineRunGroup(run))
 	prKey := PrKey(run)
-	if !qm.RemoveFromQueue(repoKey, prKey) {
+	if !qm.removeFromQueue(queueKey, prKey) {
 		return ""
 	}
-	sema, found := qm.queueMap[repoKey]
+	defer qm.persist(queueKey)
+
+	if quotas := qm.concurrencyQuotas(); quotas.Enabled(repo.GetNamespace()) {
+		nextQueueKey, next := qm.takeFairShare(quotas, repo.GetNamespace())
+		if nextQueueKey != "" && nextQueueKey != queueKey {
+			qm.persist(nextQueueKey)
+		}
+		return next
+	}
+	sema, found := qm.queueMap[queueKey]
 	if !found {
 		return ""
 	}
 
 	if next := sema.acquireLatest(); next != "" {
-		qm.logger.Infof("moved (%s) to running for repository (%s)", next, repoKey)
+		qm.logger.Infof("moved (%s) to running for repository (%s)", next, queueKey)
 		return next
 	}
 	return ""
@@ -190,7 +387,7 @@ func FilterPipelineRunByState(ctx context.Context, tekton versioned2.Interface,
 }
 
 // InitQueues rebuild all the queues for all repository if concurrency is defined before
-// reconciler started reconciling them.
+// reconciler started reconciling them, as well as the queues of the concurrency groups.
 func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interface, pac versioned.Interface) error {
 	// fetch all repos
 	repos, err := pac.PipelinesascodeV1alpha1().Repositories("").List(ctx, v1.ListOptions{})
@@ -201,11 +398,7 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 	// pipelineRuns from the namespace where repository is present
 	// those are required for creating queues
 	for _, repo := range repos.Items {
-		if repo.Spec.ConcurrencyLimit == nil || *repo.Spec.ConcurrencyLimit == 0 {
-			continue
-		}
-
-		// add all pipelineRuns in started state to pending queue
+		// fetch all pipelineRuns in started state
 		prs, err := tekton.TektonV1().PipelineRuns(repo.Namespace).
 			List(ctx, v1.ListOptions{
 				LabelSelector: fmt.Sprintf("%s=%s", keys.State, kubeinteraction.StateStarted),
@@ -213,23 +406,8 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 		if err != nil {
 			return err
 		}
-
 		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs := sortPipelineRunsByCreationTimestamp(prs.Items)
-
-		for _, pr := range sortedPRs {
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
-			}
-			orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), "", kubeinteraction.StateStarted)
-
-			_, err = qm.AddListToRunningQueue(&repo, orderedList)
-			if err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
-			}
-		}
+		allStartedPRs := sortPipelineRunsByCreationTimestamp(prs.Items)
 
 		// now fetch all queued pipelineRun
 		prs, err = tekton.TektonV1().PipelineRuns(repo.Namespace).
@@ -239,34 +417,201 @@ func (qm *QueueManager) InitQueues(ctx context.Context, tekton versioned2.Interf
 		if err != nil {
 			return err
 		}
+		allQueuedPRs := sortPipelineRunsByCreationTimestamp(prs.Items)
 
-		// sort the pipelinerun by creation time before adding to queue
-		sortedPRs = sortPipelineRunsByCreationTimestamp(prs.Items)
+		groups := pipelineRunGroups(append(append([]*tektonv1.PipelineRun{}, allStartedPRs...), allQueuedPRs...))
+		if (repo.Spec.ConcurrencyLimit != nil && *repo.Spec.ConcurrencyLimit != 0) || qm.concurrencyQuotas().Enabled(repo.Namespace) {
+			groups = append([]Group{{}}, groups...)
+		}
 
-		for _, pr := range sortedPRs {
-			order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
-			if !exist {
-				// if the pipelineRun doesn't have order label then wait
-				return nil
+		for _, group := range groups {
+			startedPRs := filterPipelineRunsByGroup(allStartedPRs, group)
+			queuedPRs := filterPipelineRunsByGroup(allQueuedPRs, group)
+			queueKey := QueueKey(&repo, group)
+
+			// restore the queue as it was before the restart if it has been persisted
+			if qm.store != nil {
+				state, err := qm.store.Load(ctx, queueKey)
+				if err != nil {
+					qm.logger.Errorf("failed to load the state of queue (%s), rebuilding it: %v", queueKey, err)
+				} else if state != nil {
+					if err := qm.restoreQueue(&repo, group, state, startedPRs, queuedPRs); err != nil {
+						qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+					}
+					continue
+				}
 			}
-			orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
-			if err := qm.AddToPendingQueue(&repo, orderedList); err != nil {
-				qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+
+			// the PipelineRuns only throttled by the quotas don't have an
+			// execution order, they are queued by creation time
+			if queueLimit(&repo, group) == 0 {
+				if err := qm.restoreQueue(&repo, group, &QueueState{}, startedPRs, queuedPRs); err != nil {
+					qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+				}
+				continue
 			}
+
+			for _, pr := range startedPRs {
+				order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+				if !exist {
+					// if the pipelineRun doesn't have order label then wait
+					return nil
+				}
+				orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), "", kubeinteraction.StateStarted)
+
+				priorities := qm.priorities(orderedList)
+				qm.lock.Lock()
+				_, err = qm.addListToRunningQueue(&repo, group, orderedList, priorities)
+				qm.lock.Unlock()
+				if err != nil {
+					qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+				}
+			}
+
+			for _, pr := range queuedPRs {
+				order, exist := pr.GetAnnotations()[keys.ExecutionOrder]
+				if !exist {
+					// if the pipelineRun doesn't have order label then wait
+					return nil
+				}
+				orderedList := FilterPipelineRunByState(ctx, tekton, strings.Split(order, ","), tektonv1.PipelineRunSpecStatusPending, kubeinteraction.StateQueued)
+				priorities := qm.priorities(orderedList)
+				qm.lock.Lock()
+				err = qm.addToPendingQueue(&repo, group, orderedList, priorities)
+				qm.lock.Unlock()
+				if err != nil {
+					qm.logger.Error("failed to init queue for repo: ", repo.GetName())
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// pipelineRunGroups returns the concurrency groups of the PipelineRuns in the
+// order they are first seen, the limit of a group is the one of its last
+// PipelineRun.
+func pipelineRunGroups(prs []*tektonv1.PipelineRun) []Group {
+	groups := []Group{}
+	index := map[string]int{}
+	for _, pr := range prs {
+		group := PipelineRunGroup(pr)
+		if group.Name == "" {
+			continue
+		}
+		if i, ok := index[group.Name]; ok {
+			groups[i] = group
+			continue
+		}
+		index[group.Name] = len(groups)
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func filterPipelineRunsByGroup(prs []*tektonv1.PipelineRun, group Group) []*tektonv1.PipelineRun {
+	filtered := []*tektonv1.PipelineRun{}
+	for _, pr := range prs {
+		if PipelineRunGroup(pr).Name == group.Name {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered
+}
+
+// restoreQueue rebuilds a queue of a repository from its persisted state.
+// The persisted PipelineRuns keep their original order and priority as long as
+// they are still started or queued, a running one which didn't get the time
+// to be started goes back to the pending queue. The PipelineRuns missing from
+// the state are added after them by creation time. Nothing is persisted here,
+// the state is saved again on the next change of the queue.
+func (qm *QueueManager) restoreQueue(repo *v1alpha1.Repository, group Group, state *QueueState, startedPRs, queuedPRs []*tektonv1.PipelineRun) error {
+	// the persisted PipelineRuns keep their priority
+	persisted := map[string]bool{}
+	for _, it := range append(append([]QueueItem{}, state.Running...), state.Pending...) {
+		persisted[it.Key] = true
+	}
+	prKeys := []string{}
+	for _, pr := range append(append([]*tektonv1.PipelineRun{}, startedPRs...), queuedPRs...) {
+		if !persisted[PrKey(pr)] {
+			prKeys = append(prKeys, PrKey(pr))
+		}
+	}
+	priorities := qm.priorities(prKeys)
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	sema, err := qm.getSemaphore(repo, group)
+	if err != nil {
+		return err
+	}
+	started := map[string]bool{}
+	for _, pr := range startedPRs {
+		started[PrKey(pr)] = true
+	}
+	queued := map[string]bool{}
+	for _, pr := range queuedPRs {
+		if pr.Spec.Status == tektonv1.PipelineRunSpecStatusPending {
+			queued[PrKey(pr)] = true
 		}
 	}
 
+	running, pending := []QueueItem{}, []QueueItem{}
+	restored := map[string]bool{}
+	for _, it := range append(append([]QueueItem{}, state.Running...), state.Pending...) {
+		switch {
+		case started[it.Key]:
+			running = append(running, it)
+		case queued[it.Key]:
+			pending = append(pending, it)
+		default:
+			continue
+		}
+		restored[it.Key] = true
+	}
+	for _, pr := range startedPRs {
+		if !restored[PrKey(pr)] {
+			running = append(running, QueueItem{Key: PrKey(pr), Priority: qm.queuePriority(priorities, PrKey(pr), pr.CreationTimestamp.Time), Enqueued: pr.CreationTimestamp.Time})
+		}
+	}
+	// keep the ones missing from the state behind the restored ones
+	last := int64(0)
+	for _, it := range pending {
+		last = max(last, it.Priority)
+	}
+	for _, pr := range queuedPRs {
+		if queued[PrKey(pr)] && !restored[PrKey(pr)] {
+			last = max(last+1, qm.queuePriority(priorities, PrKey(pr), pr.CreationTimestamp.Time))
+			pending = append(pending, QueueItem{Key: PrKey(pr), Priority: last, Enqueued: pr.CreationTimestamp.Time})
+		}
+	}
+
+	sema.restore(running, pending)
+	qm.logger.Infof("restored the queue of repository (%s) with %d running and %d pending pipelineRuns", QueueKey(repo, group), len(running), len(pending))
 	return nil
 }
 
+// RemoveRepository removes the queue of the repository and the queues of its
+// concurrency groups.
 func (qm *QueueManager) RemoveRepository(repo *v1alpha1.Repository) {
+	defer qm.flush()
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
 
 	repoKey := RepoKey(repo)
 	delete(qm.queueMap, repoKey)
+	qm.persist(repoKey)
+	for queueKey := range qm.queueMap {
+		if key, group := splitQueueKey(queueKey); key == repoKey && group != "" {
+			delete(qm.queueMap, queueKey)
+			qm.persist(queueKey)
+		}
+	}
 }
 
+// QueuedPipelineRuns returns the pending PipelineRuns of the queue of the
+// repository, the ones of the concurrency groups are not included.
 func (qm *QueueManager) QueuedPipelineRuns(repo *v1alpha1.Repository) []string {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
@@ -278,6 +623,40 @@ func (qm *QueueManager) QueuedPipelineRuns(repo *v1alpha1.Repository) []string {
 	return []string{}
 }
 
+// PromoteInQueue moves a pending PipelineRun to the front of the queue with the
+// given QueueKey, it is started next whatever its priority.
+func (qm *QueueManager) PromoteInQueue(queueKey, prKey string) bool {
+	defer qm.flush()
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	sema, ok := qm.queueMap[queueKey]
+	if !ok || !sema.promote(prKey) {
+		return false
+	}
+	qm.logger.Infof("promoted (%s) to the front of the queue for repository (%s)", prKey, queueKey)
+	qm.persist(queueKey)
+	return true
+}
+
+// QueueStatus returns the number of running PipelineRuns of the queue with the
+// given QueueKey and its pending ones in the order they are going to start.
+func (qm *QueueManager) QueueStatus(queueKey string) (int, []string) {
+	qm.lock.Lock()
+	defer qm.lock.Unlock()
+
+	sema, ok := qm.queueMap[queueKey]
+	if !ok {
+		return 0, []string{}
+	}
+	state := sema.getState()
+	pending := make([]string, 0, len(state.Pending))
+	for _, it := range state.Pending {
+		pending = append(pending, it.Key)
+	}
+	return len(state.Running), pending
+}
+
 func (qm *QueueManager) RunningPipelineRuns(repo *v1alpha1.Repository) []string {
 	qm.lock.Lock()
 	defer qm.lock.Unlock()
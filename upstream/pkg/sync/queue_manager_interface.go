@@ -15,10 +15,12 @@ type QueueManagerInterface interface {
 	RemoveRepository(repo *v1alpha1.Repository)
 	QueuedPipelineRuns(repo *v1alpha1.Repository) []string
 	RunningPipelineRuns(repo *v1alpha1.Repository) []string
-	AddListToRunningQueue(repo *v1alpha1.Repository, list []string) ([]string, error)
-	AddToPendingQueue(repo *v1alpha1.Repository, list []string) error
-	RemoveFromQueue(repoKey, prKey string) bool
+	AddListToRunningQueue(repo *v1alpha1.Repository, group Group, list []string) ([]string, error)
+	AddToPendingQueue(repo *v1alpha1.Repository, group Group, list []string) error
+	RemoveFromQueue(queueKey, prKey string) bool
 	RemoveAndTakeItemFromQueue(repo *v1alpha1.Repository, run *tektonv1.PipelineRun) string
+	QueueStatus(queueKey string) (int, []string)
+	PromoteInQueue(queueKey, prKey string) bool
 }
 
 func RepoKey(repo *v1alpha1.Repository) string {
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"testing"
@@ -50,7 +51,7 @@ func TestSomeoneElseSetPendingWithNoConcurrencyLimit(t *testing.T) {
 			Reason: v1beta1.PipelineRunReasonPending.String(),
 		},
 	}
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(pr)})
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(pr)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 }
@@ -72,7 +73,7 @@ func TestAddToPendingQueueDirectly(t *testing.T) {
 			Reason: v1beta1.PipelineRunReasonPending.String(),
 		},
 	}
-	err := qm.AddToPendingQueue(repo, []string{PrKey(pr)})
+	err := qm.AddToPendingQueue(repo, Group{}, []string{PrKey(pr)})
 	assert.NilError(t, err)
 
 	sema := qm.queueMap[RepoKey(repo)]
@@ -94,7 +95,7 @@ func TestNewQueueManagerForList(t *testing.T) {
 	prFirst := newTestPR("first", time.Now(), nil, nil, tektonv1.PipelineRunSpec{})
 
 	// added to queue, as there is only one should start
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(prFirst)})
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFirst)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 
@@ -106,7 +107,7 @@ func TestNewQueueManagerForList(t *testing.T) {
 	prSecond := newTestPR("second", time.Now().Add(1*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prThird := newTestPR("third", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prSecond), PrKey(prThird)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 1)
 	// as per the list, 2nd must be started
@@ -116,7 +117,7 @@ func TestNewQueueManagerForList(t *testing.T) {
 	prFourth := newTestPR("fourth", time.Now().Add(5*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prFifth := newTestPR("fifth", time.Now().Add(4*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFourth), PrKey(prFifth)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFourth), PrKey(prFifth)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -130,7 +131,7 @@ func TestNewQueueManagerForList(t *testing.T) {
 	prSeventh := newTestPR("seventh", time.Now().Add(5*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prEight := newTestPR("eight", time.Now().Add(4*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prSixth), PrKey(prSeventh), PrKey(prEight)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prSixth), PrKey(prSeventh), PrKey(prEight)})
 	assert.NilError(t, err)
 	// third is running, but limit is changed now, so one more should be moved to running
 	assert.Equal(t, len(started), 1)
@@ -151,18 +152,18 @@ func TestNewQueueManagerReListing(t *testing.T) {
 	prThird := newTestPR("third", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
 	// added to queue, as there is only one should start
-	started, err := qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 2)
 
 	// if first is running and other pipelineRuns are reconciling
 	// then adding again shouldn't have any effect
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
 	// again
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFirst), PrKey(prSecond), PrKey(prThird)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -176,7 +177,7 @@ func TestNewQueueManagerReListing(t *testing.T) {
 	prFifth := newTestPR("fifth", time.Now().Add(1*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 	prSixths := newTestPR("sixth", time.Now().Add(7*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
 
-	started, err = qm.AddListToRunningQueue(repo, []string{PrKey(prFourth), PrKey(prFifth), PrKey(prSixths)})
+	started, err = qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFourth), PrKey(prFifth), PrKey(prSixths)})
 	assert.NilError(t, err)
 	assert.Equal(t, len(started), 0)
 
@@ -338,3 +339,194 @@ func TestFilterPipelineRunByInProgress(t *testing.T) {
 	expected := []string{"test-ns/pr1"}
 	assert.DeepEqual(t, filtered, expected)
 }
+
+func TestQueueManager_PersistentQueues(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	store := NewConfigMapQueueStore(stdata.Kube, "pac")
+
+	repo := newTestRepo(1)
+	qm := NewPersistentQueueManager(logger, store, nil, nil)
+
+	prFirst := newTestPR("first", cw.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+	prSecond := newTestPR("second", cw.Now().Add(1*time.Second), nil, nil, tektonv1.PipelineRunSpec{})
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(prFirst), PrKey(prSecond)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(prFirst)})
+
+	state, err := store.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 1)
+	assert.Equal(t, state.Running[0].Key, PrKey(prFirst))
+	assert.Equal(t, len(state.Pending), 1)
+	assert.Equal(t, state.Pending[0].Key, PrKey(prSecond))
+
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, prFirst), PrKey(prSecond))
+	state, err = store.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 1)
+	assert.Equal(t, state.Running[0].Key, PrKey(prSecond))
+	assert.Equal(t, len(state.Pending), 0)
+
+	// the state is removed with the repository
+	qm.RemoveRepository(repo)
+	state, err = store.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+}
+
+// lockCheckingStore fails the test when the queue manager lock is held while
+// writing to the store.
+type lockCheckingStore struct {
+	QueueStore
+	t  *testing.T
+	qm *QueueManager
+}
+
+func (s *lockCheckingStore) checkLock(queueKey string) {
+	if !s.qm.lock.TryLock() {
+		s.t.Errorf("the state of queue %s is written with the queue manager lock held", queueKey)
+		return
+	}
+	s.qm.lock.Unlock()
+}
+
+func (s *lockCheckingStore) Save(ctx context.Context, queueKey string, state *QueueState) error {
+	s.checkLock(queueKey)
+	return s.QueueStore.Save(ctx, queueKey, state)
+}
+
+func (s *lockCheckingStore) Delete(ctx context.Context, queueKey string) error {
+	s.checkLock(queueKey)
+	return s.QueueStore.Delete(ctx, queueKey)
+}
+
+func TestQueueManager_PersistOutsideLock(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	cmStore := NewConfigMapQueueStore(stdata.Kube, "pac")
+	store := &lockCheckingStore{QueueStore: cmStore, t: t}
+	qm := NewPersistentQueueManager(logger, store, nil, nil)
+	store.qm = qm
+
+	repo := newTestRepo(1)
+	pr := newTestPR("first", cw.Now(), nil, nil, tektonv1.PipelineRunSpec{})
+	started, err := qm.AddListToRunningQueue(repo, Group{}, []string{PrKey(pr)})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{PrKey(pr)})
+	state, err := cmStore.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 1)
+
+	// the state of a queue becoming empty is deleted
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, pr), "")
+	state, err = cmStore.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+	assert.Equal(t, len(qm.writes), 0)
+	assert.Equal(t, len(qm.writing), 0)
+}
+
+func TestQueueManager_FlushLatestState(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	store := NewConfigMapQueueStore(stdata.Kube, "pac")
+	qm := NewPersistentQueueManager(logger, store, nil, nil)
+	repo := newTestRepo(1)
+	queueKey := RepoKey(repo)
+
+	// another goroutine is writing the queue, its state is left to it
+	qm.writing[queueKey] = true
+	qm.writes[queueKey] = &QueueState{Running: []QueueItem{{Key: "test-ns/old"}}, Pending: []QueueItem{}}
+	qm.writes[queueKey] = &QueueState{Running: []QueueItem{{Key: "test-ns/new"}}, Pending: []QueueItem{}}
+	qm.flush()
+	state, err := store.Load(ctx, queueKey)
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+
+	// only the latest recorded state is written
+	delete(qm.writing, queueKey)
+	qm.flush()
+	state, err = store.Load(ctx, queueKey)
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 1)
+	assert.Equal(t, state.Running[0].Key, "test-ns/new")
+}
+
+func TestQueueManager_InitQueuesFromStore(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	cw := clockwork.NewFakeClock()
+
+	startedLabel := map[string]string{keys.State: kubeinteraction.StateStarted}
+	queuedLabel := map[string]string{keys.State: kubeinteraction.StateQueued}
+	pendingSpec := tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending}
+
+	repo := newTestRepo(1)
+	// the creation order is the opposite of the queue order, the persisted
+	// state must win
+	firstPR := newTestPR("first", cw.Now().Add(5*time.Second), startedLabel, nil, tektonv1.PipelineRunSpec{})
+	secondPR := newTestPR("second", cw.Now().Add(4*time.Second), queuedLabel, nil, pendingSpec)
+	thirdPR := newTestPR("third", cw.Now().Add(3*time.Second), queuedLabel, nil, pendingSpec)
+	// moved to running before the restart but not started yet
+	fourthPR := newTestPR("fourth", cw.Now().Add(2*time.Second), queuedLabel, nil, pendingSpec)
+	// queued while the state couldn't be persisted
+	fifthPR := newTestPR("fifth", cw.Now().Add(8*time.Second), queuedLabel, nil, pendingSpec)
+
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*tektonv1.PipelineRun{firstPR, secondPR, thirdPR, fourthPR, fifthPR},
+	})
+	store := NewConfigMapQueueStore(stdata.Kube, "pac")
+	enqueued := func(d time.Duration) QueueItem {
+		return QueueItem{Priority: cw.Now().Add(d).UnixNano(), Enqueued: cw.Now().Add(d)}
+	}
+	withKey := func(it QueueItem, key string) QueueItem {
+		it.Key = key
+		return it
+	}
+	assert.NilError(t, store.Save(ctx, RepoKey(repo), &QueueState{
+		Running: []QueueItem{
+			withKey(enqueued(10*time.Second), PrKey(firstPR)),
+			withKey(enqueued(11*time.Second), PrKey(fourthPR)),
+		},
+		Pending: []QueueItem{
+			withKey(enqueued(12*time.Second), PrKey(secondPR)),
+			withKey(enqueued(13*time.Second), PrKey(thirdPR)),
+			// deleted while the watcher was down
+			withKey(enqueued(9*time.Second), "test-ns/gone"),
+		},
+	}))
+
+	qm := NewPersistentQueueManager(logger, store, nil, nil)
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{PrKey(firstPR)})
+	// restoring doesn't write anything, the state of another replica is kept
+	state, err := store.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.Equal(t, len(state.Running), 2)
+
+	// the pipelineRuns are started in the persisted order, then by creation
+	// time for the ones missing from it
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, firstPR), PrKey(fourthPR))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, fourthPR), PrKey(secondPR))
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, secondPR), PrKey(thirdPR))
+
+	state, err = store.Load(ctx, RepoKey(repo))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, state, &QueueState{
+		Running: []QueueItem{withKey(enqueued(13*time.Second), PrKey(thirdPR))},
+		Pending: []QueueItem{{Key: PrKey(fifthPR), Priority: cw.Now().Add(13*time.Second).UnixNano() + 1, Enqueued: cw.Now().Add(8 * time.Second)}},
+	})
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, thirdPR), PrKey(fifthPR))
+}
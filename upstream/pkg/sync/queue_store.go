@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	queueStateKey      = "state"
+	queueRepositoryKey = "repository"
+	queueStoreLabel    = pipelinesascode.GroupName + "/queue"
+)
+
+// QueueItem is a PipelineRun of the queue of a Repository as persisted in the
+// QueueStore, the priority decides the order in which the pending ones are
+// started.
+type QueueItem struct {
+	Key      string    `json:"key"`
+	Priority int64     `json:"priority"`
+	Enqueued time.Time `json:"enqueued"`
+}
+
+// QueueState is the state of the queue of a Repository, the items are
+// ordered by priority.
+type QueueState struct {
+	Running []QueueItem `json:"running"`
+	Pending []QueueItem `json:"pending"`
+}
+
+// QueueStore persists the state of the queues so the order of the
+// PipelineRuns survives a restart of the watcher. The queues are identified
+// by their QueueKey.
+type QueueStore interface {
+	// Load returns the persisted state of the queue of a Repository or nil
+	// if nothing has been persisted yet.
+	Load(ctx context.Context, queueKey string) (*QueueState, error)
+	Save(ctx context.Context, queueKey string, state *QueueState) error
+	Delete(ctx context.Context, queueKey string) error
+}
+
+// ConfigMapQueueStore stores the state of the queue of every Repository in a
+// ConfigMap of the namespace where Pipelines-as-Code is installed.
+type ConfigMapQueueStore struct {
+	kube      kubernetes.Interface
+	namespace string
+}
+
+var _ QueueStore = &ConfigMapQueueStore{}
+
+func NewConfigMapQueueStore(kube kubernetes.Interface, namespace string) *ConfigMapQueueStore {
+	return &ConfigMapQueueStore{
+		kube:      kube,
+		namespace: namespace,
+	}
+}
+
+// queueConfigMapName returns the name of the ConfigMap for a queue key, the
+// namespace and the name are joined with a dot since namespaces can't have
+// one. The name of a concurrency group can have any character so it is added
+// as a hash, the name is hashed as well if it gets too long.
+func queueConfigMapName(queueKey string) string {
+	repoKey, group := splitQueueKey(queueKey)
+	name := "pac-queue-" + strings.Replace(repoKey, "/", ".", 1)
+	if group != "" {
+		name += fmt.Sprintf(".%x", sha256.Sum256([]byte(group)))[:11]
+	}
+	return kmeta.ChildName(name, "")
+}
+
+func (s *ConfigMapQueueStore) Load(ctx context.Context, queueKey string) (*QueueState, error) {
+	cm, err := s.kube.CoreV1().ConfigMaps(s.namespace).Get(ctx, queueConfigMapName(queueKey), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[queueStateKey]
+	if !ok || cm.Data[queueRepositoryKey] != queueKey {
+		return nil, nil
+	}
+	state := &QueueState{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return nil, fmt.Errorf("cannot parse the state of queue %s: %w", queueKey, err)
+	}
+	return state, nil
+}
+
+func (s *ConfigMapQueueStore) Save(ctx context.Context, queueKey string, state *QueueState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	name := queueConfigMapName(queueKey)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.kube.CoreV1().ConfigMaps(s.namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: s.namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": pipelinesascode.GroupName,
+						queueStoreLabel:                "true",
+					},
+				},
+				Data: map[string]string{
+					queueRepositoryKey: queueKey,
+					queueStateKey:      string(data),
+				},
+			}
+			_, err = s.kube.CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[queueRepositoryKey] = queueKey
+		cm.Data[queueStateKey] = string(data)
+		_, err = s.kube.CoreV1().ConfigMaps(s.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *ConfigMapQueueStore) Delete(ctx context.Context, queueKey string) error {
+	err := s.kube.CoreV1().ConfigMaps(s.namespace).Delete(ctx, queueConfigMapName(queueKey), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func TestQueueConfigMapName(t *testing.T) {
+	assert.Equal(t, queueConfigMapName("test-ns/test"), "pac-queue-test-ns.test")
+	// namespace and name are not mixed up
+	assert.Assert(t, queueConfigMapName("a-b/c") != queueConfigMapName("a/b-c"))
+	// long names are hashed to fit in a ConfigMap name
+	long := queueConfigMapName("test-ns/" + strings.Repeat("a", 80))
+	assert.Assert(t, len(long) <= 63, long)
+	// the concurrency groups are hashed since they can have any character
+	group := queueConfigMapName("test-ns/test/deploy/release-1.0")
+	assert.Assert(t, strings.HasPrefix(group, "pac-queue-test-ns.test."), group)
+	assert.Assert(t, group != queueConfigMapName("test-ns/test/deploy/release-2.0"))
+}
+
+func TestConfigMapQueueStore(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{})
+	store := NewConfigMapQueueStore(stdata.Kube, "pac")
+	repoKey := "test-ns/test"
+
+	// nothing persisted yet
+	state, err := store.Load(ctx, repoKey)
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+
+	enqueued := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := &QueueState{
+		Running: []QueueItem{{Key: "test-ns/first", Priority: 1, Enqueued: enqueued}},
+		Pending: []QueueItem{{Key: "test-ns/second", Priority: 2, Enqueued: enqueued.Add(time.Second)}},
+	}
+	assert.NilError(t, store.Save(ctx, repoKey, want))
+	state, err = store.Load(ctx, repoKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, state, want)
+
+	// saving again updates the same ConfigMap
+	want.Running, want.Pending = want.Pending, []QueueItem{}
+	assert.NilError(t, store.Save(ctx, repoKey, want))
+	state, err = store.Load(ctx, repoKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, state, want)
+
+	cm, err := stdata.Kube.CoreV1().ConfigMaps("pac").Get(ctx, queueConfigMapName(repoKey), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, cm.Data[queueRepositoryKey], repoKey)
+	assert.Equal(t, cm.GetLabels()[queueStoreLabel], "true")
+
+	// another repository doesn't see it
+	state, err = store.Load(ctx, "test-ns/other")
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+
+	assert.NilError(t, store.Delete(ctx, repoKey))
+	state, err = store.Load(ctx, repoKey)
+	assert.NilError(t, err)
+	assert.Assert(t, state == nil)
+	// deleting twice is fine
+	assert.NilError(t, store.Delete(ctx, repoKey))
+}
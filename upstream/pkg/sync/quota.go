@@ -0,0 +1,119 @@
+package sync
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+)
+
+// unlimitedQueueLimit is the size of the semaphore of a queue without limit
+// when its PipelineRuns are only throttled by the quotas.
+const unlimitedQueueLimit = math.MaxInt32
+
+// QuotaFunc returns the cluster-wide and per-namespace concurrency quotas,
+// they are read on every change of the queues so an update of the
+// configuration is taken into account right away.
+type QuotaFunc func() settings.ConcurrencyQuotas
+
+// concurrencyQuotas returns the current quotas, none when the queue manager
+// has no quota function.
+func (qm *QueueManager) concurrencyQuotas() settings.ConcurrencyQuotas {
+	if qm.quotas == nil {
+		return settings.ConcurrencyQuotas{}
+	}
+	return qm.quotas()
+}
+
+// queueNamespace returns the namespace of the repository of a queue.
+func queueNamespace(queueKey string) string {
+	namespace, _, _ := strings.Cut(queueKey, "/")
+	return namespace
+}
+
+// runningCounts returns the number of running PipelineRuns of all the queues,
+// per namespace and per repository, the queues of the concurrency groups are
+// counted with their repository. The queue manager lock must be held.
+func (qm *QueueManager) runningCounts() (int, map[string]int, map[string]int) {
+	total := 0
+	namespaces, repositories := map[string]int{}, map[string]int{}
+	for queueKey, sema := range qm.queueMap {
+		running := len(sema.getCurrentRunning())
+		repoKey, _ := splitQueueKey(queueKey)
+		total += running
+		namespaces[queueNamespace(queueKey)] += running
+		repositories[repoKey] += running
+	}
+	return total, namespaces, repositories
+}
+
+// quotaAvailable checks if one more PipelineRun of the namespace can run
+// without exceeding the quotas. The queue manager lock must be held.
+func (qm *QueueManager) quotaAvailable(quotas settings.ConcurrencyQuotas, namespace string) bool {
+	if !quotas.Enabled(namespace) {
+		return true
+	}
+	total, namespaces, _ := qm.runningCounts()
+	if quotas.Global > 0 && total >= quotas.Global {
+		return false
+	}
+	if limit := quotas.NamespaceLimit(namespace); limit > 0 && namespaces[namespace] >= limit {
+		return false
+	}
+	return true
+}
+
+// takeFairShare starts the next PipelineRun once a running one of the
+// namespace has been released while quotas are set. The free slot is shared
+// by all the queues with the global quota, only by the ones of the namespace
+// otherwise. It goes to the queue of the repository with the fewest running
+// PipelineRuns, so a repository with a lot of queued PipelineRuns can't take
+// the whole quota, the ties are broken by the priority of the next PipelineRun
+// of the queues. It returns the key of the queue and of the started
+// PipelineRun. The queue manager lock must be held.
+func (qm *QueueManager) takeFairShare(quotas settings.ConcurrencyQuotas, namespace string) (string, string) {
+	total, namespaces, repositories := qm.runningCounts()
+	if quotas.Global > 0 && total >= quotas.Global {
+		return "", ""
+	}
+
+	type candidate struct {
+		queueKey string
+		running  int
+		next     QueueItem
+	}
+	candidates := []candidate{}
+	for queueKey, sema := range qm.queueMap {
+		queueNS := queueNamespace(queueKey)
+		if quotas.Global == 0 && queueNS != namespace {
+			continue
+		}
+		if limit := quotas.NamespaceLimit(queueNS); limit > 0 && namespaces[queueNS] >= limit {
+			continue
+		}
+		state := sema.getState()
+		if len(state.Pending) == 0 {
+			continue
+		}
+		repoKey, _ := splitQueueKey(queueKey)
+		candidates = append(candidates, candidate{queueKey: queueKey, running: repositories[repoKey], next: state.Pending[0]})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].running != candidates[j].running {
+			return candidates[i].running < candidates[j].running
+		}
+		if candidates[i].next.Priority != candidates[j].next.Priority {
+			return candidates[i].next.Priority < candidates[j].next.Priority
+		}
+		return candidates[i].queueKey < candidates[j].queueKey
+	})
+
+	for _, c := range candidates {
+		if next := qm.queueMap[c.queueKey].acquireLatest(); next != "" {
+			qm.logger.Infof("moved (%s) to running for repository (%s) with its fair share of the quotas", next, c.queueKey)
+			return c.queueKey, next
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/keys"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/params/settings"
+	testclient "github.com/openshift-pipelines/pipelines-as-code/pkg/test/clients"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"go.uber.org/zap"
+	zapobserver "go.uber.org/zap/zaptest/observer"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtesting "knative.dev/pkg/reconciler/testing"
+)
+
+func newQuotaTestRepo(namespace, name string) *v1alpha1.Repository {
+	return &v1alpha1.Repository{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func newQuotaTestPR(repo *v1alpha1.Repository, name string) *tektonv1.PipelineRun {
+	return &tektonv1.PipelineRun{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   repo.GetNamespace(),
+		Annotations: map[string]string{keys.Repository: repo.GetName()},
+	}}
+}
+
+func newQuotaQueueManager(quotas settings.ConcurrencyQuotas) *QueueManager {
+	observer, _ := zapobserver.New(zap.InfoLevel)
+	logger := zap.New(observer).Sugar()
+	return NewPersistentQueueManager(logger, nil, nil, func() settings.ConcurrencyQuotas { return quotas })
+}
+
+func TestQueueManager_GlobalQuota(t *testing.T) {
+	qm := newQuotaQueueManager(settings.ConcurrencyQuotas{Global: 2})
+	noisy := newQuotaTestRepo("team-a", "noisy")
+	quiet := newQuotaTestRepo("team-b", "quiet")
+
+	started, err := qm.AddListToRunningQueue(noisy, Group{}, []string{"team-a/noisy-1", "team-a/noisy-2", "team-a/noisy-3"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"team-a/noisy-1", "team-a/noisy-2"})
+	// the global quota is used up by another namespace
+	started, err = qm.AddListToRunningQueue(quiet, Group{}, []string{"team-b/quiet-1"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{})
+
+	// the free slot goes to the repository with the fewest running PipelineRuns
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(noisy, newQuotaTestPR(noisy, "noisy-1")), "team-b/quiet-1")
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(noisy, newQuotaTestPR(noisy, "noisy-2")), "team-a/noisy-3")
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(quiet, newQuotaTestPR(quiet, "quiet-1")), "")
+}
+
+func TestQueueManager_NamespaceQuota(t *testing.T) {
+	qm := newQuotaQueueManager(settings.ConcurrencyQuotas{Namespaces: map[string]int{"team-a": 2}})
+	first := newQuotaTestRepo("team-a", "first")
+	second := newQuotaTestRepo("team-a", "second")
+	other := newQuotaTestRepo("team-b", "other")
+
+	started, err := qm.AddListToRunningQueue(first, Group{}, []string{"team-a/first-1", "team-a/first-2", "team-a/first-3"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"team-a/first-1", "team-a/first-2"})
+	started, err = qm.AddListToRunningQueue(second, Group{}, []string{"team-a/second-1"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{})
+	// a namespace without quota isn't throttled
+	started, err = qm.AddListToRunningQueue(other, Group{}, []string{"team-b/other-1", "team-b/other-2"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"team-b/other-1", "team-b/other-2"})
+
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(first, newQuotaTestPR(first, "first-1")), "team-a/second-1")
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(second, newQuotaTestPR(second, "second-1")), "team-a/first-3")
+}
+
+func TestQueueManager_QuotaWithRepositoryLimit(t *testing.T) {
+	qm := newQuotaQueueManager(settings.ConcurrencyQuotas{Global: 5})
+	limited := newQuotaTestRepo("team-a", "limited")
+	limited.Spec.ConcurrencyLimit = intPtr(1)
+	free := newQuotaTestRepo("team-a", "free")
+
+	// the limit of the repository still applies under the quota
+	started, err := qm.AddListToRunningQueue(limited, Group{}, []string{"team-a/limited-1", "team-a/limited-2"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"team-a/limited-1"})
+	started, err = qm.AddListToRunningQueue(free, Group{}, []string{"team-a/free-1"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, started, []string{"team-a/free-1"})
+
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(free, newQuotaTestPR(free, "free-1")), "")
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(limited, newQuotaTestPR(limited, "limited-1")), "team-a/limited-2")
+}
+
+func TestQueueManager_InitQueuesWithQuotas(t *testing.T) {
+	ctx, _ := rtesting.SetupFakeContext(t)
+	cw := clockwork.NewFakeClock()
+
+	repo := newTestRepo(1)
+	repo.Spec.ConcurrencyLimit = nil
+	firstPR := newTestPR("first", cw.Now(), map[string]string{keys.State: kubeinteraction.StateStarted},
+		map[string]string{keys.State: kubeinteraction.StateStarted}, tektonv1.PipelineRunSpec{})
+	secondPR := newTestPR("second", cw.Now().Add(time.Second), map[string]string{keys.State: kubeinteraction.StateQueued},
+		map[string]string{keys.State: kubeinteraction.StateQueued}, tektonv1.PipelineRunSpec{Status: tektonv1.PipelineRunSpecStatusPending})
+
+	stdata, _ := testclient.SeedTestData(t, ctx, testclient.Data{
+		Repositories: []*v1alpha1.Repository{repo},
+		PipelineRuns: []*tektonv1.PipelineRun{firstPR, secondPR},
+	})
+	qm := newQuotaQueueManager(settings.ConcurrencyQuotas{Namespace: 1})
+	assert.NilError(t, qm.InitQueues(ctx, stdata.Pipeline, stdata.PipelineAsCode))
+
+	// the repository has no limit but its namespace has a quota, the queue is
+	// rebuilt without execution order
+	assert.DeepEqual(t, qm.RunningPipelineRuns(repo), []string{PrKey(firstPR)})
+	assert.DeepEqual(t, qm.QueuedPipelineRuns(repo), []string{PrKey(secondPR)})
+	assert.Equal(t, qm.RemoveAndTakeItemFromQueue(repo, firstPR), PrKey(secondPR))
+}
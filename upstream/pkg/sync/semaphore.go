@@ -2,6 +2,7 @@ package sync
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,7 +13,7 @@ type prioritySemaphore struct {
 	name      string
 	limit     int
 	pending   *priorityQueue
-	running   map[string]bool
+	running   map[string]*item
 	semaphore *sema.Weighted
 	lock      *sync.Mutex
 }
@@ -25,7 +26,7 @@ func newSemaphore(name string, limit int) *prioritySemaphore {
 		limit:     limit,
 		pending:   &priorityQueue{itemByKey: make(map[string]*item)},
 		semaphore: sema.NewWeighted(int64(limit)),
-		running:   make(map[string]bool),
+		running:   make(map[string]*item),
 		lock:      &sync.Mutex{},
 	}
 }
@@ -81,17 +82,7 @@ func (s *prioritySemaphore) removeFromQueue(key string) {
 }
 
 func (s *prioritySemaphore) addToPendingQueue(key string, creationTime time.Time) bool {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-
-	if _, ok := s.running[key]; ok {
-		return false
-	}
-	if s.pending.isPending(key) {
-		return false
-	}
-	s.pending.add(key, creationTime.UnixNano())
-	return true
+	return s.enqueue(key, creationTime, creationTime.UnixNano())
 }
 
 func (s *prioritySemaphore) acquireLatest() string {
@@ -106,7 +97,7 @@ func (s *prioritySemaphore) acquireLatest() string {
 
 	if s.semaphore.TryAcquire(1) {
 		_ = s.pending.pop()
-		s.running[ready.key] = true
+		s.running[ready.key] = ready
 		return ready.key
 	}
 	return ""
@@ -131,6 +122,12 @@ func (s *prioritySemaphore) release(key string) bool {
 }
 
 func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool {
+	return s.enqueue(key, creationTime, creationTime.UnixNano())
+}
+
+// enqueue adds the key to the pending queue unless it is already queued or
+// running, the lowest priority is started first.
+func (s *prioritySemaphore) enqueue(key string, enqueued time.Time, priority int64) bool {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -140,7 +137,7 @@ func (s *prioritySemaphore) addToQueue(key string, creationTime time.Time) bool
 	if s.pending.isPending(key) {
 		return false
 	}
-	s.pending.add(key, creationTime.UnixNano())
+	s.pending.addItem(&item{key: key, priority: priority, enqueued: enqueued})
 	return true
 }
 
@@ -167,8 +164,11 @@ func (s *prioritySemaphore) tryAcquire(key string) (bool, string) {
 	}
 
 	if s.semaphore.TryAcquire(1) {
-		s.running[key] = true
-		s.pending.pop()
+		now := time.Now()
+		s.running[key] = &item{key: key, priority: now.UnixNano(), enqueued: now}
+		if s.pending.Len() > 0 {
+			s.running[key] = s.pending.pop()
+		}
 		return true, ""
 	}
 
@@ -180,8 +180,82 @@ func (s *prioritySemaphore) acquire(key string) bool {
 	defer s.lock.Unlock()
 
 	if s.semaphore.TryAcquire(1) {
-		s.running[key] = true
+		now := time.Now()
+		s.running[key] = &item{key: key, priority: now.UnixNano(), enqueued: now}
 		return true
 	}
 	return false
 }
+
+// getState returns the running and pending items ordered by priority, the
+// way they are persisted in the QueueStore.
+func (s *prioritySemaphore) getState() *QueueState {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	state := &QueueState{Running: []QueueItem{}, Pending: []QueueItem{}}
+	for _, it := range s.running {
+		state.Running = append(state.Running, newQueueItem(it))
+	}
+	for _, it := range s.pending.items {
+		state.Pending = append(state.Pending, newQueueItem(it))
+	}
+	sortQueueItems(state.Running)
+	sortQueueItems(state.Pending)
+	return state
+}
+
+// restore adds back the items of a persisted state, the running ones are
+// kept running even if the limit has been lowered in between, they release
+// their slot once done like after a resize.
+func (s *prioritySemaphore) restore(running, pending []QueueItem) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, it := range running {
+		if _, ok := s.running[it.Key]; ok {
+			continue
+		}
+		s.pending.remove(it.Key)
+		_ = s.semaphore.TryAcquire(1)
+		s.running[it.Key] = &item{key: it.Key, priority: it.Priority, enqueued: it.Enqueued}
+	}
+	for _, it := range pending {
+		if _, ok := s.running[it.Key]; ok {
+			continue
+		}
+		s.pending.addItem(&item{key: it.Key, priority: it.Priority, enqueued: it.Enqueued})
+	}
+}
+
+// promote moves a pending key to the front of the queue, it keeps the time it
+// has been queued.
+func (s *prioritySemaphore) promote(key string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	it, ok := s.pending.itemByKey[key]
+	if !ok {
+		return false
+	}
+	first := s.pending.peek()
+	if first.key == key {
+		return true
+	}
+	s.pending.remove(key)
+	s.pending.addItem(&item{key: key, priority: first.priority - 1, enqueued: it.enqueued})
+	return true
+}
+
+func newQueueItem(it *item) QueueItem {
+	return QueueItem{Key: it.key, Priority: it.priority, Enqueued: it.enqueued}
+}
+
+func sortQueueItems(items []QueueItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Priority == items[j].Priority {
+			return items[i].Key < items[j].Key
+		}
+		return items[i].Priority < items[j].Priority
+	})
+}
@@ -285,3 +285,48 @@ func TestTryAcquireConcurrentAccess(t *testing.T) {
 	assert.Equal(t, acquired3, false)
 	assert.Equal(t, len(repo.getCurrentRunning()), 2)
 }
+
+func TestSemaphoreStateAndRestore(t *testing.T) {
+	cw := clockwork.NewFakeClock()
+	sema := newSemaphore("test", 1)
+
+	assert.Equal(t, sema.addToQueue("C", cw.Now().Add(5*time.Second)), true)
+	assert.Equal(t, sema.addToQueue("A", cw.Now()), true)
+	assert.Equal(t, sema.addToQueue("B", cw.Now().Add(1*time.Second)), true)
+	assert.Equal(t, sema.acquireLatest(), "A")
+
+	state := sema.getState()
+	assert.DeepEqual(t, state, &QueueState{
+		Running: []QueueItem{{Key: "A", Priority: cw.Now().UnixNano(), Enqueued: cw.Now()}},
+		Pending: []QueueItem{
+			{Key: "B", Priority: cw.Now().Add(1 * time.Second).UnixNano(), Enqueued: cw.Now().Add(1 * time.Second)},
+			{Key: "C", Priority: cw.Now().Add(5 * time.Second).UnixNano(), Enqueued: cw.Now().Add(5 * time.Second)},
+		},
+	})
+
+	// a new semaphore gets the exact same queue back
+	restored := newSemaphore("test", 1)
+	restored.restore(state.Running, state.Pending)
+	assert.DeepEqual(t, restored.getState(), state)
+
+	// the slot of the running one is taken
+	assert.Equal(t, restored.acquireLatest(), "")
+	assert.Equal(t, restored.release("A"), true)
+	assert.Equal(t, restored.acquireLatest(), "B")
+	assert.Equal(t, restored.acquireLatest(), "")
+}
+
+func TestSemaphoreRestoreOverLimit(t *testing.T) {
+	sema := newSemaphore("test", 1)
+	sema.restore([]QueueItem{{Key: "A", Priority: 1}, {Key: "B", Priority: 2}}, []QueueItem{{Key: "A", Priority: 3}, {Key: "C", Priority: 4}})
+
+	// running ones are kept even if over the limit and not added as pending
+	assert.Equal(t, len(sema.getCurrentRunning()), 2)
+	assert.DeepEqual(t, sema.getCurrentPending(), []string{"C"})
+
+	// the slot is only released once both are done
+	assert.Equal(t, sema.release("A"), true)
+	assert.Equal(t, sema.acquireLatest(), "")
+	assert.Equal(t, sema.release("B"), true)
+	assert.Equal(t, sema.acquireLatest(), "C")
+}
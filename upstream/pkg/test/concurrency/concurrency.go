@@ -5,6 +5,7 @@ import (
 
 	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	pacVersionedClient "github.com/openshift-pipelines/pipelines-as-code/pkg/generated/clientset/versioned"
+	"github.com/openshift-pipelines/pipelines-as-code/pkg/sync"
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	tektonVersionedClient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 )
@@ -31,11 +32,11 @@ func (TestQMI) RunningPipelineRuns(_ *pacv1alpha1.Repository) []string {
 	panic("implement me")
 }
 
-func (t TestQMI) AddListToRunningQueue(_ *pacv1alpha1.Repository, _ []string) ([]string, error) {
+func (t TestQMI) AddListToRunningQueue(_ *pacv1alpha1.Repository, _ sync.Group, _ []string) ([]string, error) {
 	return t.RunningQueue, nil
 }
 
-func (TestQMI) AddToPendingQueue(_ *pacv1alpha1.Repository, _ []string) error {
+func (TestQMI) AddToPendingQueue(_ *pacv1alpha1.Repository, _ sync.Group, _ []string) error {
 	// TODO implement me
 	panic("implement me")
 }
@@ -44,6 +45,14 @@ func (t TestQMI) RemoveFromQueue(_, _ string) bool {
 	return false
 }
 
+func (t TestQMI) QueueStatus(_ string) (int, []string) {
+	return len(t.RunningQueue), t.QueuedPrs
+}
+
+func (TestQMI) PromoteInQueue(_, _ string) bool {
+	return false
+}
+
 func (TestQMI) RemoveAndTakeItemFromQueue(_ *pacv1alpha1.Repository, _ *tektonv1.PipelineRun) string {
 	// TODO implement me
 	panic("implement me")
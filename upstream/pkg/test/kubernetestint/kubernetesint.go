@@ -3,6 +3,7 @@ package kubernetestint
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/kubeinteraction"
@@ -18,6 +19,8 @@ type KinterfaceTest struct {
 	ExpectedNumberofCleanups int
 	GetSecretResult          map[string]string
 	GetPodLogsOutput         map[string]string
+	// RetentionPolicies are the retention policies applied by Repository.
+	RetentionPolicies map[string]kubeinteraction.RetentionPolicy
 }
 
 var _ kubeinteraction.Interface = (*KinterfaceTest)(nil)
@@ -56,6 +59,16 @@ func (k *KinterfaceTest) CleanupPipelines(_ context.Context, _ *zap.SugaredLogge
 	return nil
 }
 
+func (k *KinterfaceTest) ApplyRetention(_ context.Context, _ *zap.SugaredLogger, repo *v1alpha1.Repository,
+	policy kubeinteraction.RetentionPolicy, _ time.Time,
+) (int, error) {
+	if k.RetentionPolicies == nil {
+		k.RetentionPolicies = map[string]kubeinteraction.RetentionPolicy{}
+	}
+	k.RetentionPolicies[repo.GetNamespace()+"/"+repo.GetName()] = policy
+	return 0, nil
+}
+
 func (k *KinterfaceTest) CreateSecret(_ context.Context, _ string, _ *corev1.Secret) error {
 	return nil
 }
@@ -30,7 +30,11 @@ type TestProviderImp struct {
 	WantDeletedFiles       []string
 	WantModifiedFiles      []string
 	WantRenamedFiles       []string
-	pacInfo                *info.PacOpts
+	// Comments and CommentMarkers record the body and the update marker of
+	// every comment created.
+	Comments       []string
+	CommentMarkers []string
+	pacInfo        *info.PacOpts
 }
 
 func (v *TestProviderImp) SetPacInfo(pacInfo *info.PacOpts) {
@@ -48,7 +52,9 @@ func (v *TestProviderImp) IsAllowedOwnersFile(_ context.Context, _ *info.Event)
 	return v.AllowedInOwnersFile, nil
 }
 
-func (v *TestProviderImp) CreateComment(_ context.Context, _ *info.Event, _, _ string) error {
+func (v *TestProviderImp) CreateComment(_ context.Context, _ *info.Event, comment, updateMarker string) error {
+	v.Comments = append(v.Comments, comment)
+	v.CommentMarkers = append(v.CommentMarkers, updateMarker)
 	return nil
 }
 